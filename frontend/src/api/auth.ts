@@ -13,22 +13,116 @@ export interface User {
   email: string    // 邮箱
   phone: string    // 手机号
   avatar: string   // 头像 URL
-  role: string     // 角色 (admin/user)
+  role: string     // 角色编码 (admin: 管理员, user: 普通用户, 或自定义角色)
   department: string // 部门
+  department_id: number // 所属部门 ID (0 表示未分配)
   position: string   // 职位
+  position_id: number // 职位 ID (0 表示未分配)
   status: number     // 状态 (1:正常, 0:禁用)
+  two_factor_enabled: boolean // 是否已启用两步验证
+  auth_source: string         // 认证来源 (local: 本地密码, ldap: 目录账户, oidc: 单点登录账户)
+}
+
+// 当前登录用户 (含权限)
+export interface CurrentUser extends User {
+  permissions: string[] // 当前角色拥有的权限编码
 }
 
 // 登录请求参数
 export interface LoginRequest {
   username: string
   password: string
+  device_name?: string // 设备名称 (为空时由服务端根据 User-Agent 推断)
+}
+
+// 登录会话
+export interface UserSession {
+  id: number
+  user_id: number
+  device_name: string    // 设备名称
+  client_ip: string      // 客户端 IP
+  user_agent: string     // User-Agent
+  last_seen_time: string // 最近活跃时间
+  create_time: string    // 登录时间
+  current: boolean       // 是否为当前会话
 }
 
 // 登录响应数据
+// 已启用两步验证时只返回 two_factor_required 与 challenge_token，需调用 verifyTwoFactor 完成登录
 export interface LoginResponse {
-  token: string // JWT Token
-  user: User    // 用户信息
+  token: string         // 访问令牌 (JWT)
+  refresh_token: string // 刷新令牌
+  expires_in: number    // 访问令牌 (或挑战令牌) 有效期 (秒)
+  user: User            // 用户信息
+  two_factor_required?: boolean       // 需要提交两步验证码
+  challenge_token?: string            // 两步验证挑战令牌
+  two_factor_setup_required?: boolean // 需先启用两步验证
+  password_change_required?: boolean  // 密码已过期，需先修改密码
+  permissions?: string[]              // 当前角色拥有的权限编码
+}
+
+// 单点登录配置
+export interface OIDCInfo {
+  enabled: boolean     // 是否已启用 OIDC 单点登录
+  display_name: string // 按钮上显示的身份提供方名称
+}
+
+// 两步验证状态
+export interface TwoFactorStatus {
+  enabled: boolean                  // 是否已启用
+  required: boolean                 // 当前角色是否被要求启用
+  recovery_codes_remaining: number  // 剩余恢复码数量
+}
+
+// 两步验证绑定信息
+export interface TwoFactorSetup {
+  secret: string      // Base32 密钥
+  otpauth_uri: string // otpauth URI
+}
+
+// 密码策略
+export interface PasswordPolicy {
+  min_length: number      // 最小长度
+  require_upper: boolean  // 必须包含大写字母
+  require_lower: boolean  // 必须包含小写字母
+  require_digit: boolean  // 必须包含数字
+  require_symbol: boolean // 必须包含特殊字符
+  ban_common: boolean     // 禁止使用常见弱密码
+  history_count: number   // 不能与最近 N 个密码相同 (0 表示不限制)
+  max_age_days: number    // 密码有效天数 (0 表示不过期)
+}
+
+// 密码不符合策略时 data.violations 中的规则
+export interface PasswordViolation {
+  rule: string    // 规则标识 (min_length/upper/lower/digit/symbol/common/reused)
+  message: string // 提示信息
+}
+
+// 安全设置 (管理员)
+export interface SecuritySettings {
+  require_admin_2fa: boolean       // 管理员必须启用两步验证
+  password_policy: PasswordPolicy  // 密码策略
+}
+
+// 安全审计日志
+export interface AuditLog {
+  id: number
+  user_id: number     // 相关用户ID (账户不存在时为 0)
+  username: string    // 登录时提交的用户名
+  action: string      // 事件类型 (login_failed/login_blocked/account_locked/ip_locked/account_unlocked/two_factor_failed/oidc_failed/signing_key_rotated/password_reset_sent/password_reset)
+  client_ip: string   // 客户端 IP
+  user_agent: string  // User-Agent
+  detail: string      // 详情
+  operator_id: number // 操作人ID (管理员操作时)
+  create_time: string // 发生时间
+}
+
+// 审计日志查询参数
+export interface AuditLogParams {
+  page: number
+  page_size: number
+  action?: string
+  user_id?: number
 }
 
 // 注册请求参数
@@ -46,16 +140,45 @@ export interface ChangePasswordRequest {
   new_password: string
 }
 
-// 更新个人信息请求参数
+// 更新个人信息请求参数 (部门与职位由管理员分配)
 export interface UpdateProfileRequest {
   name?: string
   email?: string
   phone?: string
-  department?: string
-  position?: string
 }
 
-// 认证 API 集合
+// 访问令牌签名密钥
+export interface SigningKey {
+  kid: string         // 密钥ID (令牌 header 中的 kid)
+  algorithm: string   // 签名算法 (RS256/EdDSA)
+  create_time: string // 创建时间
+  active: boolean     // 是否为当前签名密钥 (其余仅用于校验轮换前签发的令牌)
+}
+
+// 个人访问令牌 (用于脚本与系统集成)
+export interface PersonalToken {
+  id: number
+  name: string
+  token_prefix: string          // 令牌前几位 (用于辨认)
+  scopes: string[]              // 授权范围
+  expire_time: string | null    // 过期时间 (null 表示永不过期)
+  last_used_time: string | null // 最近使用时间
+  last_used_ip: string          // 最近使用 IP
+  create_time: string
+  expired: boolean
+}
+
+// 创建个人访问令牌的结果 (token 明文仅返回一次)
+export interface PersonalTokenCreated extends PersonalToken {
+  token: string
+}
+
+// 访问令牌授权范围
+export interface TokenScope {
+  scope: string
+  description: string
+}
+
 // 列表请求参数
 export interface UserListParams {
   page: number
@@ -77,7 +200,9 @@ export interface CreateUserRequest {
   email?: string
   phone?: string
   password: string
-  role: 'admin' | 'user'
+  role?: string // 角色编码，为空表示普通用户
+  department_id?: number // 所属部门 ID (0 表示不分配)
+  position_id?: number   // 职位 ID (0 表示不分配)
 }
 
 // 更新用户请求 (Admin)
@@ -85,8 +210,8 @@ export interface UpdateUserRequest {
   name?: string
   email?: string
   phone?: string
-  department?: string
-  position?: string
+  department_id?: number // 所属部门 ID (0 表示清空)
+  position_id?: number   // 职位 ID (0 表示清空)
   role?: string
   status?: number
 }
@@ -97,25 +222,91 @@ export const authApi = {
   login: (data: LoginRequest) =>
     api.post<ApiResponse<LoginResponse>>('/auth/login', data),
 
+  getPasswordPolicy: () =>
+    api.get<ApiResponse<PasswordPolicy>>('/auth/password-policy'),
+
+  // 单点登录配置
+  getOIDCInfo: () =>
+    api.get<ApiResponse<OIDCInfo>>('/auth/oidc'),
+
+  // 凭单点登录回调返回的一次性票据换取令牌
+  exchangeOIDCTicket: (ticket: string, deviceName?: string) =>
+    api.post<ApiResponse<LoginResponse>>('/auth/oidc/token', { ticket, device_name: deviceName }),
+
+  // 两步验证登录 (code 为 6 位验证码或恢复码)
+  verifyTwoFactor: (challengeToken: string, code: string) =>
+    api.post<ApiResponse<LoginResponse>>('/auth/login/2fa', { challenge_token: challengeToken, code }),
+
   // 注册
   register: (data: RegisterRequest) =>
     api.post<ApiResponse<null>>('/auth/register', data),
 
-  // 退出登录
-  logout: () =>
-    api.post<ApiResponse<null>>('/auth/logout'),
+  // 找回密码 (向注册邮箱发送重置码)
+  forgotPassword: (email: string) =>
+    api.post<ApiResponse<null>>('/auth/forgot-password', { email }),
+
+  // 凭重置码设置新密码
+  resetPassword: (token: string, newPassword: string) =>
+    api.post<ApiResponse<null>>('/auth/reset-password', { token, new_password: newPassword }),
+
+  // 退出登录 (吊销当前登录会话)
+  logout: (refreshToken: string) =>
+    api.post<ApiResponse<null>>('/auth/logout', { refresh_token: refreshToken }),
 
   // 获取当前用户
   getCurrentUser: () =>
-    api.get<ApiResponse<User>>('/users/me'),
+    api.get<ApiResponse<CurrentUser>>('/users/me'),
 
   // 更新个人信息
   updateProfile: (data: UpdateProfileRequest) =>
     api.put<ApiResponse<User>>('/users/me', data),
 
-  // 修改密码
+  // 修改密码 (其他登录全部失效，返回当前客户端的新令牌)
   changePassword: (data: ChangePasswordRequest) =>
-    api.put<ApiResponse<null>>('/users/me/password', data),
+    api.put<ApiResponse<LoginResponse>>('/users/me/password', data),
+
+  // 我的登录会话
+  getSessions: () =>
+    api.get<ApiResponse<UserSession[]>>('/users/me/sessions'),
+
+  // 下线指定登录会话
+  revokeSession: (sessionId: number) =>
+    api.delete<ApiResponse<null>>(`/users/me/sessions/${sessionId}`),
+
+  // === Personal Access Tokens ===
+  getPersonalTokens: () =>
+    api.get<ApiResponse<PersonalToken[]>>('/users/me/tokens'),
+
+  getTokenScopes: () =>
+    api.get<ApiResponse<TokenScope[]>>('/users/me/tokens/scopes'),
+
+  createPersonalToken: (data: { name: string; scopes: string[]; expires_in_days: number }) =>
+    api.post<ApiResponse<PersonalTokenCreated>>('/users/me/tokens', data),
+
+  revokePersonalToken: (id: number) =>
+    api.delete<ApiResponse<null>>(`/users/me/tokens/${id}`),
+
+  // === Two-Factor Authentication ===
+  getTwoFactorStatus: () =>
+    api.get<ApiResponse<TwoFactorStatus>>('/users/me/2fa'),
+
+  setupTwoFactor: () =>
+    api.post<ApiResponse<TwoFactorSetup>>('/users/me/2fa/setup'),
+
+  enableTwoFactor: (code: string) =>
+    api.post<ApiResponse<{ recovery_codes: string[] }>>('/users/me/2fa/enable', { code }),
+
+  disableTwoFactor: (password: string, code: string) =>
+    api.post<ApiResponse<null>>('/users/me/2fa/disable', { password, code }),
+
+  regenerateRecoveryCodes: (code: string) =>
+    api.post<ApiResponse<{ recovery_codes: string[] }>>('/users/me/2fa/recovery-codes', { code }),
+
+  getSecuritySettings: () =>
+    api.get<ApiResponse<SecuritySettings>>('/settings/security'),
+
+  updateSecuritySettings: (data: Partial<SecuritySettings>) =>
+    api.put<ApiResponse<SecuritySettings>>('/settings/security', data),
 
   // === User Management (Admin) ===
   getUsers: (params: UserListParams) =>
@@ -132,4 +323,35 @@ export const authApi = {
 
   resetPassword: (id: number, password: string) =>
     api.put<ApiResponse<null>>(`/users/${id}/password`, { new_password: password }),
+
+  getUserSessions: (id: number) =>
+    api.get<ApiResponse<UserSession[]>>(`/users/${id}/sessions`),
+
+  revokeUserSession: (id: number, sessionId: number) =>
+    api.delete<ApiResponse<null>>(`/users/${id}/sessions/${sessionId}`),
+
+  revokeUserSessions: (id: number) =>
+    api.delete<ApiResponse<null>>(`/users/${id}/sessions`),
+
+  resetTwoFactor: (id: number) =>
+    api.delete<ApiResponse<null>>(`/users/${id}/2fa`),
+
+  getUserPersonalTokens: (id: number) =>
+    api.get<ApiResponse<PersonalToken[]>>(`/users/${id}/tokens`),
+
+  revokeUserPersonalToken: (id: number, tokenId: number) =>
+    api.delete<ApiResponse<null>>(`/users/${id}/tokens/${tokenId}`),
+
+  unlockUser: (id: number) =>
+    api.post<ApiResponse<null>>(`/users/${id}/unlock`),
+
+  getAuditLogs: (params: AuditLogParams) =>
+    api.get<ApiResponse<{ list: AuditLog[]; total: number }>>('/audit-logs', { params }),
+
+  // 访问令牌签名密钥 (管理员)
+  getSigningKeys: () =>
+    api.get<ApiResponse<SigningKey[]>>('/system/signing-keys'),
+
+  rotateSigningKey: () =>
+    api.post<ApiResponse<SigningKey>>('/system/signing-keys/rotate'),
 }
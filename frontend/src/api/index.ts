@@ -2,7 +2,7 @@
  * @file api/index.ts
  * @description API 请求基础配置
  * 封装 Axios 实例，配置基础 URL、超时时间，并实现请求与响应拦截器。
- * 处理 Token 自动注入、统一错误处理以及登录过期跳转逻辑。
+ * 处理 Token 自动注入、统一错误处理、访问令牌过期自动刷新以及登录过期跳转逻辑。
  */
 import axios, { type AxiosInstance, type AxiosResponse, type InternalAxiosRequestConfig } from 'axios'
 
@@ -53,10 +53,62 @@ export const setAuthLogout = (fn: () => void) => {
   authLogout = fn
 }
 
+// 令牌刷新成功后的回调 (同步 store 中的 token 与权限)
+let tokenRefreshed: ((token: string, permissions?: string[]) => void) | null = null
+
+export const setTokenRefreshed = (fn: (token: string, permissions?: string[]) => void) => {
+  tokenRefreshed = fn
+}
+
+// 使用刷新令牌换取新的访问令牌
+// 多个请求同时过期时共用同一次刷新；刷新失败返回 null。
+let refreshing: Promise<string | null> | null = null
+
+export const refreshAccessToken = (): Promise<string | null> => {
+  if (refreshing) return refreshing
+
+  refreshing = (async () => {
+    const refreshToken = localStorage.getItem('refresh_token')
+    if (!refreshToken) return null
+    try {
+      // 直接使用 axios，避免经过本实例的拦截器
+      const res = await axios.post<ApiResponse<{ token: string; refresh_token: string; permissions?: string[] }>>(
+        '/api/v1/auth/refresh',
+        { refresh_token: refreshToken }
+      )
+      if (res.data.code !== 0) return null
+      const { token, refresh_token, permissions } = res.data.data
+      localStorage.setItem('token', token)
+      localStorage.setItem('refresh_token', refresh_token)
+      tokenRefreshed?.(token, permissions)
+      return token
+    } catch {
+      return null
+    } finally {
+      refreshing = null
+    }
+  })()
+  return refreshing
+}
+
+// 清除登录状态并跳转登录页
+const expireLogin = () => {
+  if (authLogout) {
+    authLogout()
+  } else {
+    // Fallback
+    localStorage.removeItem('token')
+    localStorage.removeItem('refresh_token')
+    localStorage.removeItem('user')
+    localStorage.removeItem('permissions')
+    window.location.href = '/login'
+  }
+}
+
 // 响应拦截器：处理业务错误和 Token 过期
 
 api.interceptors.response.use(
-  (response: AxiosResponse<ApiResponse>) => {
+  async (response: AxiosResponse<ApiResponse>) => {
     const { code, message } = response.data
 
     // 成功
@@ -64,16 +116,18 @@ api.interceptors.response.use(
       return response
     }
 
-    // Token 过期
+    // Token 过期: 先尝试刷新令牌并重发原请求，刷新失败再退出登录
     if (code === 2002) {
-      if (authLogout) {
-        authLogout()
-      } else {
-        // Fallback
-        localStorage.removeItem('token')
-        localStorage.removeItem('user')
-        window.location.href = '/login'
+      const original = response.config as InternalAxiosRequestConfig & { _retried?: boolean }
+      if (!original._retried) {
+        original._retried = true
+        const token = await refreshAccessToken()
+        if (token) {
+          original.headers.Authorization = `Bearer ${token}`
+          return api(original)
+        }
       }
+      expireLogin()
       return Promise.reject(new Error('登录已过期，请重新登录'))
     }
 
@@ -82,13 +136,7 @@ api.interceptors.response.use(
   },
   (error) => {
     if (error.response?.status === 401) {
-      if (authLogout) {
-        authLogout()
-      } else {
-        localStorage.removeItem('token')
-        localStorage.removeItem('user')
-        window.location.href = '/login'
-      }
+      expireLogin()
     }
     return Promise.reject(error)
   }
@@ -0,0 +1,92 @@
+/**
+ * @file api/organization.ts
+ * @description 组织架构管理 API
+ * 部门为树形结构 (决定数据范围中的"本部门")，职位为扁平列表，用户通过 ID 关联。
+ */
+import api, { type ApiResponse } from './index'
+
+// 部门
+export interface Department {
+  id: number
+  name: string       // 部门名称
+  parent_id: number  // 上级部门 ID (0 表示顶级部门)
+  manager_id: number // 负责人用户 ID (0 表示未指定)
+  sort: number       // 排序
+  create_time: string
+  update_time: string
+}
+
+// 部门树节点
+export interface DepartmentNode extends Department {
+  manager_name: string        // 负责人姓名
+  user_count: number          // 直属成员数 (不含下级部门)
+  children: DepartmentNode[]  // 下级部门
+}
+
+// 创建/修改部门请求参数
+export interface DepartmentRequest {
+  name: string
+  parent_id?: number
+  manager_id?: number
+  sort?: number
+}
+
+// 职位
+export interface Position {
+  id: number
+  name: string        // 职位名称
+  description: string // 描述
+  sort: number        // 排序
+  user_count: number  // 担任该职位的用户数
+  create_time: string
+  update_time: string
+}
+
+// 创建/修改职位请求参数
+export interface PositionRequest {
+  name: string
+  description?: string
+  sort?: number
+}
+
+// 部门 API 集合
+export const departmentApi = {
+  // 获取部门树
+  tree: () =>
+    api.get<ApiResponse<DepartmentNode[]>>('/departments'),
+
+  // 创建部门
+  create: (data: DepartmentRequest) =>
+    api.post<ApiResponse<Department>>('/departments', data),
+
+  // 更新部门
+  update: (id: number, data: DepartmentRequest) =>
+    api.put<ApiResponse<Department>>(`/departments/${id}`, data),
+
+  // 删除部门 (存在下级部门或成员时不可删除)
+  delete: (id: number) =>
+    api.delete<ApiResponse<null>>(`/departments/${id}`),
+}
+
+// 职位 API 集合
+export const positionApi = {
+  // 获取职位列表
+  list: () =>
+    api.get<ApiResponse<Position[]>>('/positions'),
+
+  // 创建职位
+  create: (data: PositionRequest) =>
+    api.post<ApiResponse<Position>>('/positions', data),
+
+  // 更新职位
+  update: (id: number, data: PositionRequest) =>
+    api.put<ApiResponse<Position>>(`/positions/${id}`, data),
+
+  // 删除职位 (仍有用户担任时不可删除)
+  delete: (id: number) =>
+    api.delete<ApiResponse<null>>(`/positions/${id}`),
+}
+
+// 将部门树展开为带层级的列表 (用于表格与下拉框缩进显示)
+export const flattenDepartments = (nodes: DepartmentNode[], depth = 0): (DepartmentNode & { depth: number })[] =>
+  nodes.flatMap(n => [{ ...n, depth }, ...flattenDepartments(n.children || [], depth + 1)])
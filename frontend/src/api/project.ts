@@ -1,7 +1,7 @@
 /**
  * @file api/project.ts
  * @description 项目与款项管理 API
- * 涵盖项目增删改查、款项管理、合同编号生成等核心业务接口。
+ * 涵盖项目增删改查、项目成员、款项管理、合同编号生成等核心业务接口。
  */
 import api, { type ApiResponse, type PageData } from './index'
 import type { User } from './auth'
@@ -24,6 +24,21 @@ export interface Project {
   create_time: string     // 创建时间
   payments?: Payment[]    // 关联的款项列表 (可选)
   user?: User             // 负责人信息 (可选)
+  user_id?: number        // 负责人 ID
+  my_role?: ProjectRole   // 当前用户的项目角色 (仅详情接口返回)
+}
+
+// 项目角色: 负责人 / 编辑 / 财务 / 查看
+export type ProjectRole = 'owner' | 'editor' | 'finance' | 'viewer'
+
+// 项目成员 (负责人以 owner 角色排在首位)
+export interface ProjectMember {
+  id: number
+  project_id: number
+  user_id: number
+  role: ProjectRole
+  create_time: string
+  user?: Pick<User, 'id' | 'username' | 'name' | 'department' | 'position'>
 }
 
 // 款项数据模型
@@ -76,6 +91,12 @@ export interface PaymentRequest {
   remark?: string
 }
 
+// 添加项目成员请求参数
+export interface ProjectMemberRequest {
+  user_id: number
+  role: Exclude<ProjectRole, 'owner'>
+}
+
 // 确认收款请求参数
 export interface ConfirmPaymentRequest {
   actual_date: string
@@ -125,6 +146,33 @@ export const projectApi = {
     }),
 }
 
+// 项目成员 API 集合
+export const projectMemberApi = {
+  // 获取项目成员 (含负责人)
+  list: (projectId: number) =>
+    api.get<ApiResponse<ProjectMember[]>>(`/projects/${projectId}/members`, { params: { _t: Date.now() } }),
+
+  // 添加成员
+  add: (projectId: number, data: ProjectMemberRequest) =>
+    api.post<ApiResponse<ProjectMember>>(`/projects/${projectId}/members`, data),
+
+  // 修改成员角色
+  updateRole: (projectId: number, userId: number, role: ProjectMemberRequest['role']) =>
+    api.put<ApiResponse<null>>(`/projects/${projectId}/members/${userId}`, { role }),
+
+  // 移除成员 (移除自己即退出项目)
+  remove: (projectId: number, userId: number) =>
+    api.delete<ApiResponse<null>>(`/projects/${projectId}/members/${userId}`),
+
+  // 转让项目，原负责人保留为编辑成员
+  transfer: (projectId: number, userId: number) =>
+    api.post<ApiResponse<null>>(`/projects/${projectId}/transfer`, { user_id: userId }),
+
+  // 搜索可添加的用户
+  candidates: (keyword: string) =>
+    api.get<ApiResponse<User[]>>('/projects/member-candidates', { params: { keyword } }),
+}
+
 // 收款 API 集合
 export const paymentApi = {
   // 获取收款列表
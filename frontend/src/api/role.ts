@@ -0,0 +1,62 @@
+/**
+ * @file api/role.ts
+ * @description 角色与权限管理 API
+ * 用户通过角色获得权限，管理员可创建自定义角色并为其分配权限。
+ */
+import api, { type ApiResponse } from './index'
+
+// 权限定义 (由系统内置，不可增删)
+export interface Permission {
+  id: number
+  code: string   // 权限编码 (如 project.delete)
+  name: string   // 权限名称
+  module: string // 所属模块
+  sort: number   // 排序
+}
+
+// 数据范围: 仅本人、本部门、本部门及下级部门、全部数据
+export type DataScope = 'self' | 'department' | 'department_and_children' | 'all'
+
+// 角色定义
+export interface Role {
+  id: number
+  code: string          // 角色编码 (用户的 role 字段)
+  name: string          // 角色名称
+  description: string   // 描述
+  built_in: boolean     // 是否为内置角色 (不可删除)
+  data_scope: DataScope // 可查看的项目与收款范围
+  permissions: string[] // 权限编码列表
+  user_count: number    // 使用该角色的用户数
+}
+
+// 创建/修改角色请求参数
+export interface RoleRequest {
+  code?: string // 角色编码 (仅创建时有效)
+  name: string
+  description?: string
+  data_scope?: DataScope // 为空时创建为仅本人、更新不修改
+  permissions: string[]
+}
+
+// 角色 API 集合
+export const roleApi = {
+  // 获取角色列表
+  list: () =>
+    api.get<ApiResponse<Role[]>>('/roles'),
+
+  // 获取全部权限
+  permissions: () =>
+    api.get<ApiResponse<Permission[]>>('/permissions'),
+
+  // 创建角色
+  create: (data: RoleRequest) =>
+    api.post<ApiResponse<Role>>('/roles', data),
+
+  // 更新角色
+  update: (id: number, data: RoleRequest) =>
+    api.put<ApiResponse<Role>>(`/roles/${id}`, data),
+
+  // 删除角色
+  delete: (id: number) =>
+    api.delete<ApiResponse<null>>(`/roles/${id}`),
+}
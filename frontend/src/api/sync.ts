@@ -1,7 +1,9 @@
-import api, { type ApiResponse } from "@/api"
+import api, { type ApiResponse, type PageData } from "@/api"
 
 export interface SyncConfig {
+    profile_id?: number // 已保存的连接配置ID (指定时忽略其余字段)
     db_type: string
+    path?: string // SQLite 文件路径 (db_type 为 sqlite 时)
     host: string
     port: number
     user: string
@@ -10,40 +12,256 @@ export interface SyncConfig {
     ssl_mode?: string
 }
 
+export interface SyncProfile {
+    id: number
+    name: string
+    db_type: string
+    path: string
+    host: string
+    port: number
+    user: string
+    db_name: string
+    ssl_mode: string
+    has_password: boolean
+    create_time: string
+    update_time: string
+}
+
+export interface SyncProfileForm {
+    name: string
+    db_type: string
+    path?: string
+    host: string
+    port: number
+    user: string
+    password?: string // 更新时留空表示保留原密码
+    db_name: string
+    ssl_mode?: string
+}
+
+export type SyncMode = 'push' | 'pull' | 'merge'
+
+export type ConflictPolicy = 'last_writer_wins' | 'local_wins' | 'remote_wins' | 'manual'
+
+export interface SyncConflict {
+    table_name: string
+    record_id: number
+    local_action: 'update' | 'delete'
+    remote_action: 'update' | 'delete'
+    local_time: string
+    remote_time: string
+    resolution: 'local' | 'remote' | 'unsolved'
+}
+
 export interface TableCompareResult {
     table_name: string
     local_count: number
     remote_count: number
+    pending_count: number
+    remote_pending_count: number
+    conflicts: SyncConflict[]
+    last_sync_time: string | null
+}
+
+export interface SyncFieldDiff {
+    column: string
+    local: string
+    remote: string
+}
+
+export interface TableDiffResult {
+    table_name: string
+    local_count: number
+    remote_count: number
+    same_count: number
+    local_only: number[]
+    remote_only: number[]
+    changed: number[]
+    samples: { id: number, fields: SyncFieldDiff[] }[]
+    error_message: string
 }
 
 export interface SyncResult {
     table_name: string
+    mode: SyncMode
     synced_count: number
+    deleted_count: number
+    pulled_count: number
+    local_deleted_count: number
+    conflicts: SyncConflict[]
+    incremental: boolean
     success: boolean
     error_message: string
 }
 
+export type SyncJobStatus = 'pending' | 'running' | 'succeeded' | 'failed' | 'canceled'
+
+export interface SyncTableProgress {
+    table_name: string
+    done: number
+    total: number
+}
+
+export interface SyncJob {
+    id: string
+    status: SyncJobStatus
+    mode: SyncMode
+    progress: SyncTableProgress[]
+    results: SyncResult[]
+    error_message: string
+    create_time: string
+    finish_time: string | null
+}
+
+// 订阅同步任务进度 (Server-Sent Events)
+// EventSource 无法携带 Authorization 头，这里使用 fetch 读取事件流。
+// 每次收到进度调用 onProgress，任务结束时 resolve 最终状态。
+async function watchJob(id: string, onProgress: (job: SyncJob) => void, signal?: AbortSignal): Promise<SyncJob> {
+    const token = localStorage.getItem('token')
+    const res = await fetch(`/api/v1/sync/jobs/${id}/events`, {
+        headers: token ? { Authorization: `Bearer ${token}` } : {},
+        signal
+    })
+    if (!res.ok || !res.body) {
+        throw new Error(`订阅同步进度失败: ${res.status}`)
+    }
+
+    const reader = res.body.pipeThrough(new TextDecoderStream()).getReader()
+    let buffer = ''
+    let last: SyncJob | null = null
+    for (;;) {
+        const { value, done } = await reader.read()
+        if (done) break
+        buffer += value
+
+        // 事件之间以空行分隔
+        let sep: number
+        while ((sep = buffer.indexOf('\n\n')) >= 0) {
+            const raw = buffer.slice(0, sep)
+            buffer = buffer.slice(sep + 2)
+
+            let event = 'message'
+            let data = ''
+            for (const line of raw.split('\n')) {
+                if (line.startsWith('event:')) event = line.slice(6).trim()
+                else if (line.startsWith('data:')) data += line.slice(5).trim()
+            }
+            if (!data) continue
+
+            last = JSON.parse(data) as SyncJob
+            onProgress(last)
+            if (event === 'done') return last
+        }
+    }
+
+    if (last) return last
+    throw new Error('同步进度连接已断开')
+}
+
+export interface SyncRun {
+    id: number
+    trigger: 'manual' | 'schedule'
+    mode: SyncMode
+    tables: string
+    status: 'running' | 'success' | 'partial' | 'failed' | 'skipped' | 'canceled'
+    attempts: number
+    synced_count: number
+    pulled_count: number
+    deleted_count: number
+    conflict_count: number
+    error_message: string
+    detail: string
+    start_time: string
+    finish_time: string | null
+}
+
 export const syncApi = {
     // 获取同步配置
     getConfig() {
         return api.get('/sync/config')
     },
 
+    // 连接配置列表
+    listProfiles() {
+        return api.get<ApiResponse<SyncProfile[]>>("/sync/profiles")
+    },
+
+    // 新增连接配置 (管理员)
+    createProfile(data: SyncProfileForm) {
+        return api.post<ApiResponse<SyncProfile>>("/sync/profiles", data)
+    },
+
+    // 更新连接配置 (管理员)
+    updateProfile(id: number, data: SyncProfileForm) {
+        return api.put<ApiResponse<SyncProfile>>(`/sync/profiles/${id}`, data)
+    },
+
+    // 删除连接配置 (管理员)
+    deleteProfile(id: number) {
+        return api.delete<ApiResponse<null>>(`/sync/profiles/${id}`)
+    },
+
     // 测试连接
     testConnection(config: SyncConfig) {
         return api.post<ApiResponse<null>>("/sync/test-connection", config)
     },
 
-    // 对比数据
-    compare(config: SyncConfig) {
-        return api.post<ApiResponse<TableCompareResult[]>>("/sync/compare", config)
+    // 对比数据 (policy 决定冲突的处理结果预览)
+    compare(config: SyncConfig, policy: ConflictPolicy = 'last_writer_wins') {
+        return api.post<ApiResponse<TableCompareResult[]>>("/sync/compare", { ...config, policy })
+    },
+
+    // 逐行对比 (返回仅本地、仅云端及内容不同的记录ID，tables 为空时对比全部表)
+    diff(config: SyncConfig, tables: string[] = []) {
+        return api.post<ApiResponse<TableDiffResult[]>>("/sync/diff", { ...config, tables })
+    },
+
+    // 创建/迁移云端表结构 (dryRun 为 true 时仅返回将要执行的 DDL)
+    schema(config: SyncConfig, tables: string[] = [], dryRun = false) {
+        return api.post<ApiResponse<string[]>>("/sync/schema", {
+            ...config,
+            tables,
+            dry_run: dryRun
+        })
+    },
+
+    // 提交后台同步任务 (参数同 execute)
+    submitJob(config: SyncConfig, tables: string[], full = false, mode: SyncMode = 'push', policy: ConflictPolicy = 'last_writer_wins') {
+        return api.post<ApiResponse<SyncJob>>("/sync/jobs", {
+            ...config,
+            tables,
+            full,
+            mode,
+            policy
+        })
+    },
+
+    // 查询同步任务进度
+    getJob(id: string) {
+        return api.get<ApiResponse<SyncJob>>(`/sync/jobs/${id}`)
+    },
+
+    // 取消同步任务
+    cancelJob(id: string) {
+        return api.post<ApiResponse<SyncJob>>(`/sync/jobs/${id}/cancel`)
+    },
+
+    // 订阅同步任务进度 (SSE)
+    watchJob,
+
+    // 同步执行历史
+    listRuns(params: { page?: number, page_size?: number, status?: string } = {}) {
+        return api.get<ApiResponse<PageData<SyncRun>>>("/sync/runs", { params })
     },
 
-    // 执行同步
-    execute(config: SyncConfig, tables: string[]) {
+    // 执行同步 (默认增量推送，full 为 true 时强制全量)
+    execute(config: SyncConfig, tables: string[], full = false, mode: SyncMode = 'push', policy: ConflictPolicy = 'last_writer_wins') {
         return api.post<ApiResponse<SyncResult[]>>("/sync/execute", {
             ...config,
-            tables
+            tables,
+            full,
+            mode,
+            policy
         })
     }
 }
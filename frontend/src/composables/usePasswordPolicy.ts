@@ -0,0 +1,38 @@
+/**
+ * @file composables/usePasswordPolicy.ts
+ * @description 密码策略 Hook
+ * 读取服务端密码策略，生成设置密码时的规则提示 (实际校验以服务端为准)。
+ */
+import { ref, computed } from 'vue'
+import { authApi, type PasswordPolicy } from '@/api/auth'
+
+export function usePasswordPolicy() {
+  const policy = ref<PasswordPolicy | null>(null)
+
+  /** 加载密码策略，失败时不显示提示 */
+  const load = async () => {
+    try {
+      const res = await authApi.getPasswordPolicy()
+      policy.value = res.data.data
+    } catch (error) {
+      console.error('Failed to load password policy:', error)
+    }
+  }
+
+  /** 规则提示，如 "至少 8 位，需包含大写字母、数字" */
+  const hint = computed(() => {
+    const p = policy.value
+    if (!p) return ''
+    const classes = [
+      p.require_upper && '大写字母',
+      p.require_lower && '小写字母',
+      p.require_digit && '数字',
+      p.require_symbol && '特殊字符',
+    ].filter(Boolean)
+    let text = `至少 ${p.min_length} 位`
+    if (classes.length) text += `，需包含${classes.join('、')}`
+    return text
+  })
+
+  return { policy, hint, load }
+}
@@ -21,7 +21,7 @@ import { createPinia } from 'pinia'
 
 import App from './App.vue'
 import router from './router'
-import { setAuthLogout } from '@/api'
+import { setAuthLogout, setTokenRefreshed } from '@/api'
 import { useAuthStore } from '@/stores/auth'
 
 // 创建 Vue 应用实例
@@ -40,5 +40,17 @@ setAuthLogout(() => {
   router.push('/login')
 })
 
+// 访问令牌自动刷新后同步 store 中的 token 与权限 (角色变更后刷新即生效)
+setTokenRefreshed((token, permissions) => {
+  const authStore = useAuthStore()
+  authStore.token = token
+  if (permissions) {
+    authStore.setPermissions(permissions)
+  }
+})
+
 // 挂载应用到 DOM
 app.mount('#app')
+
+// 已登录时同步最新权限 (管理员可能已调整角色权限)
+useAuthStore().loadPermissions()
@@ -1,11 +1,11 @@
 /**
  * @file stores/auth.ts
  * @description 用户认证状态管理
- * 管理用户登录状态、Token、用户信息及相关操作（登录、注册、注销、更新信息）。
+ * 管理用户登录状态、Token、用户信息、角色权限及相关操作（登录、注册、注销、更新信息）。
  */
 import { ref, computed } from 'vue'
 import { defineStore } from 'pinia'
-import { authApi, type User, type LoginRequest } from '@/api/auth'
+import { authApi, type User, type LoginRequest, type LoginResponse } from '@/api/auth'
 
 export const useAuthStore = defineStore('auth', () => {
   // State: 从 localStorage 初始化状态，实现持久化
@@ -13,8 +13,14 @@ export const useAuthStore = defineStore('auth', () => {
   const user = ref<User | null>(
     JSON.parse(localStorage.getItem('user') || 'null')
   )
+  const permissions = ref<string[]>(
+    JSON.parse(localStorage.getItem('permissions') || '[]')
+  ) // 当前角色拥有的权限编码
   const loading = ref(false) // 异步操作加载状态
   const error = ref<string | null>(null) // 错误信息
+  const challengeToken = ref<string | null>(null) // 两步验证挑战令牌 (密码已验证，等待验证码)
+  const twoFactorSetupRequired = ref(false) // 需先启用两步验证 (管理员被要求启用但尚未启用)
+  const passwordChangeRequired = ref(false) // 密码已过期，需先修改密码
 
   // Getters (Computed)
   // 判断是否有 Token
@@ -22,31 +28,113 @@ export const useAuthStore = defineStore('auth', () => {
   // 与 isLoggedIn 相同，可根据业务扩展
   const isAuthenticated = computed(() => !!token.value)
 
+  /**
+   * 判断当前用户是否拥有指定权限
+   * @param permission 权限编码 (如 user.manage)
+   */
+  function can(permission: string) {
+    return permissions.value.includes(permission)
+  }
+
+  /**
+   * 保存当前角色的权限
+   */
+  function setPermissions(list: string[]) {
+    permissions.value = list
+    localStorage.setItem('permissions', JSON.stringify(list))
+  }
+
+  /**
+   * 保存登录结果 (令牌与用户信息)
+   */
+  function saveLogin(data: LoginResponse) {
+    // 保存到 state
+    token.value = data.token
+    user.value = data.user
+    challengeToken.value = null
+    twoFactorSetupRequired.value = !!data.two_factor_setup_required
+    passwordChangeRequired.value = !!data.password_change_required
+    setPermissions(data.permissions ?? [])
+
+    // 保存到 localStorage
+    localStorage.setItem('token', data.token)
+    localStorage.setItem('refresh_token', data.refresh_token)
+    localStorage.setItem('user', JSON.stringify(data.user))
+    localStorage.setItem('isAuthenticated', 'true')
+  }
+
   /**
    * 用户登录
    * @param credentials 登录凭证 (username, password)
-   * @returns 登录成功返回 true, 失败返回 false
+   * @returns 登录成功返回 true, 需要两步验证返回 'two_factor', 失败返回 false
    */
-  async function login(credentials: LoginRequest) {
+  async function login(credentials: LoginRequest): Promise<boolean | 'two_factor'> {
     loading.value = true
     error.value = null
 
     try {
       const response = await authApi.login(credentials)
-      const { token: newToken, user: userData } = response.data.data
+      const data = response.data.data
+      if (data.two_factor_required && data.challenge_token) {
+        challengeToken.value = data.challenge_token
+        return 'two_factor'
+      }
 
-      // 保存到 state
-      token.value = newToken
-      user.value = userData
+      saveLogin(data)
+      return true
+    } catch (err: unknown) {
+      error.value = err instanceof Error ? err.message : '登录失败'
+      return false
+    } finally {
+      loading.value = false
+    }
+  }
 
-      // 保存到 localStorage
-      localStorage.setItem('token', newToken)
-      localStorage.setItem('user', JSON.stringify(userData))
-      localStorage.setItem('isAuthenticated', 'true')
+  /**
+   * 凭单点登录票据完成登录
+   * @param ticket 单点登录回调返回的一次性票据
+   * @returns 登录成功返回 true, 需要两步验证返回 'two_factor', 失败返回 false
+   */
+  async function loginWithOIDCTicket(ticket: string): Promise<boolean | 'two_factor'> {
+    loading.value = true
+    error.value = null
 
+    try {
+      const response = await authApi.exchangeOIDCTicket(ticket)
+      const data = response.data.data
+      if (data.two_factor_required && data.challenge_token) {
+        challengeToken.value = data.challenge_token
+        return 'two_factor'
+      }
+
+      saveLogin(data)
       return true
     } catch (err: unknown) {
-      error.value = err instanceof Error ? err.message : '登录失败'
+      error.value = err instanceof Error ? err.message : '单点登录失败'
+      return false
+    } finally {
+      loading.value = false
+    }
+  }
+
+  /**
+   * 提交两步验证码完成登录
+   * @param code 6 位验证码或恢复码
+   */
+  async function verifyTwoFactor(code: string) {
+    if (!challengeToken.value) {
+      error.value = '验证已过期，请重新登录'
+      return false
+    }
+    loading.value = true
+    error.value = null
+
+    try {
+      const response = await authApi.verifyTwoFactor(challengeToken.value, code)
+      saveLogin(response.data.data)
+      return true
+    } catch (err: unknown) {
+      error.value = err instanceof Error ? err.message : '验证失败'
       return false
     } finally {
       loading.value = false
@@ -74,22 +162,28 @@ export const useAuthStore = defineStore('auth', () => {
 
   /**
    * 退出登录
-   * 清除服务端 Session 及本地 Token 和用户信息
+   * 吊销服务端令牌并清除本地 Token 和用户信息
    */
   async function logout() {
-    try {
-      await authApi.logout()
-    } catch {
-      // 忽略错误，仍然清除本地状态
+    const refreshToken = localStorage.getItem('refresh_token')
+    if (refreshToken) {
+      try {
+        await authApi.logout(refreshToken)
+      } catch {
+        // 忽略错误，仍然清除本地状态
+      }
     }
 
     // 清除状态
     token.value = null
     user.value = null
+    permissions.value = []
 
     // 清除 localStorage
     localStorage.removeItem('token')
+    localStorage.removeItem('refresh_token')
     localStorage.removeItem('user')
+    localStorage.removeItem('permissions')
     localStorage.removeItem('isAuthenticated')
   }
 
@@ -102,19 +196,35 @@ export const useAuthStore = defineStore('auth', () => {
 
     try {
       const response = await authApi.getCurrentUser()
-      user.value = response.data.data
-      localStorage.setItem('user', JSON.stringify(response.data.data))
+      const { permissions: list, ...current } = response.data.data
+      user.value = current
+      localStorage.setItem('user', JSON.stringify(current))
+      setPermissions(list)
     } catch {
       // Token 可能已过期
       await logout()
     }
   }
 
+  /**
+   * 同步当前角色的权限 (失败时保留本地缓存的权限)
+   */
+  async function loadPermissions() {
+    if (!token.value) return
+
+    try {
+      const response = await authApi.getCurrentUser()
+      setPermissions(response.data.data.permissions)
+    } catch {
+      // 忽略错误，登录过期由请求拦截器处理
+    }
+  }
+
   /**
    * 更新个人资料
    * @param data 需要更新的字段
    */
-  async function updateProfile(data: { name?: string; phone?: string; department?: string; position?: string }) {
+  async function updateProfile(data: { name?: string; phone?: string }) {
     loading.value = true
     error.value = null
 
@@ -141,7 +251,13 @@ export const useAuthStore = defineStore('auth', () => {
     error.value = null
 
     try {
-      await authApi.changePassword({ old_password: oldPassword, new_password: newPassword })
+      const response = await authApi.changePassword({ old_password: oldPassword, new_password: newPassword })
+      // 旧令牌已全部吊销，换用服务端返回的新令牌
+      const { token: newToken, refresh_token: refreshToken } = response.data.data
+      token.value = newToken
+      localStorage.setItem('token', newToken)
+      localStorage.setItem('refresh_token', refreshToken)
+      passwordChangeRequired.value = false
       return true
     } catch (err: unknown) {
       error.value = err instanceof Error ? err.message : '修改失败'
@@ -155,13 +271,22 @@ export const useAuthStore = defineStore('auth', () => {
     // State
     token,
     user,
+    permissions,
     loading,
     error,
+    challengeToken,
+    twoFactorSetupRequired,
+    passwordChangeRequired,
     // Computed
     isLoggedIn,
     isAuthenticated,
     // Actions
+    can,
+    setPermissions,
+    loadPermissions,
     login,
+    loginWithOIDCTicket,
+    verifyTwoFactor,
     register,
     logout,
     refreshUser,
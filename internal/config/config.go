@@ -12,6 +12,8 @@ import (
 // Config 应用全局配置结构体
 // 包含数据库、安全、日志及第三方服务的所有配置项。
 type Config struct {
+	DataDir string // 应用数据目录 (存放数据库、日志、本机密钥等)
+
 	// 数据库配置
 	DBType       string // 数据库类型: sqlite (默认), mysql, postgres
 	DBPath       string // SQLite 文件路径 (仅 sqlite 有效)
@@ -23,16 +25,79 @@ type Config struct {
 	DBSSLMode    string // SSL 模式: disable (本地), require (云数据库)
 	DBAutoCreate bool   // 是否自动创建数据库 (本地 true, 云托管 false)
 
-	JWTSecret     string // JWT 签名密钥
-	TokenExpiry   int64  // Token 有效期 (单位: 小时)
-	LogEnable     bool   // 是否启用请求日志
-	LogLevel      string // 日志级别: debug, info, warn, error
-	GitHubRepo    string // 用于检查更新的 GitHub 仓库地址 (格式: owner/repo)
-	LogPath       string // 日志文件输出路径
-	LogMaxSize    int    // 单个日志文件最大大小 (MB)
-	LogMaxBackups int    // 保留旧日志文件的最大个数
-	LogMaxAge     int    // 保留旧日志文件的最大天数
-	LogCompress   bool   // 是否压缩旧日志文件
+	JWTAlgorithm       string // JWT 签名算法: RS256, EdDSA (密钥自动生成于数据目录下的 jwt-keys)
+	JWTKeyRotationDays int    // JWT 签名密钥自动轮换周期 (单位: 天)，0 表示不自动轮换
+	AccessTokenExpiry  int64  // 访问令牌有效期 (单位: 分钟)
+	RefreshTokenExpiry int64  // 刷新令牌有效期 (单位: 小时)，即免登录时长
+	LoginMaxFailures   int    // 同一账户连续登录失败多少次后锁定 (0 表示不锁定)
+	LoginIPMaxFailures int    // 同一 IP 连续登录失败多少次后锁定 (0 表示不锁定)
+	LoginLockout       int    // 锁定时长 (单位: 分钟)，同时作为失败计数的统计窗口
+	LoginDelayBase     int    // 登录失败后的等待时间基数 (单位: 秒)，每次失败翻倍，0 表示不限制
+	LogEnable          bool   // 是否启用请求日志
+	LogLevel           string // 日志级别: debug, info, warn, error
+	GitHubRepo         string // 用于检查更新的 GitHub 仓库地址 (格式: owner/repo)
+	LogPath            string // 日志文件输出路径
+	LogMaxSize         int    // 单个日志文件最大大小 (MB)
+	LogMaxBackups      int    // 保留旧日志文件的最大个数
+	LogMaxAge          int    // 保留旧日志文件的最大天数
+	LogCompress        bool   // 是否压缩旧日志文件
+
+	// 认证配置
+	AuthProviders          string // 启用的认证方式 (逗号分隔，按顺序尝试): local, ldap
+	LDAPURL                string // LDAP 服务地址，如 ldap://dc.example.com:389、ldaps://dc.example.com:636
+	LDAPStartTLS           bool   // 是否使用 StartTLS (仅 ldap://)
+	LDAPInsecureSkipVerify bool   // 是否跳过 TLS 证书校验 (仅测试环境)
+	LDAPBindDN             string // 用于查找用户的服务账号 DN (为空表示匿名查找)
+	LDAPBindPassword       string // 服务账号密码
+	LDAPBaseDN             string // 用户查找的起始 DN
+	LDAPUserFilter         string // 用户查找过滤器，{username} 替换为登录名
+	LDAPAttrUsername       string // 用户名属性
+	LDAPAttrName           string // 姓名属性
+	LDAPAttrEmail          string // 邮箱属性
+	LDAPAttrPhone          string // 手机号属性
+	LDAPAttrGroups         string // 所属组属性
+	LDAPGroupMapping       string // 目录组到角色/部门/职位的映射 (JSON 数组)
+	LDAPTimeout            int    // 连接超时 (单位: 秒)
+	OIDCIssuer             string // OIDC 签发方 URL (为空表示不启用 OIDC 单点登录)
+	OIDCClientID           string // OIDC 客户端ID
+	OIDCClientSecret       string // OIDC 客户端密钥 (公共客户端可为空)
+	OIDCRedirectURL        string // OIDC 回调地址，如 http://localhost:8080/api/v1/auth/oidc/callback
+	OIDCScopes             string // 申请的 scope (空格分隔)
+	OIDCUsernameClaim      string // 作为用户名的声明
+	OIDCGroupsClaim        string // 所属组声明
+	OIDCGroupMapping       string // 组到角色/部门/职位的映射 (JSON 数组，格式同 LDAP_GROUP_MAPPING)
+	OIDCLinkByEmail        bool   // 是否按已验证的邮箱关联已有账户
+	OIDCDisplayName        string // 登录页按钮上显示的名称
+
+	// 邮件与找回密码配置
+	AppURL              string // 应用访问地址，如 http://localhost:8080 (用于生成邮件中的链接，为空时邮件只包含重置码)
+	MailDriver          string // 邮件发送方式: file (写入数据目录下的 mail 目录，默认), smtp
+	MailFrom            string // 发件人
+	MailDir             string // 邮件保存目录 (仅 file，为空表示数据目录下的 mail)
+	SMTPHost            string // SMTP 服务器地址
+	SMTPPort            int    // SMTP 端口
+	SMTPUsername        string // SMTP 用户名 (为空表示无需认证)
+	SMTPPassword        string // SMTP 密码
+	SMTPSecurity        string // SMTP 加密方式: starttls, tls, none
+	PasswordResetExpiry int    // 密码重置链接有效期 (单位: 分钟)
+	PasswordResetLimit  int    // 同一邮箱每小时最多发送的重置邮件数
+
+	// 云端同步配置
+	SyncDBType       string // 云端数据库类型: postgres, mysql, sqlite
+	SyncDBPath       string // SQLite 文件路径 (SyncDBType 为 sqlite 时使用)
+	SyncDBHost       string // 云端数据库主机
+	SyncDBPort       int    // 云端数据库端口
+	SyncDBUser       string // 云端数据库用户名
+	SyncDBPassword   string // 云端数据库密码
+	SyncDBName       string // 云端数据库名
+	SyncSSLMode      string // 云端 SSL 模式 (postgres)
+	SyncInterval     int    // 自动同步间隔 (分钟)，0 表示不按间隔同步
+	SyncCron         string // 自动同步 cron 表达式 (标准 5 段格式，优先于 SyncInterval)
+	SyncMode         string // 自动同步方向: push, pull, merge
+	SyncPolicy       string // 自动同步冲突解决策略
+	SyncTables       string // 自动同步的表 (逗号分隔，为空表示全部)
+	SyncRetryMax     int    // 连接失败时的最大重试次数
+	SyncRetryBackoff int    // 首次重试等待时间 (秒)，之后每次翻倍
 }
 
 // AppConfig 全局配置实例
@@ -43,6 +108,7 @@ var AppConfig *Config
 // 默认值逻辑:
 // - 数据库路径: macOS (~/Library/Application Support/FruitsAI/Orange/orange.db), Windows (%APPDATA%/FruitsAI/Orange/orange.db)
 // - 日志路径: 同上，位于 log 子目录下
+// - 数据目录: 同上 (本机密钥等文件存放于此)
 func Load() {
 	// 尝试加载 .env 文件，如果不存在则忽略错误（使用默认值或环境变量）
 	err := godotenv.Load()
@@ -51,6 +117,7 @@ func Load() {
 	}
 
 	// 计算默认数据库路径和日志路径
+	defaultDataDir := "."
 	defaultDBPath := "orange.db"
 	defaultLogPath := "orange.log"
 
@@ -61,6 +128,7 @@ func Load() {
 		// Windows: %APPDATA%\FruitsAI\Orange
 		appDir := filepath.Join(configDir, "FruitsAI", "Orange")
 		if err := os.MkdirAll(appDir, 0755); err == nil {
+			defaultDataDir = appDir
 			defaultDBPath = filepath.Join(appDir, "orange.db")
 
 			// 日志放到 log 子目录
@@ -79,6 +147,8 @@ func Load() {
 
 	// 组装配置对象，优先从环境变量读取
 	AppConfig = &Config{
+		DataDir: getEnv("DATA_DIR", defaultDataDir),
+
 		// 数据库配置
 		DBType:       getEnv("DB_TYPE", "sqlite"),
 		DBPath:       getEnv("DB_PATH", defaultDBPath),
@@ -90,16 +160,76 @@ func Load() {
 		DBSSLMode:    getEnv("DB_SSL_MODE", "disable"),
 		DBAutoCreate: getEnvBool("DB_AUTO_CREATE", true),
 
-		JWTSecret:     getEnv("JWT_SECRET", "orange-secret-key-change-in-production"),
-		TokenExpiry:   getEnvInt("TOKEN_EXPIRY", 24),
-		LogEnable:     getEnvBool("LOG_ENABLE", true),
-		LogLevel:      getEnv("LOG_LEVEL", "debug"),
-		GitHubRepo:    getEnv("GITHUB_REPO", "FruitsAI/Orange"),
-		LogPath:       getEnv("LOG_PATH", defaultLogPath),
-		LogMaxSize:    int(getEnvInt("LOG_MAX_SIZE", 10)),   // 10MB
-		LogMaxBackups: int(getEnvInt("LOG_MAX_BACKUPS", 5)), // 5 files
-		LogMaxAge:     int(getEnvInt("LOG_MAX_AGE", 30)),    // 30 days
-		LogCompress:   getEnvBool("LOG_COMPRESS", true),     // Compress by default
+		JWTAlgorithm:       getEnv("JWT_ALGORITHM", "RS256"),
+		JWTKeyRotationDays: int(getEnvInt("JWT_KEY_ROTATION_DAYS", 0)),
+		AccessTokenExpiry:  getEnvInt("ACCESS_TOKEN_EXPIRY", 15),
+		RefreshTokenExpiry: getEnvInt("REFRESH_TOKEN_EXPIRY", 168),
+		LoginMaxFailures:   int(getEnvInt("LOGIN_MAX_FAILURES", 5)),
+		LoginIPMaxFailures: int(getEnvInt("LOGIN_IP_MAX_FAILURES", 20)),
+		LoginLockout:       int(getEnvInt("LOGIN_LOCKOUT", 15)),
+		LoginDelayBase:     int(getEnvInt("LOGIN_DELAY_BASE", 1)),
+		LogEnable:          getEnvBool("LOG_ENABLE", true),
+		LogLevel:           getEnv("LOG_LEVEL", "debug"),
+		GitHubRepo:         getEnv("GITHUB_REPO", "FruitsAI/Orange"),
+		LogPath:            getEnv("LOG_PATH", defaultLogPath),
+		LogMaxSize:         int(getEnvInt("LOG_MAX_SIZE", 10)),   // 10MB
+		LogMaxBackups:      int(getEnvInt("LOG_MAX_BACKUPS", 5)), // 5 files
+		LogMaxAge:          int(getEnvInt("LOG_MAX_AGE", 30)),    // 30 days
+		LogCompress:        getEnvBool("LOG_COMPRESS", true),     // Compress by default
+
+		AuthProviders:          getEnv("AUTH_PROVIDERS", "local"),
+		LDAPURL:                getEnv("LDAP_URL", ""),
+		LDAPStartTLS:           getEnvBool("LDAP_START_TLS", false),
+		LDAPInsecureSkipVerify: getEnvBool("LDAP_INSECURE_SKIP_VERIFY", false),
+		LDAPBindDN:             getEnv("LDAP_BIND_DN", ""),
+		LDAPBindPassword:       getEnv("LDAP_BIND_PASSWORD", ""),
+		LDAPBaseDN:             getEnv("LDAP_BASE_DN", ""),
+		LDAPUserFilter:         getEnv("LDAP_USER_FILTER", "(uid={username})"),
+		LDAPAttrUsername:       getEnv("LDAP_ATTR_USERNAME", "uid"),
+		LDAPAttrName:           getEnv("LDAP_ATTR_NAME", "cn"),
+		LDAPAttrEmail:          getEnv("LDAP_ATTR_EMAIL", "mail"),
+		LDAPAttrPhone:          getEnv("LDAP_ATTR_PHONE", "telephoneNumber"),
+		LDAPAttrGroups:         getEnv("LDAP_ATTR_GROUPS", "memberOf"),
+		LDAPGroupMapping:       getEnv("LDAP_GROUP_MAPPING", ""),
+		LDAPTimeout:            int(getEnvInt("LDAP_TIMEOUT", 10)),
+		OIDCIssuer:             getEnv("OIDC_ISSUER", ""),
+		OIDCClientID:           getEnv("OIDC_CLIENT_ID", ""),
+		OIDCClientSecret:       getEnv("OIDC_CLIENT_SECRET", ""),
+		OIDCRedirectURL:        getEnv("OIDC_REDIRECT_URL", ""),
+		OIDCScopes:             getEnv("OIDC_SCOPES", "openid profile email"),
+		OIDCUsernameClaim:      getEnv("OIDC_USERNAME_CLAIM", "preferred_username"),
+		OIDCGroupsClaim:        getEnv("OIDC_GROUPS_CLAIM", "groups"),
+		OIDCGroupMapping:       getEnv("OIDC_GROUP_MAPPING", ""),
+		OIDCLinkByEmail:        getEnvBool("OIDC_LINK_BY_EMAIL", false),
+		OIDCDisplayName:        getEnv("OIDC_DISPLAY_NAME", "SSO"),
+
+		AppURL:              getEnv("APP_URL", ""),
+		MailDriver:          getEnv("MAIL_DRIVER", "file"),
+		MailFrom:            getEnv("MAIL_FROM", "Orange <noreply@localhost>"),
+		MailDir:             getEnv("MAIL_DIR", ""),
+		SMTPHost:            getEnv("SMTP_HOST", ""),
+		SMTPPort:            int(getEnvInt("SMTP_PORT", 587)),
+		SMTPUsername:        getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:        getEnv("SMTP_PASSWORD", ""),
+		SMTPSecurity:        getEnv("SMTP_SECURITY", "starttls"),
+		PasswordResetExpiry: int(getEnvInt("PASSWORD_RESET_EXPIRY", 30)),
+		PasswordResetLimit:  int(getEnvInt("PASSWORD_RESET_LIMIT", 3)),
+
+		SyncDBType:       getEnv("SYNC_DB_TYPE", ""),
+		SyncDBPath:       getEnv("SYNC_DB_PATH", ""),
+		SyncDBHost:       getEnv("SYNC_DB_HOST", ""),
+		SyncDBPort:       int(getEnvInt("SYNC_DB_PORT", 5432)),
+		SyncDBUser:       getEnv("SYNC_DB_USER", ""),
+		SyncDBPassword:   getEnv("SYNC_DB_PASSWORD", ""),
+		SyncDBName:       getEnv("SYNC_DB_NAME", ""),
+		SyncSSLMode:      getEnv("SYNC_SSL_MODE", ""),
+		SyncInterval:     int(getEnvInt("SYNC_INTERVAL", 0)),
+		SyncCron:         getEnv("SYNC_CRON", ""),
+		SyncMode:         getEnv("SYNC_MODE", "push"),
+		SyncPolicy:       getEnv("SYNC_POLICY", "last_writer_wins"),
+		SyncTables:       getEnv("SYNC_TABLES", ""),
+		SyncRetryMax:     int(getEnvInt("SYNC_RETRY_MAX", 3)),
+		SyncRetryBackoff: int(getEnvInt("SYNC_RETRY_BACKOFF", 30)),
 	}
 }
 
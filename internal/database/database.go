@@ -86,6 +86,11 @@ func initDB() (*gorm.DB, error) {
 		return nil, err
 	}
 
+	// 注册删除墓碑回调 (增量同步依赖)
+	if err := registerTombstoneCallback(database); err != nil {
+		return nil, err
+	}
+
 	return database, nil
 }
 
@@ -0,0 +1,195 @@
+package database
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Migration 版本化的数据库迁移
+// Version 严格递增，已发布的迁移不可再修改，表结构或数据的变更一律追加新的迁移。
+type Migration struct {
+	Version int                  // 版本号
+	Name    string               // 迁移名称 (如 create_refresh_tokens)
+	Up      func(*gorm.DB) error // 升级
+	Down    func(*gorm.DB) error // 回滚 (为 nil 表示不可回滚)
+}
+
+// MigrationStatus 迁移执行状态
+type MigrationStatus struct {
+	Version   int        // 版本号
+	Name      string     // 迁移名称
+	Applied   bool       // 是否已执行
+	AppliedAt *time.Time // 执行时间
+	Unknown   bool       // 数据库中已执行、但当前程序不认识的迁移 (由更新版本的程序执行)
+}
+
+// schemaMigration 已执行的迁移记录
+type schemaMigration struct {
+	Version   int       `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"size:100;not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+// TableName 指定表名
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Migrate 执行全部未执行的迁移 (应用启动时调用)
+func Migrate(db *gorm.DB) error {
+	return MigrateTo(db, LatestVersion())
+}
+
+// LatestVersion 当前程序包含的最新迁移版本
+func LatestVersion() int {
+	if len(migrations) == 0 {
+		return 0
+	}
+	return migrations[len(migrations)-1].Version
+}
+
+// MigrateTo 升级或回滚到指定版本
+// 目标版本高于当前版本时按顺序执行 Up，低于当前版本时按倒序执行 Down。
+// 每个迁移及其版本记录在同一事务中执行；MySQL 的 DDL 会隐式提交，失败时需人工检查。
+func MigrateTo(db *gorm.DB, target int) error {
+	if target < 0 || target > LatestVersion() {
+		return fmt.Errorf("无效的迁移版本: %d (最新版本 %d)", target, LatestVersion())
+	}
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if m.Version > target {
+			break
+		}
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+		if err := runMigration(db, m, true); err != nil {
+			return err
+		}
+	}
+
+	for i := len(migrations) - 1; i >= 0; i-- {
+		m := migrations[i]
+		if m.Version <= target {
+			break
+		}
+		if _, ok := applied[m.Version]; !ok {
+			continue
+		}
+		if err := runMigration(db, m, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Rollback 回滚最近执行的 steps 个迁移
+func Rollback(db *gorm.DB, steps int) error {
+	if steps <= 0 {
+		return nil
+	}
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return err
+	}
+
+	for i := len(migrations) - 1; i >= 0 && steps > 0; i-- {
+		m := migrations[i]
+		if _, ok := applied[m.Version]; !ok {
+			continue
+		}
+		if err := runMigration(db, m, false); err != nil {
+			return err
+		}
+		steps--
+	}
+	return nil
+}
+
+// Status 获取所有迁移的执行状态 (按版本升序)
+func Status(db *gorm.DB) ([]MigrationStatus, error) {
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		status := MigrationStatus{Version: m.Version, Name: m.Name}
+		if record, ok := applied[m.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = &record.AppliedAt
+			delete(applied, m.Version)
+		}
+		list = append(list, status)
+	}
+	for _, record := range applied {
+		list = append(list, MigrationStatus{
+			Version:   record.Version,
+			Name:      record.Name,
+			Applied:   true,
+			AppliedAt: &record.AppliedAt,
+			Unknown:   true,
+		})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Version < list[j].Version })
+	return list, nil
+}
+
+// appliedMigrations 读取已执行的迁移 (首次运行时创建 schema_migrations 表)
+func appliedMigrations(db *gorm.DB) (map[int]schemaMigration, error) {
+	if err := db.AutoMigrate(&schemaMigration{}); err != nil {
+		return nil, fmt.Errorf("创建 schema_migrations 表失败: %w", err)
+	}
+	var records []schemaMigration
+	if err := db.Order("version").Find(&records).Error; err != nil {
+		return nil, err
+	}
+	applied := make(map[int]schemaMigration, len(records))
+	for _, r := range records {
+		applied[r.Version] = r
+	}
+	return applied, nil
+}
+
+// runMigration 在事务中执行单个迁移并更新版本记录
+func runMigration(db *gorm.DB, m Migration, up bool) error {
+	if !up && m.Down == nil {
+		return fmt.Errorf("迁移 %d_%s 不支持回滚", m.Version, m.Name)
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if up {
+			if err := m.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&schemaMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}).Error
+		}
+		if err := m.Down(tx); err != nil {
+			return err
+		}
+		return tx.Delete(&schemaMigration{}, m.Version).Error
+	})
+	if err != nil {
+		direction := "up"
+		if !up {
+			direction = "down"
+		}
+		return fmt.Errorf("迁移 %d_%s (%s) 失败: %w", m.Version, m.Name, direction, err)
+	}
+
+	if up {
+		slog.Info("Migration applied", "version", m.Version, "name", m.Name)
+	} else {
+		slog.Info("Migration rolled back", "version", m.Version, "name", m.Name)
+	}
+	return nil
+}
@@ -0,0 +1,637 @@
+package database
+
+import (
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/models"
+	"gorm.io/gorm"
+)
+
+// migrations 全部迁移 (按版本升序)
+//
+// 基线迁移按引入版本化迁移时的表结构快照 (v1* 结构体) 建表，不随模型变化；之后的表结构变化都须通过新的迁移完成。
+// 早期版本的基线曾按当时的模型建表，后续迁移须可在这样的库上安全执行:
+// 新增表/列优先使用 AutoMigrate 或先用 Migrator().HasColumn 等判断，数据回填使用幂等的 UPDATE。
+var migrations = []Migration{
+	{
+		Version: 1,
+		Name:    "baseline",
+		// 引入版本化迁移之前由 AutoMigrate 维护的全部表，已有库执行时只补齐缺失的表和列
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(baselineModels()...)
+		},
+		Down: func(tx *gorm.DB) error {
+			list := baselineModels()
+			for i := len(list) - 1; i >= 0; i-- {
+				if err := tx.Migrator().DropTable(list[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Version: 2,
+		Name:    "create_refresh_tokens",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.User{}, &models.RefreshToken{})
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropTable(&models.RefreshToken{}); err != nil {
+				return err
+			}
+			return tx.Migrator().DropColumn(&models.User{}, "TokenVersion")
+		},
+	},
+	{
+		Version: 3,
+		Name:    "create_user_sessions",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.RefreshToken{}, &models.UserSession{})
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropTable(&models.UserSession{}); err != nil {
+				return err
+			}
+			return tx.Migrator().DropColumn(&models.RefreshToken{}, "SessionID")
+		},
+	},
+	{
+		Version: 4,
+		Name:    "add_two_factor_auth",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.User{}, &models.RecoveryCode{}, &models.LoginChallenge{}, &models.SystemSetting{})
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropTable(&models.RecoveryCode{}, &models.LoginChallenge{}, &models.SystemSetting{}); err != nil {
+				return err
+			}
+			for _, column := range []string{"TwoFactorEnabled", "TOTPSecret", "TOTPLastStep"} {
+				if err := tx.Migrator().DropColumn(&models.User{}, column); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Version: 5,
+		Name:    "create_login_throttles_and_audit_logs",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.LoginThrottle{}, &models.AuditLog{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&models.LoginThrottle{}, &models.AuditLog{})
+		},
+	},
+	{
+		Version: 6,
+		Name:    "add_password_policy",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.AutoMigrate(&models.User{}, &models.PasswordHistory{}); err != nil {
+				return err
+			}
+			// 已有用户以创建时间作为最近修改密码时间
+			return tx.Model(&models.User{}).
+				Where("password_change_time IS NULL").
+				Update("password_change_time", gorm.Expr("create_time")).Error
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropTable(&models.PasswordHistory{}); err != nil {
+				return err
+			}
+			return tx.Migrator().DropColumn(&models.User{}, "PasswordChangeTime")
+		},
+	},
+	{
+		Version: 7,
+		Name:    "add_user_auth_source",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.User{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropColumn(&models.User{}, "AuthSource")
+		},
+	},
+	{
+		Version: 8,
+		Name:    "create_user_identities_and_oidc_logins",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.UserIdentity{}, &models.OIDCLogin{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&models.OIDCLogin{}, &models.UserIdentity{})
+		},
+	},
+	{
+		Version: 9,
+		Name:    "create_personal_access_tokens",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.PersonalAccessToken{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&models.PersonalAccessToken{})
+		},
+	},
+	{
+		Version: 10,
+		Name:    "create_password_reset_tokens",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.PasswordResetToken{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&models.PasswordResetToken{})
+		},
+	},
+	{
+		Version: 11,
+		Name:    "create_roles_and_permissions",
+		// 建表并写入初始权限与内置角色: admin 拥有全部权限，user 保留原有的项目、收款、仪表盘与同步功能
+		Up: func(tx *gorm.DB) error {
+			if err := tx.AutoMigrate(&models.Role{}, &models.Permission{}, &models.RolePermission{}); err != nil {
+				return err
+			}
+			// 此前角色为自由字符串，非 admin 的取值一律视为普通用户
+			if err := tx.Model(&models.User{}).Where("role NOT IN ?", []string{"admin", "user"}).Update("role", "user").Error; err != nil {
+				return err
+			}
+			return seedRoles(tx, v11Permissions, []v11Role{
+				{Code: "admin", Name: "管理员", Description: "拥有全部权限"},
+				{Code: "user", Name: "普通用户", Description: "管理自己的项目与收款", Permissions: []string{
+					"project.view", "project.create", "project.edit", "project.delete", "project.archive",
+					"payment.view", "payment.create", "payment.edit", "payment.delete", "payment.confirm",
+					"dashboard.view", "sync.execute",
+				}},
+			})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&models.RolePermission{}, &models.Permission{}, &models.Role{})
+		},
+	},
+	{
+		Version: 12,
+		Name:    "add_departments_and_data_scope",
+		// 将用户原有的部门名称整理为顶级部门并回填 users.department_id；
+		// 管理员角色的数据范围为全部，其余角色保持仅本人
+		Up: func(tx *gorm.DB) error {
+			if err := tx.AutoMigrate(&models.Department{}, &models.User{}, &models.Role{}); err != nil {
+				return err
+			}
+			var names []string
+			if err := tx.Model(&models.User{}).Where("department <> '' AND department_id = 0").
+				Distinct().Order("department").Pluck("department", &names).Error; err != nil {
+				return err
+			}
+			for _, name := range names {
+				dept := models.Department{Name: name}
+				if err := tx.Where("name = ? AND parent_id = 0", name).FirstOrCreate(&dept).Error; err != nil {
+					return err
+				}
+				if err := tx.Model(&models.User{}).Where("department = ? AND department_id = 0", name).
+					Update("department_id", dept.ID).Error; err != nil {
+					return err
+				}
+			}
+			return tx.Model(&models.Role{}).Where("code = ?", "admin").Update("data_scope", "all").Error
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropColumn(&models.Role{}, "DataScope"); err != nil {
+				return err
+			}
+			if err := tx.Migrator().DropIndex(&models.User{}, "DepartmentID"); err != nil {
+				return err
+			}
+			if err := tx.Migrator().DropColumn(&models.User{}, "DepartmentID"); err != nil {
+				return err
+			}
+			return tx.Migrator().DropTable(&models.Department{})
+		},
+	},
+	{
+		Version: 13,
+		Name:    "create_positions_and_department_managers",
+		// 部门增加负责人；将用户原有的职位名称整理为职位并回填 users.position_id，新增组织架构管理权限 (授予管理员)
+		Up: func(tx *gorm.DB) error {
+			if err := tx.AutoMigrate(&models.Department{}, &models.Position{}, &models.User{}); err != nil {
+				return err
+			}
+			var names []string
+			if err := tx.Model(&models.User{}).Where("position <> '' AND position_id = 0").
+				Distinct().Order("position").Pluck("position", &names).Error; err != nil {
+				return err
+			}
+			for i, name := range names {
+				position := models.Position{Name: name, Sort: i + 1}
+				if err := tx.Where("name = ?", name).FirstOrCreate(&position).Error; err != nil {
+					return err
+				}
+				if err := tx.Model(&models.User{}).Where("position = ? AND position_id = 0", name).
+					Update("position_id", position.ID).Error; err != nil {
+					return err
+				}
+			}
+			return seedPermissions(tx, v13Permissions, "admin")
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := dropPermissions(tx, v13Permissions); err != nil {
+				return err
+			}
+			if err := tx.Migrator().DropIndex(&models.User{}, "PositionID"); err != nil {
+				return err
+			}
+			if err := tx.Migrator().DropColumn(&models.User{}, "PositionID"); err != nil {
+				return err
+			}
+			if err := tx.Migrator().DropTable(&models.Position{}); err != nil {
+				return err
+			}
+			return tx.Migrator().DropColumn(&models.Department{}, "ManagerID")
+		},
+	},
+	{
+		Version: 14,
+		Name:    "create_project_members",
+		// 项目成员 (负责人仍由 projects.user_id 表示，无需回填)
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.ProjectMember{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&models.ProjectMember{})
+		},
+	},
+	{
+		Version: 15,
+		Name:    "revoke_user_sync_execute",
+		// 迁移 11 将 sync.execute 授予了内置 user 角色；同步可读写云端与本地全部数据，仅保留给管理员
+		Up: func(tx *gorm.DB) error {
+			return revokePermissions(tx, []string{"sync.execute"}, "user")
+		},
+		Down: func(tx *gorm.DB) error {
+			return seedPermissions(tx, []models.Permission{{Code: "sync.execute"}}, "user")
+		},
+	},
+	{
+		Version: 16,
+		Name:    "add_sync_state_remote_seq",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&models.SyncState{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropColumn(&models.SyncState{}, "LastRemoteSeq")
+		},
+	},
+	{
+		Version: 17,
+		Name:    "add_project_manage_permission",
+		// 新增管理全部项目的权限 (授予管理员)，此前管理员按角色编码视同所有项目的负责人
+		Up: func(tx *gorm.DB) error {
+			return seedPermissions(tx, v17Permissions, "admin")
+		},
+		Down: func(tx *gorm.DB) error {
+			return dropPermissions(tx, v17Permissions)
+		},
+	},
+}
+
+// v11Permissions 迁移 11 写入的权限 (之后新增的权限须通过新的迁移写入)
+var v11Permissions = []models.Permission{
+	{Code: "project.view", Name: "查看项目", Module: "项目"},
+	{Code: "project.create", Name: "创建项目", Module: "项目"},
+	{Code: "project.edit", Name: "编辑项目", Module: "项目"},
+	{Code: "project.delete", Name: "删除项目", Module: "项目"},
+	{Code: "project.archive", Name: "归档项目", Module: "项目"},
+	{Code: "payment.view", Name: "查看收款", Module: "收款"},
+	{Code: "payment.create", Name: "创建收款", Module: "收款"},
+	{Code: "payment.edit", Name: "编辑收款", Module: "收款"},
+	{Code: "payment.delete", Name: "删除收款", Module: "收款"},
+	{Code: "payment.confirm", Name: "确认收款", Module: "收款"},
+	{Code: "dashboard.view", Name: "查看仪表盘", Module: "仪表盘"},
+	{Code: "dictionary.edit", Name: "维护数据字典", Module: "系统"},
+	{Code: "notification.send", Name: "发送与管理通知", Module: "系统"},
+	{Code: "sync.execute", Name: "执行数据同步", Module: "数据同步"},
+	{Code: "sync.manage", Name: "管理同步连接配置", Module: "数据同步"},
+	{Code: "user.manage", Name: "用户管理", Module: "用户与权限"},
+	{Code: "role.manage", Name: "角色与权限管理", Module: "用户与权限"},
+	{Code: "audit.view", Name: "查看审计日志", Module: "用户与权限"},
+	{Code: "setting.manage", Name: "安全设置与签名密钥", Module: "用户与权限"},
+}
+
+// v13Permissions 迁移 13 新增的权限
+var v13Permissions = []models.Permission{
+	{Code: "org.manage", Name: "组织架构管理", Module: "用户与权限", Sort: 20},
+}
+
+// v17Permissions 迁移 17 新增的权限
+var v17Permissions = []models.Permission{
+	{Code: "project.manage", Name: "管理全部项目", Module: "项目", Sort: 21},
+}
+
+// seedPermissions 写入新增的权限并授予指定角色 (已存在的编码跳过，可重复执行)
+func seedPermissions(tx *gorm.DB, permissions []models.Permission, roles ...string) error {
+	var roleIDs []int64
+	if err := tx.Model(&models.Role{}).Where("code IN ?", roles).Pluck("id", &roleIDs).Error; err != nil {
+		return err
+	}
+	for _, p := range permissions {
+		if err := tx.Where(models.Permission{Code: p.Code}).FirstOrCreate(&p).Error; err != nil {
+			return err
+		}
+		for _, roleID := range roleIDs {
+			link := models.RolePermission{RoleID: roleID, PermissionID: p.ID}
+			if err := tx.Where(link).FirstOrCreate(&link).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// dropPermissions 删除权限及其与角色的关联 (回滚新增权限的迁移时使用)
+func dropPermissions(tx *gorm.DB, permissions []models.Permission) error {
+	codes := make([]string, 0, len(permissions))
+	for _, p := range permissions {
+		codes = append(codes, p.Code)
+	}
+	ids := tx.Model(&models.Permission{}).Select("id").Where("code IN ?", codes)
+	if err := tx.Where("permission_id IN (?)", ids).Delete(&models.RolePermission{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("code IN ?", codes).Delete(&models.Permission{}).Error
+}
+
+// revokePermissions 收回指定角色的权限 (权限本身保留)
+func revokePermissions(tx *gorm.DB, codes []string, roles ...string) error {
+	permissionIDs := tx.Model(&models.Permission{}).Select("id").Where("code IN ?", codes)
+	roleIDs := tx.Model(&models.Role{}).Select("id").Where("code IN ?", roles)
+	return tx.Where("permission_id IN (?) AND role_id IN (?)", permissionIDs, roleIDs).Delete(&models.RolePermission{}).Error
+}
+
+// v11Role 迁移 11 写入的内置角色，Permissions 为空表示拥有全部权限
+type v11Role struct {
+	Code        string
+	Name        string
+	Description string
+	Permissions []string
+}
+
+// seedRoles 写入权限与内置角色 (已存在的编码跳过，可重复执行)
+func seedRoles(tx *gorm.DB, permissions []models.Permission, roles []v11Role) error {
+	ids := make(map[string]int64, len(permissions))
+	for i, p := range permissions {
+		p.Sort = i + 1
+		if err := tx.Where(models.Permission{Code: p.Code}).FirstOrCreate(&p).Error; err != nil {
+			return err
+		}
+		ids[p.Code] = p.ID
+	}
+
+	for _, r := range roles {
+		role := models.Role{Code: r.Code, Name: r.Name, Description: r.Description, BuiltIn: true}
+		if err := tx.Where(models.Role{Code: r.Code}).FirstOrCreate(&role).Error; err != nil {
+			return err
+		}
+		codes := r.Permissions
+		if len(codes) == 0 {
+			for _, p := range permissions {
+				codes = append(codes, p.Code)
+			}
+		}
+		for _, code := range codes {
+			link := models.RolePermission{RoleID: role.ID, PermissionID: ids[code]}
+			if err := tx.Where(link).FirstOrCreate(&link).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// baselineModels 基线迁移包含的表 (按依赖顺序)
+func baselineModels() []interface{} {
+	return []interface{}{
+		&v1User{},
+		&v1Project{},
+		&v1Payment{},
+		&v1Dictionary{},
+		&v1DictionaryItem{},
+		&v1Notification{},
+		&v1UserNotification{},
+		&v1SyncState{},
+		&v1SyncTombstone{},
+		&v1SyncRun{},
+		&v1SyncProfile{},
+	}
+}
+
+// v1User 基线迁移时的 users 表结构
+type v1User struct {
+	ID            int64  `gorm:"primaryKey;autoIncrement"`
+	Username      string `gorm:"size:50;not null;uniqueIndex"`
+	Password      string `gorm:"size:100;not null"`
+	Name          string `gorm:"size:50;not null"`
+	Email         string `gorm:"size:100"`
+	Phone         string `gorm:"size:20"`
+	Avatar        string `gorm:"size:255"`
+	Role          string `gorm:"size:20;not null;default:'user'"`
+	Department    string `gorm:"size:50"`
+	Position      string `gorm:"size:50"`
+	Status        int    `gorm:"default:1"`
+	LastLoginTime *time.Time
+	CreateTime    time.Time `gorm:"autoCreateTime"`
+	UpdateTime    time.Time `gorm:"autoUpdateTime"`
+}
+
+// TableName 指定表名
+func (v1User) TableName() string {
+	return "users"
+}
+
+// v1Project 基线迁移时的 projects 表结构
+type v1Project struct {
+	ID             int64      `gorm:"primaryKey;autoIncrement"`
+	Name           string     `gorm:"size:100;not null"`
+	Company        string     `gorm:"size:100;not null"`
+	TotalAmount    float64    `gorm:"type:real;not null"`
+	ReceivedAmount float64    `gorm:"type:real;default:0"`
+	Status         string     `gorm:"size:20;not null"`
+	Type           string     `gorm:"size:50;not null"`
+	ContractNumber string     `gorm:"size:50"`
+	ContractDate   *time.Time `gorm:"type:date"`
+	PaymentMethod  string     `gorm:"size:30"`
+	StartDate      time.Time  `gorm:"type:date;not null"`
+	EndDate        time.Time  `gorm:"type:date;not null"`
+	Description    string
+	UserID         int64     `gorm:"not null;index"`
+	CreateTime     time.Time `gorm:"autoCreateTime"`
+	UpdateTime     time.Time `gorm:"autoUpdateTime"`
+}
+
+// TableName 指定表名
+func (v1Project) TableName() string {
+	return "projects"
+}
+
+// v1Payment 基线迁移时的 payments 表结构
+type v1Payment struct {
+	ID         int64      `gorm:"primaryKey;autoIncrement"`
+	ProjectID  int64      `gorm:"not null;index"`
+	Stage      string     `gorm:"size:50;not null"`
+	Amount     float64    `gorm:"type:real;not null"`
+	Percentage float64    `gorm:"type:real"`
+	PlanDate   time.Time  `gorm:"type:date;not null;index"`
+	Status     string     `gorm:"size:20;not null;index"`
+	ActualDate *time.Time `gorm:"type:date"`
+	Method     string     `gorm:"size:30"`
+	Remark     string     `gorm:"size:255"`
+	UserID     int64      `gorm:"not null"`
+	CreateTime time.Time  `gorm:"autoCreateTime"`
+	UpdateTime time.Time  `gorm:"autoUpdateTime"`
+}
+
+// TableName 指定表名
+func (v1Payment) TableName() string {
+	return "payments"
+}
+
+// v1Dictionary 基线迁移时的 dictionaries 表结构
+type v1Dictionary struct {
+	ID         int64     `gorm:"primaryKey;autoIncrement"`
+	Code       string    `gorm:"size:50;not null;uniqueIndex"`
+	Name       string    `gorm:"size:50;not null"`
+	Status     int       `gorm:"default:1"`
+	Remark     string    `gorm:"size:255"`
+	CreateTime time.Time `gorm:"autoCreateTime"`
+	UpdateTime time.Time `gorm:"autoUpdateTime"`
+}
+
+// TableName 指定表名
+func (v1Dictionary) TableName() string {
+	return "dictionaries"
+}
+
+// v1DictionaryItem 基线迁移时的 dictionary_item 表结构
+type v1DictionaryItem struct {
+	ID           int64     `gorm:"primaryKey;autoIncrement"`
+	DictionaryID int64     `gorm:"not null;index"`
+	Label        string    `gorm:"size:50;not null"`
+	Value        string    `gorm:"size:50;not null"`
+	Sort         int       `gorm:"default:0"`
+	Status       int       `gorm:"default:1"`
+	Remark       string    `gorm:"size:255"`
+	CreateTime   time.Time `gorm:"autoCreateTime"`
+	UpdateTime   time.Time `gorm:"autoUpdateTime"`
+}
+
+// TableName 指定表名
+func (v1DictionaryItem) TableName() string {
+	return "dictionary_item"
+}
+
+// v1Notification 基线迁移时的 notifications 表结构
+type v1Notification struct {
+	ID         int64     `gorm:"primaryKey;autoIncrement"`
+	Title      string    `gorm:"size:100;not null"`
+	Content    string    `gorm:"not null"`
+	Type       int       `gorm:"default:1"`
+	SenderID   int64     `gorm:"not null;index"`
+	IsGlobal   int       `gorm:"default:0"`
+	CreateTime time.Time `gorm:"autoCreateTime"`
+	UpdateTime time.Time `gorm:"autoUpdateTime"`
+	IsRead     bool      `gorm:"->"`
+}
+
+// TableName 指定表名
+func (v1Notification) TableName() string {
+	return "notifications"
+}
+
+// v1UserNotification 基线迁移时的 user_notifications 表结构
+type v1UserNotification struct {
+	ID             int64 `gorm:"primaryKey;autoIncrement"`
+	UserID         int64 `gorm:"not null;uniqueIndex:idx_user_notification"`
+	NotificationID int64 `gorm:"not null;uniqueIndex:idx_user_notification"`
+	IsRead         int   `gorm:"default:0"`
+	ReadTime       *time.Time
+	UpdateTime     time.Time `gorm:"autoUpdateTime"`
+}
+
+// TableName 指定表名
+func (v1UserNotification) TableName() string {
+	return "user_notifications"
+}
+
+// v1SyncState 基线迁移时的 sync_states 表结构
+type v1SyncState struct {
+	ID                int64  `gorm:"primaryKey;autoIncrement"`
+	Target            string `gorm:"size:255;not null;uniqueIndex:idx_sync_state_target_table"`
+	Table             string `gorm:"column:table_name;size:50;not null;uniqueIndex:idx_sync_state_target_table"`
+	LastUpdateTime    *time.Time
+	LastTombstoneTime *time.Time
+	LastRemoteUpdate  *time.Time
+	LastRemoteDelete  *time.Time
+	LastSyncTime      *time.Time
+	UpdateTime        time.Time `gorm:"autoUpdateTime"`
+}
+
+// TableName 指定表名
+func (v1SyncState) TableName() string {
+	return "sync_states"
+}
+
+// v1SyncTombstone 基线迁移时的 sync_tombstones 表结构
+type v1SyncTombstone struct {
+	ID         int64     `gorm:"primaryKey;autoIncrement"`
+	Table      string    `gorm:"column:table_name;size:50;not null;uniqueIndex:idx_sync_tombstone_record"`
+	RecordID   int64     `gorm:"not null;uniqueIndex:idx_sync_tombstone_record"`
+	DeleteTime time.Time `gorm:"not null;index"`
+}
+
+// TableName 指定表名
+func (v1SyncTombstone) TableName() string {
+	return "sync_tombstones"
+}
+
+// v1SyncRun 基线迁移时的 sync_runs 表结构
+type v1SyncRun struct {
+	ID            int64     `gorm:"primaryKey;autoIncrement"`
+	Trigger       string    `gorm:"size:20;not null"`
+	Mode          string    `gorm:"size:20;not null"`
+	Tables        string    `gorm:"type:text"`
+	Status        string    `gorm:"size:20;not null;index"`
+	Attempts      int       `gorm:"default:1"`
+	SyncedCount   int64     `gorm:"default:0"`
+	PulledCount   int64     `gorm:"default:0"`
+	DeletedCount  int64     `gorm:"default:0"`
+	ConflictCount int64     `gorm:"default:0"`
+	ErrorMessage  string    `gorm:"type:text"`
+	Detail        string    `gorm:"type:text"`
+	StartTime     time.Time `gorm:"not null;index"`
+	FinishTime    *time.Time
+}
+
+// TableName 指定表名
+func (v1SyncRun) TableName() string {
+	return "sync_runs"
+}
+
+// v1SyncProfile 基线迁移时的 sync_profiles 表结构
+type v1SyncProfile struct {
+	ID         int64  `gorm:"primaryKey;autoIncrement"`
+	Name       string `gorm:"size:100;uniqueIndex;not null"`
+	DBType     string `gorm:"size:20;not null"`
+	Path       string `gorm:"size:500"`
+	Host       string `gorm:"size:255"`
+	Port       int
+	User       string `gorm:"size:100"`
+	Password   string `gorm:"type:text"`
+	DBName     string `gorm:"size:100"`
+	SSLMode    string `gorm:"size:20"`
+	CreatedBy  int64
+	CreateTime time.Time `gorm:"autoCreateTime"`
+	UpdateTime time.Time `gorm:"autoUpdateTime"`
+}
+
+// TableName 指定表名
+func (v1SyncProfile) TableName() string {
+	return "sync_profiles"
+}
@@ -28,11 +28,14 @@ func Seed(db *gorm.DB) error {
 		// 包含:
 		// - 默认管理员 (admin / 123456)
 		// - 演示用户 (xu)
+		// - 默认部门 (技术部) 与职位
 		usersSQL := []string{
-			`INSERT INTO users (id, username, password, name, email, phone, role, department, position, status, create_time) VALUES 
-(1, 'admin', '$2a$10$sp/NLWYRQjt9zVCq6HeOieFaFNBl79RoBXdePqxg9UhwQyT1/C7vu', '管理员', 'admin@orange.com', '13800000000', 'admin', '技术部', '系统管理员', 1, CURRENT_TIMESTAMP);`,
-			`INSERT INTO users (id, username, password, name, email, phone, role, department, position, status, create_time) VALUES 
-(2, 'xu', '$2a$10$sp/NLWYRQjt9zVCq6HeOieFaFNBl79RoBXdePqxg9UhwQyT1/C7vu', '郑旭', 'xu@company.com', '13800000001', 'user', '技术部', '项目经理', 1, CURRENT_TIMESTAMP);`,
+			`INSERT INTO departments (id, name, parent_id, manager_id, sort, create_time) VALUES (1, '技术部', 0, 1, 1, CURRENT_TIMESTAMP);`,
+			`INSERT INTO positions (id, name, sort, create_time) VALUES (1, '系统管理员', 1, CURRENT_TIMESTAMP), (2, '项目经理', 2, CURRENT_TIMESTAMP);`,
+			`INSERT INTO users (id, username, password, name, email, phone, role, department, department_id, position, position_id, status, create_time) VALUES 
+(1, 'admin', '$2a$10$sp/NLWYRQjt9zVCq6HeOieFaFNBl79RoBXdePqxg9UhwQyT1/C7vu', '管理员', 'admin@orange.com', '13800000000', 'admin', '技术部', 1, '系统管理员', 1, 1, CURRENT_TIMESTAMP);`,
+			`INSERT INTO users (id, username, password, name, email, phone, role, department, department_id, position, position_id, status, create_time) VALUES 
+(2, 'xu', '$2a$10$sp/NLWYRQjt9zVCq6HeOieFaFNBl79RoBXdePqxg9UhwQyT1/C7vu', '郑旭', 'xu@company.com', '13800000001', 'user', '技术部', 1, '项目经理', 2, 1, CURRENT_TIMESTAMP);`,
 		}
 
 		for _, sql := range usersSQL {
@@ -0,0 +1,93 @@
+package database
+
+import (
+	"reflect"
+
+	"github.com/FruitsAI/Orange/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// trackedTables 需要记录删除墓碑的业务表 (即参与云端同步的表)
+var trackedTables = map[string]bool{
+	"departments":        true,
+	"positions":          true,
+	"users":              true,
+	"projects":           true,
+	"project_members":    true,
+	"payments":           true,
+	"dictionaries":       true,
+	"dictionary_item":    true,
+	"notifications":      true,
+	"user_notifications": true,
+}
+
+// registerTombstoneCallback 注册删除墓碑回调
+// 在 GORM 执行 DELETE 之前查出即将被删除的记录 ID，写入 sync_tombstones 表，
+// 供增量同步将删除操作推送到云端。墓碑与删除语句使用同一连接 (同一事务)。
+func registerTombstoneCallback(db *gorm.DB) error {
+	return db.Callback().Delete().Before("gorm:delete").Register("orange:sync_tombstone", recordTombstones)
+}
+
+// recordTombstones 记录即将被删除的记录
+func recordTombstones(db *gorm.DB) {
+	stmt := db.Statement
+	if db.Error != nil || stmt.Schema == nil || !trackedTables[stmt.Table] {
+		return
+	}
+
+	// 按删除语句的条件查出受影响的记录 ID
+	query := db.Session(&gorm.Session{NewDB: true}).Model(stmt.Model)
+	hasCondition := false
+
+	// 1. 模型自身携带的主键 (如 tx.Delete(&payment))
+	if pk := stmt.Schema.PrioritizedPrimaryField; pk != nil && stmt.ReflectValue.Kind() == reflect.Struct {
+		if value, isZero := pk.ValueOf(stmt.Context, stmt.ReflectValue); !isZero {
+			query = query.Where(pk.DBName+" = ?", value)
+			hasCondition = true
+		}
+	}
+
+	// 2. Where 条件 (如 tx.Delete(&models.Project{}, id) 或 tx.Where(...).Delete(...))
+	if c, ok := stmt.Clauses["WHERE"]; ok {
+		if where, ok := c.Expression.(clause.Where); ok && len(where.Exprs) > 0 {
+			query = query.Clauses(where)
+			hasCondition = true
+		}
+	}
+
+	// 无条件删除会被 GORM 拒绝，此处无需记录
+	if !hasCondition {
+		return
+	}
+
+	var ids []int64
+	if err := query.Pluck("id", &ids).Error; err != nil {
+		db.AddError(err)
+		return
+	}
+	if len(ids) == 0 {
+		return
+	}
+
+	now := db.NowFunc()
+	tombstones := make([]models.SyncTombstone, 0, len(ids))
+	for _, id := range ids {
+		tombstones = append(tombstones, models.SyncTombstone{
+			Table:      stmt.Table,
+			RecordID:   id,
+			DeleteTime: now,
+		})
+	}
+
+	// 同一记录重复删除 (如 ID 被复用) 时刷新删除时间
+	err := db.Session(&gorm.Session{NewDB: true}).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "table_name"}, {Name: "record_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"delete_time"}),
+		}).
+		Create(&tombstones).Error
+	if err != nil {
+		db.AddError(err)
+	}
+}
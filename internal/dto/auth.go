@@ -4,14 +4,116 @@ import "github.com/FruitsAI/Orange/internal/models"
 
 // LoginRequest 登录请求
 type LoginRequest struct {
-	Username string `json:"username" binding:"required"`
-	Password string `json:"password" binding:"required"`
+	Username   string `json:"username" binding:"required"`
+	Password   string `json:"password" binding:"required"`
+	DeviceName string `json:"device_name"` // 设备名称 (可选，为空时根据 User-Agent 推断)
+}
+
+// ClientInfo 登录客户端信息 (记录到登录会话)
+type ClientInfo struct {
+	DeviceName string
+	ClientIP   string
+	UserAgent  string
+}
+
+// SessionView 登录会话 (含是否为当前会话)
+type SessionView struct {
+	models.UserSession
+	Current bool `json:"current"` // 是否为发起请求的会话
 }
 
 // LoginResult 登录结果
+// 已启用两步验证时密码验证通过后只返回 TwoFactorRequired 与 ChallengeToken，
+// 凭挑战令牌提交验证码后才签发令牌。
 type LoginResult struct {
-	Token string       `json:"token"`
-	User  *models.User `json:"user"`
+	Token                  string       `json:"token,omitempty"`                     // 访问令牌 (JWT)
+	RefreshToken           string       `json:"refresh_token,omitempty"`             // 刷新令牌
+	ExpiresIn              int64        `json:"expires_in"`                          // 访问令牌 (或挑战令牌) 有效期 (秒)
+	User                   *models.User `json:"user,omitempty"`                      // 用户信息
+	TwoFactorRequired      bool         `json:"two_factor_required,omitempty"`       // 需要提交两步验证码
+	ChallengeToken         string       `json:"challenge_token,omitempty"`           // 两步验证挑战令牌
+	TwoFactorSetupRequired bool         `json:"two_factor_setup_required,omitempty"` // 需先启用两步验证才能使用其他功能
+	PasswordChangeRequired bool         `json:"password_change_required,omitempty"`  // 密码已过期，需先修改密码才能使用其他功能
+	Permissions            []string     `json:"permissions,omitempty"`               // 当前角色拥有的权限编码
+}
+
+// TwoFactorLoginRequest 两步验证登录请求
+// Code 为身份验证器中的 6 位验证码，或一个未使用的恢复码。
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+}
+
+// TwoFactorCodeRequest 提交验证码的请求 (启用两步验证、重新生成恢复码)
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// DisableTwoFactorRequest 关闭两步验证请求
+type DisableTwoFactorRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+// TwoFactorStatus 两步验证状态
+type TwoFactorStatus struct {
+	Enabled                bool  `json:"enabled"`                  // 是否已启用
+	Required               bool  `json:"required"`                 // 当前角色是否被要求启用
+	RecoveryCodesRemaining int64 `json:"recovery_codes_remaining"` // 剩余可用恢复码数量
+}
+
+// TwoFactorSetup 两步验证绑定信息
+type TwoFactorSetup struct {
+	Secret     string `json:"secret"`      // Base32 密钥 (无法扫码时手动输入)
+	OtpauthURI string `json:"otpauth_uri"` // otpauth URI，用于生成二维码
+}
+
+// RecoveryCodesResult 新生成的恢复码 (仅展示一次)
+type RecoveryCodesResult struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// OIDCInfo 单点登录配置 (登录页据此显示单点登录按钮)
+type OIDCInfo struct {
+	Enabled     bool   `json:"enabled"`      // 是否已启用 OIDC 单点登录
+	DisplayName string `json:"display_name"` // 按钮上显示的身份提供方名称
+}
+
+// OIDCTicketRequest 凭单点登录回调返回的一次性票据换取令牌
+type OIDCTicketRequest struct {
+	Ticket     string `json:"ticket" binding:"required"`
+	DeviceName string `json:"device_name"` // 设备名称 (可选，为空时根据 User-Agent 推断)
+}
+
+// CreatePersonalTokenRequest 创建个人访问令牌请求
+type CreatePersonalTokenRequest struct {
+	Name          string   `json:"name" binding:"required,max=100"`
+	Scopes        []string `json:"scopes" binding:"required,min=1"`          // 授权范围，如 projects:read
+	ExpiresInDays int      `json:"expires_in_days" binding:"min=0,max=3650"` // 有效天数，0 表示永不过期
+}
+
+// PersonalTokenView 个人访问令牌 (不含令牌明文)
+type PersonalTokenView struct {
+	models.PersonalAccessToken
+	Scopes  []string `json:"scopes"`  // 授权范围
+	Expired bool     `json:"expired"` // 是否已过期
+}
+
+// PersonalTokenCreated 新创建的个人访问令牌
+type PersonalTokenCreated struct {
+	PersonalTokenView
+	Token string `json:"token"` // 令牌明文 (仅在创建时返回一次)
+}
+
+// TokenScope 个人访问令牌可申请的授权范围
+type TokenScope struct {
+	Scope       string `json:"scope"`       // 授权范围
+	Description string `json:"description"` // 说明
+}
+
+// RefreshTokenRequest 刷新令牌请求 (也用于退出登录)
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
 }
 
 // RegisterRequest 注册请求
@@ -20,20 +122,30 @@ type RegisterRequest struct {
 	Name     string `json:"name" binding:"required"`
 	Email    string `json:"email"`
 	Phone    string `json:"phone"`
-	Password string `json:"password" binding:"required,min=6"`
+	Password string `json:"password" binding:"required"` // 需符合密码策略
 }
 
 // UpdateProfileRequest 更新个人信息请求
+// 部门与职位由管理员在用户管理中分配，个人资料不可修改。
 type UpdateProfileRequest struct {
-	Name       string `json:"name"`
-	Email      string `json:"email"`
-	Phone      string `json:"phone"`
-	Department string `json:"department"`
-	Position   string `json:"position"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Phone string `json:"phone"`
+}
+
+// ForgotPasswordRequest 找回密码请求
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email,max=100"`
+}
+
+// ResetPasswordByTokenRequest 通过邮件中的重置令牌设置新密码
+type ResetPasswordByTokenRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"` // 需符合密码策略
 }
 
 // ChangePasswordRequest 修改密码请求
 type ChangePasswordRequest struct {
 	OldPassword string `json:"old_password" binding:"required"`
-	NewPassword string `json:"new_password" binding:"required,min=6"`
+	NewPassword string `json:"new_password" binding:"required"` // 需符合密码策略
 }
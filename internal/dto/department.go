@@ -0,0 +1,19 @@
+package dto
+
+import "github.com/FruitsAI/Orange/internal/models"
+
+// DepartmentRequest 创建/更新部门请求
+type DepartmentRequest struct {
+	Name      string `json:"name" binding:"required,max=50"` // 部门名称 (同一上级下唯一)
+	ParentID  int64  `json:"parent_id"`                      // 上级部门ID，0 表示顶级部门
+	ManagerID int64  `json:"manager_id"`                     // 负责人用户ID，0 表示不指定
+	Sort      int    `json:"sort"`                           // 排序
+}
+
+// DepartmentNode 部门树节点
+type DepartmentNode struct {
+	models.Department
+	ManagerName string           `json:"manager_name"` // 负责人姓名
+	UserCount   int64            `json:"user_count"`   // 直属成员数 (不含下级部门)
+	Children    []DepartmentNode `json:"children"`     // 下级部门
+}
@@ -0,0 +1,16 @@
+package dto
+
+import "github.com/FruitsAI/Orange/internal/models"
+
+// PositionRequest 创建/更新职位请求
+type PositionRequest struct {
+	Name        string `json:"name" binding:"required,max=50"` // 职位名称 (唯一)
+	Description string `json:"description" binding:"max=255"`  // 描述
+	Sort        int    `json:"sort"`                           // 排序
+}
+
+// PositionView 职位详情 (含使用人数)
+type PositionView struct {
+	models.Position
+	UserCount int64 `json:"user_count"` // 担任该职位的用户数
+}
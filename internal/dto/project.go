@@ -25,3 +25,25 @@ type CreateProjectRequest struct {
 	Description    string  `json:"description"`
 	UserID         int64   `json:"-"`
 }
+
+// ProjectDetail 项目详情 (含当前用户的项目角色)
+type ProjectDetail struct {
+	models.Project
+	MyRole string `json:"my_role"` // 当前用户的项目角色: owner, editor, finance, viewer
+}
+
+// ProjectMemberRequest 添加项目成员请求
+type ProjectMemberRequest struct {
+	UserID int64  `json:"user_id" binding:"required"` // 成员用户ID
+	Role   string `json:"role" binding:"required"`    // 项目角色: editor, viewer, finance
+}
+
+// ProjectMemberRoleRequest 修改项目成员角色请求
+type ProjectMemberRoleRequest struct {
+	Role string `json:"role" binding:"required"` // 项目角色: editor, viewer, finance
+}
+
+// TransferProjectRequest 转让项目请求
+type TransferProjectRequest struct {
+	UserID int64 `json:"user_id" binding:"required"` // 新负责人用户ID
+}
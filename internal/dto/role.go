@@ -0,0 +1,26 @@
+package dto
+
+import "github.com/FruitsAI/Orange/internal/models"
+
+// RoleRequest 创建/更新角色请求
+// 更新时 Code 不可修改 (忽略)；Permissions 为完整的权限编码列表，会替换角色原有权限。
+type RoleRequest struct {
+	Code        string   `json:"code" binding:"max=20"`          // 角色编码 (小写字母、数字与下划线)
+	Name        string   `json:"name" binding:"required,max=50"` // 角色名称
+	Description string   `json:"description" binding:"max=255"`  // 描述
+	DataScope   string   `json:"data_scope"`                     // 数据范围: self, department, department_and_children, all (为空时创建为 self、更新不修改)
+	Permissions []string `json:"permissions"`                    // 权限编码列表
+}
+
+// RoleView 角色详情 (含权限与使用人数)
+type RoleView struct {
+	models.Role
+	Permissions []string `json:"permissions"` // 权限编码列表
+	UserCount   int64    `json:"user_count"`  // 使用该角色的用户数
+}
+
+// CurrentUser 当前登录用户信息 (含权限)
+type CurrentUser struct {
+	*models.User
+	Permissions []string `json:"permissions"` // 当前角色拥有的权限编码
+}
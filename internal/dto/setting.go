@@ -0,0 +1,9 @@
+package dto
+
+import "github.com/FruitsAI/Orange/internal/pkg/password"
+
+// SecuritySettings 安全设置 (管理员维护)
+type SecuritySettings struct {
+	RequireAdminTwoFactor bool            `json:"require_admin_2fa"` // 管理员必须启用两步验证
+	PasswordPolicy        password.Policy `json:"password_policy"`   // 密码策略
+}
@@ -0,0 +1,15 @@
+package dto
+
+// SyncProfileRequest 新增/更新同步连接配置请求
+// sqlite 类型只需 Path，其余类型需填写主机、端口、用户名及数据库名；更新时 Password 为空表示保留原密码。
+type SyncProfileRequest struct {
+	Name     string `json:"name" binding:"required"`
+	DBType   string `json:"db_type" binding:"required"`
+	Path     string `json:"path"`
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+	User     string `json:"user"`
+	Password string `json:"password"`
+	DBName   string `json:"db_name"`
+	SSLMode  string `json:"ssl_mode"`
+}
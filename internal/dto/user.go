@@ -4,28 +4,30 @@ import "github.com/FruitsAI/Orange/internal/models"
 
 // CreateUserRequest 管理员创建用户请求
 type CreateUserRequest struct {
-	Username string `json:"username" binding:"required"`
-	Name     string `json:"name" binding:"required"`
-	Email    string `json:"email"`
-	Phone    string `json:"phone"`
-	Password string `json:"password" binding:"required,min=6"`
-	Role     string `json:"role"` // admin or user
+	Username     string `json:"username" binding:"required"`
+	Name         string `json:"name" binding:"required"`
+	Email        string `json:"email"`
+	Phone        string `json:"phone"`
+	Password     string `json:"password" binding:"required"` // 需符合密码策略
+	Role         string `json:"role"`                        // 角色编码，为空表示普通用户
+	DepartmentID int64  `json:"department_id"`               // 所属部门ID，0 表示未分配
+	PositionID   int64  `json:"position_id"`                 // 职位ID，0 表示未分配
 }
 
 // UpdateUserRequest 管理员更新用户请求
 type UpdateUserRequest struct {
-	Name       string `json:"name"`
-	Email      string `json:"email"`
-	Phone      string `json:"phone"`
-	Department string `json:"department"`
-	Position   string `json:"position"`
-	Role       string `json:"role"`
-	Status     int    `json:"status"` // 1: active, 0: disabled
+	Name         string `json:"name"`
+	Email        string `json:"email"`
+	Phone        string `json:"phone"`
+	DepartmentID *int64 `json:"department_id"` // 所属部门ID，不传表示不修改，0 表示清除
+	PositionID   *int64 `json:"position_id"`   // 职位ID，不传表示不修改，0 表示清除
+	Role         string `json:"role"`
+	Status       int    `json:"status"` // 1: active, 0: disabled
 }
 
 // ResetPasswordRequest 管理员重置密码
 type ResetPasswordRequest struct {
-	NewPassword string `json:"new_password" binding:"required,min=6"`
+	NewPassword string `json:"new_password" binding:"required"` // 需符合密码策略
 }
 
 // UserPageResult 用户分页结果
@@ -33,3 +35,9 @@ type UserPageResult struct {
 	List  []models.User `json:"list"`
 	Total int64         `json:"total"`
 }
+
+// AuditLogPageResult 审计日志分页结果
+type AuditLogPageResult struct {
+	List  []models.AuditLog `json:"list"`
+	Total int64             `json:"total"`
+}
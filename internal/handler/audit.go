@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/FruitsAI/Orange/internal/pkg/response"
+	"github.com/FruitsAI/Orange/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// AuditHandler 安全审计日志接口处理器 (需要 audit.view 权限)
+type AuditHandler struct {
+	auditService *service.AuditService
+}
+
+// NewAuditHandler 创建审计日志处理器实例
+func NewAuditHandler() *AuditHandler {
+	return &AuditHandler{
+		auditService: service.NewAuditService(),
+	}
+}
+
+// List 分页查询审计日志
+// @Summary 审计日志列表
+// @Tags Audit
+// @Security Bearer
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Param action query string false "事件类型"
+// @Param user_id query int false "用户ID"
+// @Success 200 {object} dto.AuditLogPageResult
+// @Router /api/v1/audit-logs [get]
+func (h *AuditHandler) List(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	userID, _ := strconv.ParseInt(c.Query("user_id"), 10, 64)
+
+	result, err := h.auditService.List(page, pageSize, c.Query("action"), userID)
+	if err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+
+	response.Success(c, result)
+}
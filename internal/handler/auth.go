@@ -1,7 +1,12 @@
 package handler
 
 import (
+	"errors"
+	"strconv"
+
 	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/middleware"
+	"github.com/FruitsAI/Orange/internal/pkg/password"
 	"github.com/FruitsAI/Orange/internal/pkg/response"
 	"github.com/FruitsAI/Orange/internal/service"
 	"github.com/gin-gonic/gin"
@@ -22,7 +27,7 @@ func NewAuthHandler() *AuthHandler {
 
 // Login 用户登录接口
 // @Summary 用户登录
-// @Description 验证用户名密码并返回JWT Token
+// @Description 验证用户名密码，返回访问令牌 (JWT) 与刷新令牌
 // @Tags Auth
 // @Accept json
 // @Produce json
@@ -38,17 +43,39 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	}
 
 	// 2. 调用服务层登录逻辑
-	result, err := h.authService.Login(req.Username, req.Password)
+	result, err := h.authService.Login(req.Username, req.Password, clientInfo(c, req.DeviceName))
+	if err != nil {
+		response.Error(c, response.CodeUnauthorized, err.Error())
+		return
+	}
+
+	// 3. 返回令牌及用户信息
+	response.Success(c, result)
+}
+
+// Refresh 刷新令牌接口
+// @Summary 刷新令牌
+// @Description 使用刷新令牌换取新的访问令牌与刷新令牌，旧刷新令牌随即失效
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param refresh body dto.RefreshTokenRequest true "刷新令牌"
+// @Success 200 {object} dto.LoginResult
+// @Router /api/v1/auth/refresh [post]
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req dto.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, "刷新令牌不能为空")
+		return
+	}
+
+	result, err := h.authService.Refresh(req.RefreshToken, clientInfo(c, ""))
 	if err != nil {
 		response.Error(c, response.CodeUnauthorized, err.Error())
 		return
 	}
 
-	// 3. 返回 Token 及用户信息
-	response.Success(c, gin.H{
-		"token": result.Token,
-		"user":  result.User,
-	})
+	response.Success(c, result)
 }
 
 // Register 用户注册接口
@@ -69,7 +96,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 
 	err := h.authService.Register(req)
 	if err != nil {
-		response.ParamError(c, err.Error())
+		passwordError(c, err)
 		return
 	}
 
@@ -78,10 +105,20 @@ func (h *AuthHandler) Register(c *gin.Context) {
 
 // Logout 退出登录
 // @Summary 退出登录
-// @Description 客户端登出（当前由前端清除Token，后端仅做预留）
+// @Description 吊销刷新令牌所属的登录会话，该会话的访问令牌同时失效
 // @Tags Auth
+// @Param refresh body dto.RefreshTokenRequest false "刷新令牌"
 // @Router /api/v1/auth/logout [post]
 func (h *AuthHandler) Logout(c *gin.Context) {
+	// 访问令牌可能已过期，通过刷新令牌识别用户；未携带时仅由客户端清除本地令牌
+	var req dto.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err == nil {
+		if err := h.authService.Logout(req.RefreshToken); err != nil {
+			response.InternalError(c, "退出失败")
+			return
+		}
+	}
+
 	response.SuccessWithMessage(c, "退出成功", nil)
 }
 
@@ -90,7 +127,7 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 // @Description 获取当前登录用户的详细资料
 // @Tags User
 // @Security Bearer
-// @Success 200 {object} models.User
+// @Success 200 {object} dto.CurrentUser
 // @Router /api/v1/users/me [get]
 func (h *AuthHandler) GetCurrentUser(c *gin.Context) {
 	userID := c.GetInt64("user_id")
@@ -129,7 +166,7 @@ func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 		return
 	}
 
-	user, err := h.authService.UpdateProfile(userID, req.Name, req.Email, req.Phone, req.Department, req.Position)
+	user, err := h.authService.UpdateProfile(userID, req.Name, req.Email, req.Phone)
 	if err != nil {
 		response.InternalError(c, err.Error())
 		return
@@ -140,11 +177,11 @@ func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 
 // ChangePassword 修改密码
 // @Summary 修改密码
-// @Description 修改当前登录用户的密码
+// @Description 修改当前登录用户的密码，其他设备上的登录全部失效，并为当前客户端返回新令牌
 // @Tags User
 // @Security Bearer
 // @Param password body dto.ChangePasswordRequest true "密码修改参数"
-// @Success 200 {string} string "此处返回成功消息"
+// @Success 200 {object} dto.LoginResult
 // @Router /api/v1/users/me/password [put]
 func (h *AuthHandler) ChangePassword(c *gin.Context) {
 	userID := c.GetInt64("user_id")
@@ -159,10 +196,94 @@ func (h *AuthHandler) ChangePassword(c *gin.Context) {
 		return
 	}
 
-	if err := h.authService.ChangePassword(userID, req.OldPassword, req.NewPassword); err != nil {
-		response.ParamError(c, err.Error())
+	result, err := h.authService.ChangePassword(userID, req.OldPassword, req.NewPassword, clientInfo(c, ""))
+	if err != nil {
+		passwordError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "密码修改成功", result)
+}
+
+// ListSessions 获取当前用户的登录会话
+// @Summary 我的登录会话
+// @Description 列出当前用户在各设备上的有效登录，current 标记发起请求的会话
+// @Tags User
+// @Security Bearer
+// @Success 200 {array} dto.SessionView
+// @Router /api/v1/users/me/sessions [get]
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	userID := c.GetInt64("user_id")
+	if userID == 0 {
+		response.Unauthorized(c)
+		return
+	}
+
+	sessions, err := h.authService.ListSessions(userID, middleware.GetSessionID(c))
+	if err != nil {
+		response.InternalError(c, "获取登录会话失败")
+		return
+	}
+
+	response.Success(c, sessions)
+}
+
+// RevokeSession 吊销当前用户的指定登录会话
+// @Summary 下线登录会话
+// @Description 吊销指定会话，对应设备需重新登录；吊销当前会话等同于退出登录
+// @Tags User
+// @Security Bearer
+// @Param sid path int true "会话ID"
+// @Router /api/v1/users/me/sessions/{sid} [delete]
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	userID := c.GetInt64("user_id")
+	if userID == 0 {
+		response.Unauthorized(c)
+		return
+	}
+
+	sessionID, err := strconv.ParseInt(c.Param("sid"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的会话ID")
+		return
+	}
+
+	if err := h.authService.RevokeSession(userID, sessionID); err != nil {
+		if errors.Is(err, service.ErrSessionNotFound) {
+			response.NotFound(c, err.Error())
+			return
+		}
+		response.InternalError(c, "下线失败")
+		return
+	}
+
+	response.SuccessWithMessage(c, "已下线", nil)
+}
+
+// PasswordPolicy 获取密码策略 (注册、修改密码时展示规则)
+// @Summary 获取密码策略
+// @Tags Auth
+// @Success 200 {object} password.Policy
+// @Router /api/v1/auth/password-policy [get]
+func (h *AuthHandler) PasswordPolicy(c *gin.Context) {
+	response.Success(c, h.authService.PasswordPolicy())
+}
+
+// passwordError 输出设置密码失败的错误，不符合密码策略时在 data.violations 中列出未满足的规则
+func passwordError(c *gin.Context, err error) {
+	var policyErr *password.PolicyError
+	if errors.As(err, &policyErr) {
+		response.ErrorWithData(c, response.CodeParamError, err.Error(), gin.H{"violations": policyErr.Violations})
 		return
 	}
+	response.ParamError(c, err.Error())
+}
 
-	response.SuccessWithMessage(c, "密码修改成功", nil)
+// clientInfo 从请求中提取客户端信息
+func clientInfo(c *gin.Context, deviceName string) dto.ClientInfo {
+	return dto.ClientInfo{
+		DeviceName: deviceName,
+		ClientIP:   c.ClientIP(),
+		UserAgent:  c.Request.UserAgent(),
+	}
 }
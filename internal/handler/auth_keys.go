@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/FruitsAI/Orange/internal/pkg/jwt"
+	"github.com/FruitsAI/Orange/internal/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// JWKS 访问令牌签名公钥集
+// @Summary 签名公钥集 (JWKS)
+// @Description 返回标准 JSON Web Key Set (不使用统一响应格式)，供其他服务按 kid 校验 Orange 签发的访问令牌
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} jwk.Set
+// @Router /api/v1/auth/jwks [get]
+func (h *AuthHandler) JWKS(c *gin.Context) {
+	set, err := jwt.JWKS()
+	if err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+	// 轮换后新密钥立即加入，缓存时间不宜过长
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, set)
+}
+
+// SigningKeys 签名密钥列表 (需要 setting.manage 权限)
+// @Summary 签名密钥列表
+// @Tags System
+// @Security Bearer
+// @Success 200 {array} jwt.KeyInfo
+// @Router /api/v1/system/signing-keys [get]
+func (h *AuthHandler) SigningKeys(c *gin.Context) {
+	keys, err := h.authService.SigningKeys()
+	if err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+	response.Success(c, keys)
+}
+
+// RotateSigningKey 轮换签名密钥 (需要 setting.manage 权限)
+// @Summary 轮换签名密钥
+// @Description 生成新的签名密钥用于签发令牌，旧密钥在访问令牌有效期内继续用于校验
+// @Tags System
+// @Security Bearer
+// @Success 200 {object} jwt.KeyInfo
+// @Router /api/v1/system/signing-keys/rotate [post]
+func (h *AuthHandler) RotateSigningKey(c *gin.Context) {
+	key, err := h.authService.RotateSigningKey(c.GetInt64("user_id"))
+	if err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+	response.Success(c, key)
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// oidcLoginPage 单点登录回调后跳转的前端登录页
+const oidcLoginPage = "/login"
+
+// OIDCInfo 单点登录配置
+// @Summary 单点登录配置
+// @Description 返回是否启用 OIDC 单点登录及登录页按钮名称
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} dto.OIDCInfo
+// @Router /api/v1/auth/oidc [get]
+func (h *AuthHandler) OIDCInfo(c *gin.Context) {
+	response.Success(c, h.authService.OIDCInfo())
+}
+
+// OIDCLogin 发起单点登录
+// @Summary 发起单点登录
+// @Description 生成 state、nonce 与 PKCE 参数后重定向到身份提供方的授权页
+// @Tags Auth
+// @Success 302
+// @Router /api/v1/auth/oidc/login [get]
+func (h *AuthHandler) OIDCLogin(c *gin.Context) {
+	authURL, err := h.authService.StartOIDCLogin(c.Request.Context())
+	if err != nil {
+		redirectOIDCResult(c, "oidc_error", err.Error())
+		return
+	}
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// OIDCCallback 单点登录回调
+// @Summary 单点登录回调
+// @Description 身份提供方授权后的回调地址。校验通过后重定向到登录页并附带一次性登录票据 (oidc_ticket)，失败时附带错误信息 (oidc_error)
+// @Tags Auth
+// @Param state query string true "state"
+// @Param code query string false "授权码"
+// @Success 302
+// @Router /api/v1/auth/oidc/callback [get]
+func (h *AuthHandler) OIDCCallback(c *gin.Context) {
+	if errCode := c.Query("error"); errCode != "" {
+		message := "单点登录失败: " + errCode
+		if desc := c.Query("error_description"); desc != "" {
+			message += " (" + desc + ")"
+		}
+		redirectOIDCResult(c, "oidc_error", message)
+		return
+	}
+	state, code := c.Query("state"), c.Query("code")
+	if state == "" || code == "" {
+		redirectOIDCResult(c, "oidc_error", "单点登录回调参数缺失")
+		return
+	}
+
+	ticket, err := h.authService.CompleteOIDCLogin(c.Request.Context(), state, code, clientInfo(c, ""))
+	if err != nil {
+		redirectOIDCResult(c, "oidc_error", err.Error())
+		return
+	}
+	redirectOIDCResult(c, "oidc_ticket", ticket)
+}
+
+// OIDCToken 凭单点登录票据换取令牌
+// @Summary 单点登录换取令牌
+// @Description 提交回调返回的一次性登录票据，返回访问令牌与刷新令牌 (已启用两步验证时返回挑战令牌)
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param ticket body dto.OIDCTicketRequest true "登录票据"
+// @Success 200 {object} dto.LoginResult
+// @Router /api/v1/auth/oidc/token [post]
+func (h *AuthHandler) OIDCToken(c *gin.Context) {
+	var req dto.OIDCTicketRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, "登录票据不能为空")
+		return
+	}
+
+	result, err := h.authService.ExchangeOIDCTicket(req.Ticket, clientInfo(c, req.DeviceName))
+	if err != nil {
+		response.Error(c, response.CodeUnauthorized, err.Error())
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// redirectOIDCResult 重定向到前端登录页并附带单点登录结果
+func redirectOIDCResult(c *gin.Context, key, value string) {
+	c.Redirect(http.StatusFound, oidcLoginPage+"?"+url.Values{key: {value}}.Encode())
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// ForgotPassword 申请找回密码
+// @Summary 找回密码
+// @Description 向邮箱发送一次性重置链接 (重置码)。无论邮箱是否已注册均返回成功
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param forgot body dto.ForgotPasswordRequest true "注册邮箱"
+// @Success 200 {string} string "如果该邮箱已注册，将收到重置邮件"
+// @Router /api/v1/auth/forgot-password [post]
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req dto.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, "请输入正确的邮箱地址")
+		return
+	}
+
+	if err := h.authService.RequestPasswordReset(c.Request.Context(), req.Email, clientInfo(c, "")); err != nil {
+		response.InternalError(c, "发送失败，请稍后重试")
+		return
+	}
+
+	response.SuccessWithMessage(c, "如果该邮箱已注册，将收到重置邮件", nil)
+}
+
+// ResetPassword 凭重置令牌设置新密码
+// @Summary 重置密码
+// @Description 提交邮件中的重置码与新密码，成功后该账户的全部登录会话下线
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param reset body dto.ResetPasswordByTokenRequest true "重置码与新密码"
+// @Success 200 {string} string "密码已重置"
+// @Router /api/v1/auth/reset-password [post]
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req dto.ResetPasswordByTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, "请输入重置码和新密码")
+		return
+	}
+
+	if err := h.authService.ResetPasswordByToken(req.Token, req.NewPassword, clientInfo(c, "")); err != nil {
+		passwordError(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "密码已重置，请使用新密码登录", nil)
+}
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/pkg/response"
+	"github.com/FruitsAI/Orange/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// PersonalTokenScopes 获取可申请的授权范围
+// @Summary 访问令牌授权范围
+// @Tags User
+// @Security Bearer
+// @Success 200 {array} dto.TokenScope
+// @Router /api/v1/users/me/tokens/scopes [get]
+func (h *AuthHandler) PersonalTokenScopes(c *gin.Context) {
+	response.Success(c, h.authService.PersonalTokenScopes())
+}
+
+// ListPersonalTokens 获取当前用户的个人访问令牌
+// @Summary 个人访问令牌列表
+// @Tags User
+// @Security Bearer
+// @Success 200 {array} dto.PersonalTokenView
+// @Router /api/v1/users/me/tokens [get]
+func (h *AuthHandler) ListPersonalTokens(c *gin.Context) {
+	userID := c.GetInt64("user_id")
+	if userID == 0 {
+		response.Unauthorized(c)
+		return
+	}
+
+	tokens, err := h.authService.ListPersonalTokens(userID)
+	if err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+
+	response.Success(c, tokens)
+}
+
+// CreatePersonalToken 创建个人访问令牌
+// @Summary 创建个人访问令牌
+// @Description 令牌明文仅在本次响应中返回，请立即保存
+// @Tags User
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param token body dto.CreatePersonalTokenRequest true "令牌名称、授权范围与有效期"
+// @Success 200 {object} dto.PersonalTokenCreated
+// @Router /api/v1/users/me/tokens [post]
+func (h *AuthHandler) CreatePersonalToken(c *gin.Context) {
+	userID := c.GetInt64("user_id")
+	if userID == 0 {
+		response.Unauthorized(c)
+		return
+	}
+
+	var req dto.CreatePersonalTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, "请填写令牌名称并至少选择一个授权范围")
+		return
+	}
+
+	created, err := h.authService.CreatePersonalToken(userID, req)
+	if err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "创建成功", created)
+}
+
+// RevokePersonalToken 吊销当前用户的个人访问令牌
+// @Summary 吊销个人访问令牌
+// @Tags User
+// @Security Bearer
+// @Param tid path int true "令牌ID"
+// @Router /api/v1/users/me/tokens/{tid} [delete]
+func (h *AuthHandler) RevokePersonalToken(c *gin.Context) {
+	userID := c.GetInt64("user_id")
+	if userID == 0 {
+		response.Unauthorized(c)
+		return
+	}
+
+	tokenID, err := strconv.ParseInt(c.Param("tid"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的令牌ID")
+		return
+	}
+
+	revokePersonalToken(c, h.authService, userID, tokenID)
+}
+
+// ListPersonalTokens 获取指定用户的个人访问令牌
+func (h *UserHandler) ListPersonalTokens(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的用户ID")
+		return
+	}
+
+	tokens, err := h.authService.ListPersonalTokens(id)
+	if err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+
+	response.Success(c, tokens)
+}
+
+// RevokePersonalToken 吊销指定用户的个人访问令牌
+func (h *UserHandler) RevokePersonalToken(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的用户ID")
+		return
+	}
+	tokenID, err := strconv.ParseInt(c.Param("tid"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的令牌ID")
+		return
+	}
+
+	revokePersonalToken(c, h.authService, id, tokenID)
+}
+
+// revokePersonalToken 吊销令牌并输出响应
+func revokePersonalToken(c *gin.Context, authService *service.AuthService, userID, tokenID int64) {
+	if err := authService.RevokePersonalToken(userID, tokenID); err != nil {
+		if errors.Is(err, service.ErrPersonalTokenNotFound) {
+			response.NotFound(c, err.Error())
+			return
+		}
+		response.InternalError(c, "吊销失败")
+		return
+	}
+
+	response.SuccessWithMessage(c, "已吊销", nil)
+}
@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// VerifyTwoFactor 两步验证登录
+// @Summary 两步验证登录
+// @Description 提交登录返回的挑战令牌与验证码 (TOTP 或恢复码)，成功后返回访问令牌与刷新令牌
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param verify body dto.TwoFactorLoginRequest true "挑战令牌与验证码"
+// @Success 200 {object} dto.LoginResult
+// @Router /api/v1/auth/login/2fa [post]
+func (h *AuthHandler) VerifyTwoFactor(c *gin.Context) {
+	var req dto.TwoFactorLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, "验证码不能为空")
+		return
+	}
+
+	result, err := h.authService.VerifyTwoFactorLogin(req.ChallengeToken, req.Code, clientInfo(c, ""))
+	if err != nil {
+		response.Error(c, response.CodeUnauthorized, err.Error())
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// TwoFactorStatus 获取两步验证状态
+// @Summary 两步验证状态
+// @Tags User
+// @Security Bearer
+// @Success 200 {object} dto.TwoFactorStatus
+// @Router /api/v1/users/me/2fa [get]
+func (h *AuthHandler) TwoFactorStatus(c *gin.Context) {
+	userID := c.GetInt64("user_id")
+	if userID == 0 {
+		response.Unauthorized(c)
+		return
+	}
+
+	status, err := h.authService.TwoFactorStatus(userID)
+	if err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+
+	response.Success(c, status)
+}
+
+// SetupTwoFactor 获取两步验证绑定密钥
+// @Summary 绑定身份验证器
+// @Description 生成 TOTP 密钥与 otpauth URI，提交首个验证码后才会启用
+// @Tags User
+// @Security Bearer
+// @Success 200 {object} dto.TwoFactorSetup
+// @Router /api/v1/users/me/2fa/setup [post]
+func (h *AuthHandler) SetupTwoFactor(c *gin.Context) {
+	userID := c.GetInt64("user_id")
+	if userID == 0 {
+		response.Unauthorized(c)
+		return
+	}
+
+	setup, err := h.authService.SetupTwoFactor(userID)
+	if err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+
+	response.Success(c, setup)
+}
+
+// EnableTwoFactor 启用两步验证
+// @Summary 启用两步验证
+// @Description 校验身份验证器生成的首个验证码，成功后启用并返回恢复码 (仅展示一次)
+// @Tags User
+// @Security Bearer
+// @Param code body dto.TwoFactorCodeRequest true "验证码"
+// @Success 200 {object} dto.RecoveryCodesResult
+// @Router /api/v1/users/me/2fa/enable [post]
+func (h *AuthHandler) EnableTwoFactor(c *gin.Context) {
+	userID := c.GetInt64("user_id")
+	if userID == 0 {
+		response.Unauthorized(c)
+		return
+	}
+
+	var req dto.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, "验证码不能为空")
+		return
+	}
+
+	codes, err := h.authService.EnableTwoFactor(userID, req.Code)
+	if err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "两步验证已启用", dto.RecoveryCodesResult{RecoveryCodes: codes})
+}
+
+// DisableTwoFactor 关闭两步验证
+// @Summary 关闭两步验证
+// @Tags User
+// @Security Bearer
+// @Param disable body dto.DisableTwoFactorRequest true "密码与验证码"
+// @Router /api/v1/users/me/2fa/disable [post]
+func (h *AuthHandler) DisableTwoFactor(c *gin.Context) {
+	userID := c.GetInt64("user_id")
+	if userID == 0 {
+		response.Unauthorized(c)
+		return
+	}
+
+	var req dto.DisableTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, "密码和验证码不能为空")
+		return
+	}
+
+	if err := h.authService.DisableTwoFactor(userID, req.Password, req.Code); err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "两步验证已关闭", nil)
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码
+// @Summary 重新生成恢复码
+// @Description 原有恢复码全部作废，新恢复码仅展示一次
+// @Tags User
+// @Security Bearer
+// @Param code body dto.TwoFactorCodeRequest true "验证码"
+// @Success 200 {object} dto.RecoveryCodesResult
+// @Router /api/v1/users/me/2fa/recovery-codes [post]
+func (h *AuthHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	userID := c.GetInt64("user_id")
+	if userID == 0 {
+		response.Unauthorized(c)
+		return
+	}
+
+	var req dto.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, "验证码不能为空")
+		return
+	}
+
+	codes, err := h.authService.RegenerateRecoveryCodes(userID, req.Code)
+	if err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+
+	response.Success(c, dto.RecoveryCodesResult{RecoveryCodes: codes})
+}
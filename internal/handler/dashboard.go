@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"github.com/FruitsAI/Orange/internal/middleware"
 	"github.com/FruitsAI/Orange/internal/pkg/response"
 	"github.com/FruitsAI/Orange/internal/service"
 	"github.com/gin-gonic/gin"
@@ -28,10 +29,10 @@ func NewDashboardHandler() *DashboardHandler {
 // @Success 200 {object} dto.Stats
 // @Router /api/v1/dashboard/stats [get]
 func (h *DashboardHandler) Stats(c *gin.Context) {
-	userID := c.GetInt64("user_id")
+	scope := middleware.GetDataScope(c)
 
 	period := c.Query("period") // 参数为空时，Service层默认视为全局统计
-	stats, err := h.dashboardService.GetStats(userID, period)
+	stats, err := h.dashboardService.GetStats(scope, period)
 	if err != nil {
 		response.InternalError(c, "获取统计数据失败")
 		return
@@ -49,10 +50,10 @@ func (h *DashboardHandler) Stats(c *gin.Context) {
 // @Success 200 {object} dto.IncomeTrend
 // @Router /api/v1/dashboard/income-trend [get]
 func (h *DashboardHandler) IncomeTrend(c *gin.Context) {
-	userID := c.GetInt64("user_id")
+	scope := middleware.GetDataScope(c)
 	period := c.DefaultQuery("period", "month")
 
-	trend, err := h.dashboardService.GetIncomeTrend(userID, period)
+	trend, err := h.dashboardService.GetIncomeTrend(scope, period)
 	if err != nil {
 		response.InternalError(c, "获取收入趋势失败")
 		return
@@ -69,9 +70,9 @@ func (h *DashboardHandler) IncomeTrend(c *gin.Context) {
 // @Success 200 {array} models.Project
 // @Router /api/v1/dashboard/recent-projects [get]
 func (h *DashboardHandler) RecentProjects(c *gin.Context) {
-	userID := c.GetInt64("user_id")
+	scope := middleware.GetDataScope(c)
 
-	projects, err := h.dashboardService.GetRecentProjects(userID)
+	projects, err := h.dashboardService.GetRecentProjects(scope)
 	if err != nil {
 		response.InternalError(c, "获取最近项目失败")
 		return
@@ -88,9 +89,9 @@ func (h *DashboardHandler) RecentProjects(c *gin.Context) {
 // @Success 200 {array} models.Payment
 // @Router /api/v1/dashboard/upcoming-payments [get]
 func (h *DashboardHandler) UpcomingPayments(c *gin.Context) {
-	userID := c.GetInt64("user_id")
+	scope := middleware.GetDataScope(c)
 
-	payments, err := h.dashboardService.GetUpcomingPayments(userID)
+	payments, err := h.dashboardService.GetUpcomingPayments(scope)
 	if err != nil {
 		response.InternalError(c, "获取即将到期收款失败")
 		return
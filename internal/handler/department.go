@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/pkg/response"
+	"github.com/FruitsAI/Orange/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// DepartmentHandler 部门接口处理器
+// 部门树对拥有 org.manage 或 user.manage 权限的用户开放 (用于为用户分配部门)，增删改需要 org.manage 权限。
+type DepartmentHandler struct {
+	departmentService *service.DepartmentService
+}
+
+// NewDepartmentHandler 创建部门处理器实例
+func NewDepartmentHandler() *DepartmentHandler {
+	return &DepartmentHandler{
+		departmentService: service.NewDepartmentService(),
+	}
+}
+
+// Tree 获取部门树
+// @Summary 获取部门树
+// @Description 获取全部部门 (按上级组织为树)，含负责人姓名与直属成员数
+// @Tags Department
+// @Security Bearer
+// @Success 200 {array} dto.DepartmentNode
+// @Router /api/v1/departments [get]
+func (h *DepartmentHandler) Tree(c *gin.Context) {
+	tree, err := h.departmentService.Tree()
+	if err != nil {
+		response.InternalError(c, "获取部门列表失败")
+		return
+	}
+
+	response.Success(c, tree)
+}
+
+// Create 创建部门
+// @Summary 创建部门
+// @Tags Department
+// @Security Bearer
+// @Param department body dto.DepartmentRequest true "部门信息"
+// @Success 200 {object} models.Department
+// @Failure 403 {string} string "无权操作"
+// @Router /api/v1/departments [post]
+func (h *DepartmentHandler) Create(c *gin.Context) {
+	var req dto.DepartmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+
+	dept, err := h.departmentService.Create(req)
+	if err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+
+	response.Success(c, dept)
+}
+
+// Update 更新部门
+// @Summary 更新部门
+// @Description 更新名称、上级部门、负责人与排序，成员的部门名称随之更新
+// @Tags Department
+// @Security Bearer
+// @Param id path int true "部门ID"
+// @Param department body dto.DepartmentRequest true "部门信息"
+// @Success 200 {object} models.Department
+// @Failure 403 {string} string "无权操作"
+// @Router /api/v1/departments/{id} [put]
+func (h *DepartmentHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的部门ID")
+		return
+	}
+
+	var req dto.DepartmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+
+	dept, err := h.departmentService.Update(id, req)
+	if errors.Is(err, service.ErrDepartmentNotFound) {
+		response.NotFound(c, err.Error())
+		return
+	}
+	if err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+
+	response.Success(c, dept)
+}
+
+// Delete 删除部门
+// @Summary 删除部门
+// @Description 删除部门 (仍有下级部门或成员时不可删除)
+// @Tags Department
+// @Security Bearer
+// @Param id path int true "部门ID"
+// @Success 200 {string} string "删除成功"
+// @Failure 403 {string} string "无权操作"
+// @Router /api/v1/departments/{id} [delete]
+func (h *DepartmentHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的部门ID")
+		return
+	}
+
+	err = h.departmentService.Delete(id)
+	switch {
+	case errors.Is(err, service.ErrDepartmentNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrDepartmentHasChildren), errors.Is(err, service.ErrDepartmentInUse):
+		response.ParamError(c, err.Error())
+	case err != nil:
+		response.InternalError(c, "删除部门失败")
+	default:
+		response.SuccessWithMessage(c, "删除成功", nil)
+	}
+}
@@ -61,7 +61,7 @@ func (h *DictionaryHandler) GetItems(c *gin.Context) {
 
 // CreateItem 新增字典选项
 // @Summary 创建字典项
-// @Description 为指定字典添加一个新的选项值(仅限管理员)
+// @Description 为指定字典添加一个新的选项值(需要 dictionary.edit 权限)
 // @Tags Dictionary
 // @Security Bearer
 // @Param code path string true "字典编码"
@@ -70,21 +70,16 @@ func (h *DictionaryHandler) GetItems(c *gin.Context) {
 // @Failure 403 {string} string "无权操作"
 // @Router /api/v1/dictionaries/{code}/items [post]
 func (h *DictionaryHandler) CreateItem(c *gin.Context) {
-	// 1. 权限校验
-	if c.GetString("role") != "admin" {
-		response.Forbidden(c, "无权操作")
-		return
-	}
 	code := c.Param("code")
 
-	// 2. 参数绑定
+	// 1. 参数绑定
 	var req dto.CreateDictionaryItemRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		response.ParamError(c, err.Error())
 		return
 	}
 
-	// 3. 执行创建
+	// 2. 执行创建
 	item, err := h.dictService.CreateItem(code, req.Label, req.Value, req.Sort)
 	if err != nil {
 		response.InternalError(c, "创建字典项失败")
@@ -96,7 +91,7 @@ func (h *DictionaryHandler) CreateItem(c *gin.Context) {
 
 // UpdateItem 更新字典选项
 // @Summary 更新字典项
-// @Description 更新现有字典选项的名称、值或排序(仅限管理员)
+// @Description 更新现有字典选项的名称、值或排序(需要 dictionary.edit 权限)
 // @Tags Dictionary
 // @Security Bearer
 // @Param code path string true "字典编码 (仅作路由占位)"
@@ -106,27 +101,21 @@ func (h *DictionaryHandler) CreateItem(c *gin.Context) {
 // @Failure 403 {string} string "无权操作"
 // @Router /api/v1/dictionaries/{code}/items/{id} [put]
 func (h *DictionaryHandler) UpdateItem(c *gin.Context) {
-	// 1. 权限校验
-	if c.GetString("role") != "admin" {
-		response.Forbidden(c, "无权操作")
-		return
-	}
-
-	// 2. ID解析
+	// 1. ID解析
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
 		response.ParamError(c, "无效的字典项ID")
 		return
 	}
 
-	// 3. 参数绑定
+	// 2. 参数绑定
 	var req dto.CreateDictionaryItemRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		response.ParamError(c, err.Error())
 		return
 	}
 
-	// 4. 执行更新
+	// 3. 执行更新
 	item, err := h.dictService.UpdateItem(id, req.Label, req.Value, req.Sort)
 	if err != nil {
 		response.InternalError(c, "更新字典项失败")
@@ -138,7 +127,7 @@ func (h *DictionaryHandler) UpdateItem(c *gin.Context) {
 
 // DeleteItem 删除字典选项
 // @Summary 删除字典项
-// @Description 物理删除指定的字典选项(仅限管理员)
+// @Description 物理删除指定的字典选项(需要 dictionary.edit 权限)
 // @Tags Dictionary
 // @Security Bearer
 // @Param code path string true "字典编码 (仅作路由占位)"
@@ -147,10 +136,6 @@ func (h *DictionaryHandler) UpdateItem(c *gin.Context) {
 // @Failure 403 {string} string "无权操作"
 // @Router /api/v1/dictionaries/{code}/items/{id} [delete]
 func (h *DictionaryHandler) DeleteItem(c *gin.Context) {
-	if c.GetString("role") != "admin" {
-		response.Forbidden(c, "无权操作")
-		return
-	}
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
 		response.ParamError(c, "无效的字典项ID")
@@ -32,7 +32,7 @@ type CreateNotificationRequest struct {
 
 // Create 发布新通知
 // @Summary 发布通知
-// @Description 管理员发布新通知，支持全员或指定用户
+// @Description 发布新通知，支持全员或指定用户(需要 notification.send 权限)
 // @Tags Notification
 // @Security Bearer
 // @Param notification body CreateNotificationRequest true "通知内容"
@@ -41,22 +41,15 @@ type CreateNotificationRequest struct {
 // @Router /api/v1/notifications [post]
 func (h *NotificationHandler) Create(c *gin.Context) {
 	userID := c.GetInt64("user_id")
-	role := c.GetString("role")
 
-	// 1. 权限校验
-	if role != "admin" {
-		response.Forbidden(c)
-		return
-	}
-
-	// 2. 参数绑定
+	// 1. 参数绑定
 	var req CreateNotificationRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		response.ParamError(c, err.Error())
 		return
 	}
 
-	// 3. 调用服务层
+	// 2. 调用服务层
 	notification, err := h.notificationService.Create(userID, req.Title, req.Content, req.Type, req.TargetUserID)
 	if err != nil {
 		response.InternalError(c, err.Error())
@@ -68,7 +61,7 @@ func (h *NotificationHandler) Create(c *gin.Context) {
 
 // Update 更新通知内容
 // @Summary 更新通知
-// @Description 修改现有通知的标题、内容等信息(仅限管理员)
+// @Description 修改现有通知的标题、内容等信息(需要 notification.send 权限)
 // @Tags Notification
 // @Security Bearer
 // @Param id path int true "通知ID"
@@ -77,28 +70,20 @@ func (h *NotificationHandler) Create(c *gin.Context) {
 // @Failure 403 {string} string "无权操作"
 // @Router /api/v1/notifications/{id} [put]
 func (h *NotificationHandler) Update(c *gin.Context) {
-	role := c.GetString("role")
-
-	// 1. 权限校验
-	if role != "admin" {
-		response.Forbidden(c)
-		return
-	}
-
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
 		response.ParamError(c, "无效的通知ID")
 		return
 	}
 
-	// 2. 参数绑定
+	// 1. 参数绑定
 	var req CreateNotificationRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		response.ParamError(c, err.Error())
 		return
 	}
 
-	// 3. 执行更新
+	// 2. 执行更新
 	notification, err := h.notificationService.Update(id, req.Title, req.Content, req.Type, req.TargetUserID)
 	if err != nil {
 		response.InternalError(c, err.Error())
@@ -164,7 +149,7 @@ func (h *NotificationHandler) MarkAsRead(c *gin.Context) {
 
 // Delete 删除通知
 // @Summary 删除通知
-// @Description 管理员删除指定通知
+// @Description 删除指定通知(需要 notification.send 权限)
 // @Tags Notification
 // @Security Bearer
 // @Param id path int true "通知ID"
@@ -172,14 +157,6 @@ func (h *NotificationHandler) MarkAsRead(c *gin.Context) {
 // @Failure 403 {string} string "无权操作"
 // @Router /api/v1/notifications/{id} [delete]
 func (h *NotificationHandler) Delete(c *gin.Context) {
-	role := c.GetString("role")
-
-	// 1. 权限校验
-	if role != "admin" {
-		response.Forbidden(c)
-		return
-	}
-
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
 		response.ParamError(c, "无效的通知ID")
@@ -215,21 +192,13 @@ func (h *NotificationHandler) UnreadCount(c *gin.Context) {
 
 // ListUsers 获取可选用户列表
 // @Summary 获取用户列表
-// @Description 获取所有用户列表，用于发送通知时选择目标(仅限管理员)
+// @Description 获取所有用户列表，用于发送通知时选择目标(需要 notification.send 权限)
 // @Tags Notification
 // @Security Bearer
 // @Success 200 {array} models.User
 // @Failure 403 {string} string "无权操作"
 // @Router /api/v1/notifications/users [get]
 func (h *NotificationHandler) ListUsers(c *gin.Context) {
-	role := c.GetString("role")
-
-	// 权限校验
-	if role != "admin" {
-		response.Forbidden(c)
-		return
-	}
-
 	users, err := h.notificationService.ListUsers()
 	if err != nil {
 		response.InternalError(c, err.Error())
@@ -4,6 +4,7 @@ import (
 	"strconv"
 
 	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/middleware"
 	"github.com/FruitsAI/Orange/internal/pkg/response"
 	"github.com/FruitsAI/Orange/internal/service"
 	"github.com/gin-gonic/gin"
@@ -37,9 +38,9 @@ func (h *PaymentHandler) GetByProject(c *gin.Context) {
 		return
 	}
 
-	payments, err := h.paymentService.ListByProject(projectID)
+	payments, err := h.paymentService.ListByProject(projectID, middleware.GetProjectActor(c))
 	if err != nil {
-		response.InternalError(c, "获取收款列表失败")
+		projectError(c, err, "获取收款列表失败")
 		return
 	}
 
@@ -57,13 +58,13 @@ func (h *PaymentHandler) GetByProject(c *gin.Context) {
 // @Success 200 {array} models.Payment
 // @Router /api/v1/payments [get]
 func (h *PaymentHandler) List(c *gin.Context) {
-	userID := c.GetInt64("user_id")
+	scope := middleware.GetDataScope(c)
 	startDate := c.Query("start_date")
 	endDate := c.Query("end_date")
 
 	// 1. 按日期范围查询
 	if startDate != "" && endDate != "" {
-		payments, err := h.paymentService.ListByDateRange(userID, startDate, endDate)
+		payments, err := h.paymentService.ListByDateRange(scope, startDate, endDate)
 		if err != nil {
 			response.InternalError(c, "获取收款列表失败")
 			return
@@ -80,9 +81,9 @@ func (h *PaymentHandler) List(c *gin.Context) {
 			response.ParamError(c, "无效的项目ID")
 			return
 		}
-		payments, err := h.paymentService.ListByProject(projectID)
+		payments, err := h.paymentService.ListByProject(projectID, middleware.GetProjectActor(c))
 		if err != nil {
-			response.InternalError(c, "获取收款列表失败")
+			projectError(c, err, "获取收款列表失败")
 			return
 		}
 		response.Success(c, payments)
@@ -112,9 +113,9 @@ func (h *PaymentHandler) Create(c *gin.Context) {
 
 	req.UserID = userID // 手动设置 UserID，确保数据归属正确
 
-	payment, err := h.paymentService.Create(req)
+	payment, err := h.paymentService.Create(req, middleware.GetProjectActor(c))
 	if err != nil {
-		response.InternalError(c, "创建收款失败")
+		projectError(c, err, "创建收款失败")
 		return
 	}
 
@@ -143,9 +144,9 @@ func (h *PaymentHandler) Update(c *gin.Context) {
 		return
 	}
 
-	payment, err := h.paymentService.Update(id, req)
+	payment, err := h.paymentService.Update(id, req, middleware.GetProjectActor(c))
 	if err != nil {
-		response.InternalError(c, "更新收款失败")
+		projectError(c, err, "更新收款失败")
 		return
 	}
 
@@ -167,8 +168,8 @@ func (h *PaymentHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.paymentService.Delete(id); err != nil {
-		response.InternalError(c, "删除收款失败")
+	if err := h.paymentService.Delete(id, middleware.GetProjectActor(c)); err != nil {
+		projectError(c, err, "删除收款失败")
 		return
 	}
 
@@ -197,8 +198,8 @@ func (h *PaymentHandler) Confirm(c *gin.Context) {
 		return
 	}
 
-	if err := h.paymentService.Confirm(id, req.ActualDate, req.Method); err != nil {
-		response.InternalError(c, "确认收款失败")
+	if err := h.paymentService.Confirm(id, req.ActualDate, req.Method, middleware.GetProjectActor(c)); err != nil {
+		projectError(c, err, "确认收款失败")
 		return
 	}
 
@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/pkg/response"
+	"github.com/FruitsAI/Orange/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// PositionHandler 职位接口处理器
+// 职位列表对拥有 org.manage 或 user.manage 权限的用户开放 (用于为用户分配职位)，增删改需要 org.manage 权限。
+type PositionHandler struct {
+	positionService *service.PositionService
+}
+
+// NewPositionHandler 创建职位处理器实例
+func NewPositionHandler() *PositionHandler {
+	return &PositionHandler{
+		positionService: service.NewPositionService(),
+	}
+}
+
+// List 获取职位列表
+// @Summary 获取职位列表
+// @Description 获取全部职位及担任人数
+// @Tags Position
+// @Security Bearer
+// @Success 200 {array} dto.PositionView
+// @Router /api/v1/positions [get]
+func (h *PositionHandler) List(c *gin.Context) {
+	positions, err := h.positionService.List()
+	if err != nil {
+		response.InternalError(c, "获取职位列表失败")
+		return
+	}
+
+	response.Success(c, positions)
+}
+
+// Create 创建职位
+// @Summary 创建职位
+// @Tags Position
+// @Security Bearer
+// @Param position body dto.PositionRequest true "职位信息"
+// @Success 200 {object} models.Position
+// @Failure 403 {string} string "无权操作"
+// @Router /api/v1/positions [post]
+func (h *PositionHandler) Create(c *gin.Context) {
+	var req dto.PositionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+
+	position, err := h.positionService.Create(req)
+	if err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+
+	response.Success(c, position)
+}
+
+// Update 更新职位
+// @Summary 更新职位
+// @Description 更新名称、描述与排序，用户的职位名称随之更新
+// @Tags Position
+// @Security Bearer
+// @Param id path int true "职位ID"
+// @Param position body dto.PositionRequest true "职位信息"
+// @Success 200 {object} models.Position
+// @Failure 403 {string} string "无权操作"
+// @Router /api/v1/positions/{id} [put]
+func (h *PositionHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的职位ID")
+		return
+	}
+
+	var req dto.PositionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+
+	position, err := h.positionService.Update(id, req)
+	if errors.Is(err, service.ErrPositionNotFound) {
+		response.NotFound(c, err.Error())
+		return
+	}
+	if err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+
+	response.Success(c, position)
+}
+
+// Delete 删除职位
+// @Summary 删除职位
+// @Description 删除职位 (仍有用户担任时不可删除)
+// @Tags Position
+// @Security Bearer
+// @Param id path int true "职位ID"
+// @Success 200 {string} string "删除成功"
+// @Failure 403 {string} string "无权操作"
+// @Router /api/v1/positions/{id} [delete]
+func (h *PositionHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的职位ID")
+		return
+	}
+
+	err = h.positionService.Delete(id)
+	switch {
+	case errors.Is(err, service.ErrPositionNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrPositionInUse):
+		response.ParamError(c, err.Error())
+	case err != nil:
+		response.InternalError(c, "删除职位失败")
+	default:
+		response.SuccessWithMessage(c, "删除成功", nil)
+	}
+}
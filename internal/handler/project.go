@@ -1,9 +1,11 @@
 package handler
 
 import (
+	"errors"
 	"strconv"
 
 	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/middleware"
 	"github.com/FruitsAI/Orange/internal/pkg/response"
 	"github.com/FruitsAI/Orange/internal/service"
 	"github.com/gin-gonic/gin"
@@ -34,13 +36,13 @@ func NewProjectHandler() *ProjectHandler {
 // @Success 200 {object} response.PageResult
 // @Router /api/v1/projects [get]
 func (h *ProjectHandler) List(c *gin.Context) {
-	userID := c.GetInt64("user_id")
+	scope := middleware.GetDataScope(c)
 	status := c.Query("status")
 	keyword := c.Query("keyword")
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
 
-	result, err := h.projectService.List(userID, status, keyword, page, pageSize)
+	result, err := h.projectService.List(scope, status, keyword, page, pageSize)
 	if err != nil {
 		response.InternalError(c, "获取项目列表失败")
 		return
@@ -55,7 +57,7 @@ func (h *ProjectHandler) List(c *gin.Context) {
 // @Tags Project
 // @Security Bearer
 // @Param id path int true "项目ID"
-// @Success 200 {object} models.Project
+// @Success 200 {object} dto.ProjectDetail
 // @Router /api/v1/projects/{id} [get]
 func (h *ProjectHandler) Get(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
@@ -64,9 +66,9 @@ func (h *ProjectHandler) Get(c *gin.Context) {
 		return
 	}
 
-	project, err := h.projectService.Get(id)
+	project, err := h.projectService.Get(id, middleware.GetProjectActor(c))
 	if err != nil {
-		response.NotFound(c, "项目不存在")
+		projectError(c, err, "获取项目详情失败")
 		return
 	}
 
@@ -123,9 +125,9 @@ func (h *ProjectHandler) Update(c *gin.Context) {
 		return
 	}
 
-	project, err := h.projectService.Update(id, req)
+	project, err := h.projectService.Update(id, req, middleware.GetProjectActor(c))
 	if err != nil {
-		response.InternalError(c, "更新项目失败")
+		projectError(c, err, "更新项目失败")
 		return
 	}
 
@@ -147,8 +149,8 @@ func (h *ProjectHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.projectService.Delete(id); err != nil {
-		response.InternalError(c, "删除项目失败")
+	if err := h.projectService.Delete(id, middleware.GetProjectActor(c)); err != nil {
+		projectError(c, err, "删除项目失败")
 		return
 	}
 
@@ -170,8 +172,8 @@ func (h *ProjectHandler) Archive(c *gin.Context) {
 		return
 	}
 
-	if err := h.projectService.Archive(id); err != nil {
-		response.InternalError(c, "归档项目失败")
+	if err := h.projectService.Archive(id, middleware.GetProjectActor(c)); err != nil {
+		projectError(c, err, "归档项目失败")
 		return
 	}
 
@@ -230,3 +232,19 @@ func (h *ProjectHandler) GenerateContractNumber(c *gin.Context) {
 
 	response.Success(c, gin.H{"contract_number": contractNumber})
 }
+
+// projectError 输出项目与收款操作失败的错误
+// 项目或款项不存在返回 1002，项目角色不允许该操作返回 2003，修改收款所属项目返回 1001，其余错误返回 message。
+func projectError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrProjectNotFound), errors.Is(err, service.ErrPaymentNotFound),
+		errors.Is(err, service.ErrProjectMemberNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrProjectForbidden):
+		response.Forbidden(c, err.Error())
+	case errors.Is(err, service.ErrPaymentProjectChanged):
+		response.ParamError(c, err.Error())
+	default:
+		response.InternalError(c, message)
+	}
+}
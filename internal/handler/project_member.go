@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/middleware"
+	"github.com/FruitsAI/Orange/internal/pkg/response"
+	"github.com/FruitsAI/Orange/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// ProjectMemberHandler 项目成员接口处理器
+// 项目成员可查看成员列表，负责人可添加、移除成员，调整成员的项目角色或转让项目。
+type ProjectMemberHandler struct {
+	memberService *service.ProjectMemberService
+}
+
+// NewProjectMemberHandler 创建项目成员处理器实例
+func NewProjectMemberHandler() *ProjectMemberHandler {
+	return &ProjectMemberHandler{
+		memberService: service.NewProjectMemberService(),
+	}
+}
+
+// List 获取项目成员
+// @Summary 项目成员列表
+// @Description 获取项目负责人 (角色 owner) 与全部成员
+// @Tags Project
+// @Security Bearer
+// @Param id path int true "项目ID"
+// @Success 200 {array} models.ProjectMember
+// @Router /api/v1/projects/{id}/members [get]
+func (h *ProjectMemberHandler) List(c *gin.Context) {
+	projectID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的项目ID")
+		return
+	}
+
+	members, err := h.memberService.List(projectID, middleware.GetProjectActor(c))
+	if err != nil {
+		projectError(c, err, "获取项目成员失败")
+		return
+	}
+
+	response.Success(c, members)
+}
+
+// Add 添加项目成员
+// @Summary 添加项目成员
+// @Description 负责人为项目添加成员，项目角色为 editor、viewer 或 finance
+// @Tags Project
+// @Security Bearer
+// @Param id path int true "项目ID"
+// @Param member body dto.ProjectMemberRequest true "成员信息"
+// @Success 200 {object} models.ProjectMember
+// @Router /api/v1/projects/{id}/members [post]
+func (h *ProjectMemberHandler) Add(c *gin.Context) {
+	projectID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的项目ID")
+		return
+	}
+
+	var req dto.ProjectMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+
+	member, err := h.memberService.Add(projectID, req, middleware.GetProjectActor(c))
+	if err != nil {
+		memberError(c, err, "添加项目成员失败")
+		return
+	}
+
+	response.Success(c, member)
+}
+
+// UpdateRole 修改项目成员角色
+// @Summary 修改成员角色
+// @Description 负责人调整成员的项目角色
+// @Tags Project
+// @Security Bearer
+// @Param id path int true "项目ID"
+// @Param uid path int true "成员用户ID"
+// @Param member body dto.ProjectMemberRoleRequest true "项目角色"
+// @Success 200 {string} string "更新成功"
+// @Router /api/v1/projects/{id}/members/{uid} [put]
+func (h *ProjectMemberHandler) UpdateRole(c *gin.Context) {
+	projectID, userID, ok := memberParams(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ProjectMemberRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+
+	if err := h.memberService.UpdateRole(projectID, userID, req.Role, middleware.GetProjectActor(c)); err != nil {
+		memberError(c, err, "修改成员角色失败")
+		return
+	}
+
+	response.SuccessWithMessage(c, "更新成功", nil)
+}
+
+// Remove 移除项目成员
+// @Summary 移除项目成员
+// @Description 负责人移除成员，成员也可移除自己 (退出项目)
+// @Tags Project
+// @Security Bearer
+// @Param id path int true "项目ID"
+// @Param uid path int true "成员用户ID"
+// @Success 200 {string} string "移除成功"
+// @Router /api/v1/projects/{id}/members/{uid} [delete]
+func (h *ProjectMemberHandler) Remove(c *gin.Context) {
+	projectID, userID, ok := memberParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.memberService.Remove(projectID, userID, middleware.GetProjectActor(c)); err != nil {
+		memberError(c, err, "移除项目成员失败")
+		return
+	}
+
+	response.SuccessWithMessage(c, "移除成功", nil)
+}
+
+// Transfer 转让项目
+// @Summary 转让项目
+// @Description 负责人将项目转让给其他用户，原负责人保留为编辑成员
+// @Tags Project
+// @Security Bearer
+// @Param id path int true "项目ID"
+// @Param transfer body dto.TransferProjectRequest true "新负责人"
+// @Success 200 {string} string "转让成功"
+// @Router /api/v1/projects/{id}/transfer [post]
+func (h *ProjectMemberHandler) Transfer(c *gin.Context) {
+	projectID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的项目ID")
+		return
+	}
+
+	var req dto.TransferProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+
+	if err := h.memberService.Transfer(projectID, req.UserID, middleware.GetProjectActor(c)); err != nil {
+		memberError(c, err, "转让项目失败")
+		return
+	}
+
+	response.SuccessWithMessage(c, "转让成功", nil)
+}
+
+// Candidates 搜索可添加为成员的用户
+// @Summary 搜索成员候选用户
+// @Description 按用户名或姓名搜索正常状态的用户 (最多 20 个)，用于添加成员与转让项目
+// @Tags Project
+// @Security Bearer
+// @Param keyword query string false "用户名或姓名"
+// @Success 200 {array} models.User
+// @Router /api/v1/projects/member-candidates [get]
+func (h *ProjectMemberHandler) Candidates(c *gin.Context) {
+	users, err := h.memberService.Candidates(c.Query("keyword"))
+	if err != nil {
+		response.InternalError(c, "搜索用户失败")
+		return
+	}
+
+	response.Success(c, users)
+}
+
+// memberParams 解析路径中的项目ID与成员用户ID，无效时输出错误并返回 false
+func memberParams(c *gin.Context) (projectID, userID int64, ok bool) {
+	projectID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的项目ID")
+		return 0, 0, false
+	}
+	userID, err = strconv.ParseInt(c.Param("uid"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的用户ID")
+		return 0, 0, false
+	}
+	return projectID, userID, true
+}
+
+// memberError 输出成员管理失败的错误，成员参数不合法时返回 1001
+func memberError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidProjectRole), errors.Is(err, service.ErrProjectMemberExists),
+		errors.Is(err, service.ErrProjectMemberUser), errors.Is(err, service.ErrProjectAlreadyOwner):
+		response.ParamError(c, err.Error())
+	default:
+		projectError(c, err, message)
+	}
+}
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/pkg/response"
+	"github.com/FruitsAI/Orange/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// RoleHandler 角色与权限接口处理器
+// 角色列表对拥有 role.manage 或 user.manage 权限的用户开放 (用于为用户分配角色)，增删改需要 role.manage 权限。
+type RoleHandler struct {
+	roleService *service.RoleService
+}
+
+// NewRoleHandler 创建角色处理器实例
+func NewRoleHandler() *RoleHandler {
+	return &RoleHandler{
+		roleService: service.NewRoleService(),
+	}
+}
+
+// List 获取角色列表
+// @Summary 获取角色列表
+// @Description 获取全部角色及其权限与使用人数
+// @Tags Role
+// @Security Bearer
+// @Success 200 {array} dto.RoleView
+// @Router /api/v1/roles [get]
+func (h *RoleHandler) List(c *gin.Context) {
+	roles, err := h.roleService.List()
+	if err != nil {
+		response.InternalError(c, "获取角色列表失败")
+		return
+	}
+
+	response.Success(c, roles)
+}
+
+// Permissions 获取全部权限
+// @Summary 获取权限列表
+// @Description 获取系统定义的全部权限 (按模块排序)
+// @Tags Role
+// @Security Bearer
+// @Success 200 {array} models.Permission
+// @Router /api/v1/permissions [get]
+func (h *RoleHandler) Permissions(c *gin.Context) {
+	permissions, err := h.roleService.ListPermissions()
+	if err != nil {
+		response.InternalError(c, "获取权限列表失败")
+		return
+	}
+
+	response.Success(c, permissions)
+}
+
+// Create 创建角色
+// @Summary 创建角色
+// @Tags Role
+// @Security Bearer
+// @Param role body dto.RoleRequest true "角色信息"
+// @Success 200 {object} models.Role
+// @Failure 403 {string} string "无权操作"
+// @Router /api/v1/roles [post]
+func (h *RoleHandler) Create(c *gin.Context) {
+	var req dto.RoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+
+	role, err := h.roleService.Create(req)
+	if err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+
+	response.Success(c, role)
+}
+
+// Update 更新角色
+// @Summary 更新角色
+// @Description 更新角色名称、描述、数据范围与权限 (编码不可修改，管理员角色始终拥有全部权限与全部数据)
+// @Tags Role
+// @Security Bearer
+// @Param id path int true "角色ID"
+// @Param role body dto.RoleRequest true "角色信息"
+// @Success 200 {object} models.Role
+// @Failure 403 {string} string "无权操作"
+// @Router /api/v1/roles/{id} [put]
+func (h *RoleHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的角色ID")
+		return
+	}
+
+	var req dto.RoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+
+	role, err := h.roleService.Update(id, req)
+	if errors.Is(err, service.ErrRoleNotFound) {
+		response.NotFound(c, err.Error())
+		return
+	}
+	if err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+
+	response.Success(c, role)
+}
+
+// Delete 删除角色
+// @Summary 删除角色
+// @Description 删除自定义角色 (内置角色与仍有用户使用的角色不可删除)
+// @Tags Role
+// @Security Bearer
+// @Param id path int true "角色ID"
+// @Success 200 {string} string "删除成功"
+// @Failure 403 {string} string "无权操作"
+// @Router /api/v1/roles/{id} [delete]
+func (h *RoleHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的角色ID")
+		return
+	}
+
+	err = h.roleService.Delete(id)
+	switch {
+	case errors.Is(err, service.ErrRoleNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrRoleBuiltIn), errors.Is(err, service.ErrRoleInUse):
+		response.ParamError(c, err.Error())
+	case err != nil:
+		response.InternalError(c, "删除角色失败")
+	default:
+		response.SuccessWithMessage(c, "删除成功", nil)
+	}
+}
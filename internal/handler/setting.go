@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"github.com/FruitsAI/Orange/internal/pkg/response"
+	"github.com/FruitsAI/Orange/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// SettingHandler 系统设置接口处理器 (需要 setting.manage 权限)
+type SettingHandler struct {
+	settingService *service.SettingService
+}
+
+// NewSettingHandler 创建系统设置处理器实例
+func NewSettingHandler() *SettingHandler {
+	return &SettingHandler{
+		settingService: service.NewSettingService(),
+	}
+}
+
+// GetSecurity 获取安全设置
+// @Summary 获取安全设置
+// @Tags Settings
+// @Security Bearer
+// @Success 200 {object} dto.SecuritySettings
+// @Router /api/v1/settings/security [get]
+func (h *SettingHandler) GetSecurity(c *gin.Context) {
+	settings, err := h.settingService.GetSecurity()
+	if err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+
+	response.Success(c, settings)
+}
+
+// UpdateSecurity 更新安全设置
+// 请求中未提供的字段保持原值。
+// @Summary 更新安全设置
+// @Tags Settings
+// @Security Bearer
+// @Param settings body dto.SecuritySettings true "安全设置"
+// @Router /api/v1/settings/security [put]
+func (h *SettingHandler) UpdateSecurity(c *gin.Context) {
+	req, err := h.settingService.GetSecurity()
+	if err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+
+	if err := h.settingService.UpdateSecurity(req); err != nil {
+		response.InternalError(c, "保存失败")
+		return
+	}
+
+	settings, _ := h.settingService.GetSecurity()
+	response.SuccessWithMessage(c, "保存成功", settings)
+}
@@ -1,67 +1,95 @@
 package handler
 
 import (
+	"errors"
+	"io"
 	"net/http"
-	"os"
 	"strconv"
 
+	"github.com/FruitsAI/Orange/internal/config"
+	"github.com/FruitsAI/Orange/internal/middleware"
+	"github.com/FruitsAI/Orange/internal/pkg/response"
 	"github.com/FruitsAI/Orange/internal/service"
 	"github.com/gin-gonic/gin"
 )
 
 // SyncHandler 数据同步 HTTP Handler
 type SyncHandler struct {
-	syncService *service.SyncService
+	syncService    *service.SyncService
+	profileService *service.SyncProfileService
 }
 
 // GetConfig 获取同步配置 (从环境变量)
+// 不返回密码，仅通过 has_password 告知是否已配置。
 // @Router /api/v1/sync/config [get]
 func (h *SyncHandler) GetConfig(c *gin.Context) {
-	port, _ := strconv.Atoi(os.Getenv("SYNC_DB_PORT"))
-	if port == 0 {
-		port = 5432
+	cfg := config.AppConfig
+	data := gin.H{
+		"db_type":      cfg.SyncDBType,
+		"path":         cfg.SyncDBPath,
+		"host":         cfg.SyncDBHost,
+		"port":         cfg.SyncDBPort,
+		"user":         cfg.SyncDBUser,
+		"has_password": cfg.SyncDBPassword != "",
+		"db_name":      cfg.SyncDBName,
+		"ssl_mode":     cfg.SyncSSLMode,
 	}
-
-	config := gin.H{
-		"db_type":  os.Getenv("SYNC_DB_TYPE"),
-		"host":     os.Getenv("SYNC_DB_HOST"),
-		"port":     port,
-		"user":     os.Getenv("SYNC_DB_USER"),
-		"password": os.Getenv("SYNC_DB_PASSWORD"),
-		"db_name":  os.Getenv("SYNC_DB_NAME"),
-		"ssl_mode": os.Getenv("SYNC_SSL_MODE"),
-	}
-	c.JSON(http.StatusOK, gin.H{"code": 0, "data": config})
+	c.JSON(http.StatusOK, gin.H{"code": 0, "data": data})
 }
 
 // NewSyncHandler 创建同步 Handler 实例
 func NewSyncHandler() *SyncHandler {
 	return &SyncHandler{
-		syncService: service.NewSyncService(),
+		syncService:    service.NewSyncService(),
+		profileService: service.NewSyncProfileService(),
 	}
 }
 
-// TestConnectionRequest 测试连接请求
+// TestConnectionRequest 云端连接参数
+// 指定 profile_id 时使用已保存的连接配置，否则需提供完整的连接信息。
 type TestConnectionRequest struct {
-	DBType   string `json:"db_type" binding:"required"`
-	Host     string `json:"host" binding:"required"`
-	Port     int    `json:"port" binding:"required"`
-	User     string `json:"user" binding:"required"`
-	Password string `json:"password" binding:"required"`
-	DBName   string `json:"db_name" binding:"required"`
-	SSLMode  string `json:"ssl_mode"`
+	ProfileID int64  `json:"profile_id"` // 连接配置ID
+	DBType    string `json:"db_type"`
+	Path      string `json:"path"` // SQLite 文件路径 (db_type 为 sqlite 时必填)
+	Host      string `json:"host"`
+	Port      int    `json:"port"`
+	User      string `json:"user"`
+	Password  string `json:"password"`
+	DBName    string `json:"db_name"`
+	SSLMode   string `json:"ssl_mode"`
 }
 
-// TestConnection 测试云端数据库连接
-// @Router /api/v1/sync/test-connection [post]
-func (h *SyncHandler) TestConnection(c *gin.Context) {
-	var req TestConnectionRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": "参数错误: " + err.Error()})
-		return
+// resolveConfig 解析云端连接配置，失败时直接写入错误响应
+func (h *SyncHandler) resolveConfig(c *gin.Context, req TestConnectionRequest) (service.SyncConfig, bool) {
+	if req.ProfileID > 0 {
+		cfg, err := h.profileService.ResolveConfig(req.ProfileID)
+		if err != nil {
+			c.JSON(http.StatusOK, gin.H{"code": 1, "message": err.Error()})
+			return cfg, false
+		}
+		return cfg, true
+	}
+
+	// 直接指定的连接会让服务端连接任意数据库 (SQLite 为任意路径的文件)，需要 sync.manage 权限，
+	// 其他用户只能使用已保存的连接配置
+	if !middleware.HasPermission(c, service.PermSyncManage) {
+		c.JSON(http.StatusForbidden, gin.H{"code": 1, "message": "权限不足: 请选择已保存的连接配置"})
+		return service.SyncConfig{}, false
+	}
+
+	if req.DBType == "sqlite" {
+		if req.Path == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": "参数错误: 请指定 SQLite 文件路径"})
+			return service.SyncConfig{}, false
+		}
+		return service.SyncConfig{DBType: req.DBType, Path: req.Path}, true
 	}
 
-	cfg := service.SyncConfig{
+	if req.DBType == "" || req.Host == "" || req.Port == 0 || req.User == "" || req.Password == "" || req.DBName == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": "参数错误: 请选择连接配置或填写完整的连接信息"})
+		return service.SyncConfig{}, false
+	}
+	return service.SyncConfig{
 		DBType:   req.DBType,
 		Host:     req.Host,
 		Port:     req.Port,
@@ -69,6 +97,43 @@ func (h *SyncHandler) TestConnection(c *gin.Context) {
 		Password: req.Password,
 		DBName:   req.DBName,
 		SSLMode:  req.SSLMode,
+	}, true
+}
+
+// resolveExecuteConfig 解析执行同步的云端连接配置，失败时直接写入错误响应
+// 拉取与双向同步会将云端数据写入本地，只能使用由 sync.manage 权限持有者保存的连接配置。
+func (h *SyncHandler) resolveExecuteConfig(c *gin.Context, req ExecuteRequest) (service.SyncConfig, bool) {
+	if req.Mode != service.SyncModePull && req.Mode != service.SyncModeMerge {
+		return h.resolveConfig(c, req.TestConnectionRequest)
+	}
+	if req.ProfileID == 0 {
+		c.JSON(http.StatusForbidden, gin.H{"code": 1, "message": "权限不足: 拉取或双向同步只能使用已保存的连接配置"})
+		return service.SyncConfig{}, false
+	}
+	cfg, err := h.profileService.ResolvePullConfig(req.ProfileID)
+	if errors.Is(err, service.ErrSyncProfileUntrusted) {
+		c.JSON(http.StatusForbidden, gin.H{"code": 1, "message": "权限不足: " + err.Error()})
+		return cfg, false
+	}
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{"code": 1, "message": err.Error()})
+		return cfg, false
+	}
+	return cfg, true
+}
+
+// TestConnection 测试云端数据库连接
+// @Router /api/v1/sync/test-connection [post]
+func (h *SyncHandler) TestConnection(c *gin.Context) {
+	var req TestConnectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": "参数错误: " + err.Error()})
+		return
+	}
+
+	cfg, ok := h.resolveConfig(c, req)
+	if !ok {
+		return
 	}
 
 	if err := h.syncService.TestConnection(cfg); err != nil {
@@ -79,26 +144,57 @@ func (h *SyncHandler) TestConnection(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"code": 0, "message": "连接成功"})
 }
 
-// Compare 对比本地与云端表的记录数
+// CompareRequest 对比数据请求
+type CompareRequest struct {
+	TestConnectionRequest
+	Policy string `json:"policy"` // 冲突解决策略: last_writer_wins/local_wins/remote_wins/manual
+}
+
+// Compare 对比本地与云端表的记录数及冲突
 // @Router /api/v1/sync/compare [post]
 func (h *SyncHandler) Compare(c *gin.Context) {
-	var req TestConnectionRequest
+	var req CompareRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": "参数错误: " + err.Error()})
 		return
 	}
 
-	cfg := service.SyncConfig{
-		DBType:   req.DBType,
-		Host:     req.Host,
-		Port:     req.Port,
-		User:     req.User,
-		Password: req.Password,
-		DBName:   req.DBName,
-		SSLMode:  req.SSLMode,
+	cfg, ok := h.resolveConfig(c, req.TestConnectionRequest)
+	if !ok {
+		return
+	}
+
+	results, err := h.syncService.CompareData(cfg, req.Policy)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{"code": 1, "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"code": 0, "data": results})
+}
+
+// DiffRequest 行级对比请求
+type DiffRequest struct {
+	TestConnectionRequest
+	Tables []string `json:"tables"` // 要对比的表，为空时对比全部同步表
+}
+
+// Diff 逐行对比本地与云端数据
+// 返回各表仅本地存在、仅云端存在及内容不同的记录ID，以及部分字段级差异。
+// @Router /api/v1/sync/diff [post]
+func (h *SyncHandler) Diff(c *gin.Context) {
+	var req DiffRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": "参数错误: " + err.Error()})
+		return
+	}
+
+	cfg, ok := h.resolveConfig(c, req.TestConnectionRequest)
+	if !ok {
+		return
 	}
 
-	results, err := h.syncService.CompareData(cfg)
+	results, err := h.syncService.DiffData(cfg, req.Tables)
 	if err != nil {
 		c.JSON(http.StatusOK, gin.H{"code": 1, "message": err.Error()})
 		return
@@ -109,14 +205,11 @@ func (h *SyncHandler) Compare(c *gin.Context) {
 
 // ExecuteRequest 执行同步请求
 type ExecuteRequest struct {
-	DBType   string   `json:"db_type" binding:"required"`
-	Host     string   `json:"host" binding:"required"`
-	Port     int      `json:"port" binding:"required"`
-	User     string   `json:"user" binding:"required"`
-	Password string   `json:"password" binding:"required"`
-	DBName   string   `json:"db_name" binding:"required"`
-	SSLMode  string   `json:"ssl_mode"`
-	Tables   []string `json:"tables" binding:"required"` // 要同步的表列表
+	TestConnectionRequest
+	Tables []string `json:"tables" binding:"required"` // 要同步的表列表
+	Full   bool     `json:"full"`                      // 是否强制全量同步
+	Mode   string   `json:"mode"`                      // 同步方向: push/pull/merge
+	Policy string   `json:"policy"`                    // 冲突解决策略
 }
 
 // Execute 执行数据同步
@@ -128,17 +221,17 @@ func (h *SyncHandler) Execute(c *gin.Context) {
 		return
 	}
 
-	cfg := service.SyncConfig{
-		DBType:   req.DBType,
-		Host:     req.Host,
-		Port:     req.Port,
-		User:     req.User,
-		Password: req.Password,
-		DBName:   req.DBName,
-		SSLMode:  req.SSLMode,
+	cfg, ok := h.resolveExecuteConfig(c, req)
+	if !ok {
+		return
 	}
 
-	results, err := h.syncService.SyncTables(cfg, req.Tables)
+	results, err := h.syncService.RunSync(c.Request.Context(), service.SyncTriggerManual, cfg, service.SyncOptions{
+		Tables: req.Tables,
+		Full:   req.Full,
+		Mode:   req.Mode,
+		Policy: req.Policy,
+	})
 	if err != nil {
 		c.JSON(http.StatusOK, gin.H{"code": 1, "message": err.Error()})
 		return
@@ -146,3 +239,154 @@ func (h *SyncHandler) Execute(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"code": 0, "data": results, "message": "同步完成"})
 }
+
+// SubmitJob 提交后台同步任务
+// 立即返回任务信息，通过 GetJob 轮询或 JobEvents 订阅进度。
+// @Router /api/v1/sync/jobs [post]
+func (h *SyncHandler) SubmitJob(c *gin.Context) {
+	var req ExecuteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": "参数错误: " + err.Error()})
+		return
+	}
+
+	cfg, ok := h.resolveExecuteConfig(c, req)
+	if !ok {
+		return
+	}
+
+	job := h.syncService.SubmitJob(cfg, service.SyncOptions{
+		Tables: req.Tables,
+		Full:   req.Full,
+		Mode:   req.Mode,
+		Policy: req.Policy,
+	})
+	c.JSON(http.StatusOK, gin.H{"code": 0, "data": job, "message": "同步任务已提交"})
+}
+
+// GetJob 查询同步任务状态与进度
+// @Router /api/v1/sync/jobs/{id} [get]
+func (h *SyncHandler) GetJob(c *gin.Context) {
+	job, err := h.syncService.GetJob(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "data": job})
+}
+
+// CancelJob 取消同步任务
+// @Router /api/v1/sync/jobs/{id}/cancel [post]
+func (h *SyncHandler) CancelJob(c *gin.Context) {
+	job, err := h.syncService.CancelJob(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "data": job, "message": "已请求取消"})
+}
+
+// JobEvents 以 Server-Sent Events 推送同步任务进度
+// 连接建立后先推送一次当前状态 (progress 事件)，之后每次进度变化推送 progress 事件，
+// 任务结束时推送 done 事件并关闭连接。
+// @Router /api/v1/sync/jobs/{id}/events [get]
+func (h *SyncHandler) JobEvents(c *gin.Context) {
+	id := c.Param("id")
+	updates, unsubscribe, err := h.syncService.SubscribeJob(id)
+	if err != nil {
+		if errors.Is(err, service.ErrSyncJobNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"code": 1, "message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"code": 1, "message": err.Error()})
+		return
+	}
+	defer unsubscribe()
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+
+	first := true
+	c.Stream(func(w io.Writer) bool {
+		if first {
+			first = false
+			if job, err := h.syncService.GetJob(id); err == nil {
+				c.SSEvent("progress", job)
+			}
+			return true
+		}
+
+		select {
+		case _, ok := <-updates:
+			job, err := h.syncService.GetJob(id)
+			if err != nil {
+				return false
+			}
+			if !ok {
+				c.SSEvent("done", job)
+				return false
+			}
+			c.SSEvent("progress", job)
+			return true
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
+
+// SchemaRequest 云端表结构初始化请求
+type SchemaRequest struct {
+	TestConnectionRequest
+	Tables []string `json:"tables"`  // 要创建的表，为空时创建全部同步表
+	DryRun bool     `json:"dry_run"` // 仅返回将要执行的 DDL，不修改云端
+}
+
+// Schema 在云端创建或迁移同步表结构
+// @Router /api/v1/sync/schema [post]
+func (h *SyncHandler) Schema(c *gin.Context) {
+	var req SchemaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": "参数错误: " + err.Error()})
+		return
+	}
+
+	cfg, ok := h.resolveConfig(c, req.TestConnectionRequest)
+	if !ok {
+		return
+	}
+
+	statements, err := h.syncService.ProvisionSchema(cfg, req.Tables, req.DryRun)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{"code": 1, "message": err.Error(), "data": statements})
+		return
+	}
+
+	message := "云端表结构已就绪"
+	if req.DryRun {
+		message = "预览完成，未修改云端"
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "data": statements, "message": message})
+}
+
+// ListRuns 分页获取同步执行记录
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(10)
+// @Param status query string false "状态筛选"
+// @Router /api/v1/sync/runs [get]
+func (h *SyncHandler) ListRuns(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	runs, total, err := h.syncService.ListRuns(c.Query("status"), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{"code": 1, "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"code": 0, "data": response.PageData{
+		List:     runs,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}})
+}
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/pkg/response"
+	"github.com/FruitsAI/Orange/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// SyncProfileHandler 同步连接配置接口处理器
+// 连接配置保存在本地数据库，密码加密存储且不会通过接口返回。
+// 列表对所有登录用户开放 (用于选择配置)，增删改需要 sync.manage 权限。
+type SyncProfileHandler struct {
+	profileService *service.SyncProfileService
+}
+
+// NewSyncProfileHandler 创建同步连接配置处理器实例
+func NewSyncProfileHandler() *SyncProfileHandler {
+	return &SyncProfileHandler{
+		profileService: service.NewSyncProfileService(),
+	}
+}
+
+// List 获取连接配置列表
+// @Summary 获取同步连接配置列表
+// @Tags Sync
+// @Security Bearer
+// @Success 200 {array} service.SyncProfileView
+// @Router /api/v1/sync/profiles [get]
+func (h *SyncProfileHandler) List(c *gin.Context) {
+	profiles, err := h.profileService.List()
+	if err != nil {
+		response.InternalError(c, "获取连接配置失败")
+		return
+	}
+
+	response.Success(c, profiles)
+}
+
+// Create 新增连接配置
+// @Summary 新增同步连接配置
+// @Description 新增云端数据库连接配置，密码加密存储(需要 sync.manage 权限)
+// @Tags Sync
+// @Security Bearer
+// @Param profile body dto.SyncProfileRequest true "连接配置"
+// @Success 200 {object} service.SyncProfileView
+// @Failure 403 {string} string "无权操作"
+// @Router /api/v1/sync/profiles [post]
+func (h *SyncProfileHandler) Create(c *gin.Context) {
+	var req dto.SyncProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+	if req.DBType != "sqlite" && req.Password == "" {
+		response.ParamError(c, "密码不能为空")
+		return
+	}
+
+	profile, err := h.profileService.Create(toSyncProfileInput(req), c.GetInt64("user_id"))
+	if err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+
+	response.Success(c, profile)
+}
+
+// Update 更新连接配置
+// @Summary 更新同步连接配置
+// @Description 更新云端数据库连接配置，密码留空则保留原密码(需要 sync.manage 权限)
+// @Tags Sync
+// @Security Bearer
+// @Param id path int true "配置ID"
+// @Param profile body dto.SyncProfileRequest true "连接配置"
+// @Success 200 {object} service.SyncProfileView
+// @Failure 403 {string} string "无权操作"
+// @Router /api/v1/sync/profiles/{id} [put]
+func (h *SyncProfileHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的配置ID")
+		return
+	}
+
+	var req dto.SyncProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+
+	profile, err := h.profileService.Update(id, toSyncProfileInput(req))
+	if errors.Is(err, service.ErrSyncProfileNotFound) {
+		response.NotFound(c, err.Error())
+		return
+	}
+	if err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+
+	response.Success(c, profile)
+}
+
+// Delete 删除连接配置
+// @Summary 删除同步连接配置
+// @Tags Sync
+// @Security Bearer
+// @Param id path int true "配置ID"
+// @Success 200 {string} string "删除成功"
+// @Failure 403 {string} string "无权操作"
+// @Router /api/v1/sync/profiles/{id} [delete]
+func (h *SyncProfileHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的配置ID")
+		return
+	}
+
+	if err := h.profileService.Delete(id); err != nil {
+		if errors.Is(err, service.ErrSyncProfileNotFound) {
+			response.NotFound(c, err.Error())
+			return
+		}
+		response.InternalError(c, "删除连接配置失败")
+		return
+	}
+
+	response.SuccessWithMessage(c, "删除成功", nil)
+}
+
+// toSyncProfileInput 转换为服务层参数
+func toSyncProfileInput(req dto.SyncProfileRequest) service.SyncProfileInput {
+	return service.SyncProfileInput{
+		Name:     req.Name,
+		DBType:   req.DBType,
+		Path:     req.Path,
+		Host:     req.Host,
+		Port:     req.Port,
+		User:     req.User,
+		Password: req.Password,
+		DBName:   req.DBName,
+		SSLMode:  req.SSLMode,
+	}
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"strconv"
 
 	"github.com/FruitsAI/Orange/internal/dto"
@@ -20,22 +21,8 @@ func NewUserHandler() *UserHandler {
 	}
 }
 
-// ensureAdmin 检查当前用户是否为管理员
-func (h *UserHandler) ensureAdmin(c *gin.Context) bool {
-	role := middleware.GetRole(c)
-	if role != "admin" {
-		response.Error(c, response.CodeForbidden, "权限不足")
-		return false
-	}
-	return true
-}
-
 // List 获取用户列表
 func (h *UserHandler) List(c *gin.Context) {
-	if !h.ensureAdmin(c) {
-		return
-	}
-
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
 	keyword := c.Query("keyword")
@@ -51,18 +38,18 @@ func (h *UserHandler) List(c *gin.Context) {
 
 // Create 创建用户
 func (h *UserHandler) Create(c *gin.Context) {
-	if !h.ensureAdmin(c) {
-		return
-	}
-
 	var req dto.CreateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		response.ParamError(c, err.Error())
 		return
 	}
+	// 分配角色需要 role.manage 权限，否则新用户为普通用户
+	if !middleware.HasPermission(c, service.PermRoleManage) {
+		req.Role = ""
+	}
 
 	if err := h.authService.CreateUser(req); err != nil {
-		response.ParamError(c, err.Error())
+		passwordError(c, err)
 		return
 	}
 
@@ -71,10 +58,6 @@ func (h *UserHandler) Create(c *gin.Context) {
 
 // Update 更新用户
 func (h *UserHandler) Update(c *gin.Context) {
-	if !h.ensureAdmin(c) {
-		return
-	}
-
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
 		response.ParamError(c, "无效的用户ID")
@@ -86,8 +69,16 @@ func (h *UserHandler) Update(c *gin.Context) {
 		response.ParamError(c, err.Error())
 		return
 	}
+	// 调整角色需要 role.manage 权限，否则保留原角色
+	if !middleware.HasPermission(c, service.PermRoleManage) {
+		req.Role = ""
+	}
 
 	if err := h.authService.UpdateUser(id, req); err != nil {
+		if errors.Is(err, service.ErrRoleNotFound) || errors.Is(err, service.ErrDepartmentNotFound) || errors.Is(err, service.ErrPositionNotFound) {
+			response.ParamError(c, err.Error())
+			return
+		}
 		response.InternalError(c, err.Error())
 		return
 	}
@@ -97,10 +88,6 @@ func (h *UserHandler) Update(c *gin.Context) {
 
 // Delete 删除用户
 func (h *UserHandler) Delete(c *gin.Context) {
-	if !h.ensureAdmin(c) {
-		return
-	}
-
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
 		response.ParamError(c, "无效的用户ID")
@@ -124,10 +111,6 @@ func (h *UserHandler) Delete(c *gin.Context) {
 
 // ResetPassword 重置密码
 func (h *UserHandler) ResetPassword(c *gin.Context) {
-	if !h.ensureAdmin(c) {
-		return
-	}
-
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
 		response.ParamError(c, "无效的用户ID")
@@ -141,9 +124,99 @@ func (h *UserHandler) ResetPassword(c *gin.Context) {
 	}
 
 	if err := h.authService.ResetPassword(id, req.NewPassword); err != nil {
-		response.InternalError(c, err.Error())
+		passwordError(c, err)
 		return
 	}
 
 	response.SuccessWithMessage(c, "密码重置成功", nil)
 }
+
+// ListSessions 获取指定用户的登录会话
+func (h *UserHandler) ListSessions(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的用户ID")
+		return
+	}
+
+	sessions, err := h.authService.ListSessions(id, middleware.GetSessionID(c))
+	if err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+
+	response.Success(c, sessions)
+}
+
+// RevokeSession 强制下线指定用户的登录会话
+func (h *UserHandler) RevokeSession(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的用户ID")
+		return
+	}
+	sessionID, err := strconv.ParseInt(c.Param("sid"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的会话ID")
+		return
+	}
+
+	if err := h.authService.RevokeSession(id, sessionID); err != nil {
+		if errors.Is(err, service.ErrSessionNotFound) {
+			response.NotFound(c, err.Error())
+			return
+		}
+		response.InternalError(c, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "已下线", nil)
+}
+
+// RevokeAllSessions 强制下线指定用户的全部登录会话
+func (h *UserHandler) RevokeAllSessions(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的用户ID")
+		return
+	}
+
+	if err := h.authService.RevokeAll(id); err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "已全部下线", nil)
+}
+
+// ResetTwoFactor 重置用户的两步验证 (用户丢失身份验证器时由管理员操作)
+func (h *UserHandler) ResetTwoFactor(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的用户ID")
+		return
+	}
+
+	if err := h.authService.ResetTwoFactor(id); err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "两步验证已重置", nil)
+}
+
+// Unlock 解除用户的登录锁定
+func (h *UserHandler) Unlock(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的用户ID")
+		return
+	}
+
+	if err := h.authService.UnlockUser(id, middleware.GetUserID(c)); err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "账户已解锁", nil)
+}
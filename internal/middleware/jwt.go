@@ -1,10 +1,12 @@
 package middleware
 
 import (
+	"errors"
 	"strings"
 
 	"github.com/FruitsAI/Orange/internal/pkg/jwt"
 	"github.com/FruitsAI/Orange/internal/pkg/response"
+	"github.com/FruitsAI/Orange/internal/service"
 	"github.com/gin-gonic/gin"
 )
 
@@ -12,7 +14,11 @@ import (
 // 拦截 HTTP 请求，验证 Request Header 中的 Authorization 字段。
 // 仅允许携带有效 Bearer Token 的请求通过，否则返回 401 Unauthorized。
 // 验证通过后，将用户信息(ID, Username, Role) 解析并存入 Gin Context，供后续 Handler 使用。
+// 除签名与有效期外，还会核对用户状态、令牌版本与登录会话，已禁用或令牌/会话已被吊销的请求同样拒绝。
+// 通过后当前会话ID (session_id) 同样存入 Context。
+// 以 orp_ 开头的 Bearer Token 按个人访问令牌校验，见 personalTokenAuth。
 func JWTAuth() gin.HandlerFunc {
+	authService := service.NewAuthService()
 	return func(c *gin.Context) {
 		// 1. 从 Header 获取 Token
 		authHeader := c.GetHeader("Authorization")
@@ -29,6 +35,10 @@ func JWTAuth() gin.HandlerFunc {
 		}
 
 		tokenString := parts[1]
+		if strings.HasPrefix(tokenString, service.PersonalTokenPrefix) {
+			personalTokenAuth(c, authService, tokenString)
+			return
+		}
 
 		// 3. 校验并解析 Token
 		claims, err := jwt.ParseToken(tokenString)
@@ -38,15 +48,97 @@ func JWTAuth() gin.HandlerFunc {
 			return
 		}
 
-		// 4. 将用户信息注入上下文 (Context)
+		// 4. 校验令牌是否已被吊销 (退出登录、会话被踢下线、修改密码、账户禁用)
+		session, err := authService.CheckAccessToken(claims)
+		if errors.Is(err, service.ErrPasswordChangeRequired) || errors.Is(err, service.ErrTwoFactorSetupRequired) {
+			// 密码已过期或被要求启用两步验证的用户，仅允许访问个人信息与修改密码 / 两步验证接口
+			if !restrictedPathAllowed(err, c.FullPath()) {
+				response.Forbidden(c, err.Error())
+				return
+			}
+		} else if err != nil {
+			response.Error(c, response.CodeTokenExpired, err.Error())
+			c.Abort()
+			return
+		}
+
+		// 5. 将用户信息注入上下文 (Context)
 		c.Set("user_id", claims.UserID)
 		c.Set("username", claims.Username)
 		c.Set("role", claims.Role)
+		c.Set("session_id", session.ID)
 
 		c.Next()
 	}
 }
 
+// personalTokenAuth 个人访问令牌鉴权
+// 令牌只能访问授权范围覆盖的业务接口 (见 personalTokenScope)，账户、会话与系统管理类接口一律拒绝。
+// 通过后以令牌所属用户的身份继续处理，session_id 为 0。
+func personalTokenAuth(c *gin.Context, authService *service.AuthService, tokenString string) {
+	user, scopes, err := authService.CheckPersonalToken(tokenString, c.ClientIP())
+	if errors.Is(err, service.ErrPasswordChangeRequired) || errors.Is(err, service.ErrTwoFactorSetupRequired) {
+		response.Forbidden(c, err.Error())
+		return
+	} else if err != nil {
+		response.Unauthorized(c, err.Error())
+		return
+	}
+
+	required := personalTokenScope(c.Request.Method, c.FullPath())
+	if required == "" || !service.ScopeAllows(scopes, required) {
+		response.Forbidden(c, "令牌无权访问该接口")
+		return
+	}
+
+	c.Set("user_id", user.ID)
+	c.Set("username", user.Username)
+	c.Set("role", user.Role)
+	c.Set("session_id", int64(0))
+	c.Next()
+}
+
+// personalTokenResources 个人访问令牌可访问的接口前缀与对应资源
+var personalTokenResources = map[string]string{
+	"/api/v1/projects":      "projects",
+	"/api/v1/payments":      "payments",
+	"/api/v1/dashboard":     "dashboard",
+	"/api/v1/dictionaries":  "dictionaries",
+	"/api/v1/notifications": "notifications",
+}
+
+// personalTokenScope 访问接口所需的授权范围，返回空字符串表示令牌不可访问
+// GET/HEAD 请求需要 资源:read，其余请求需要 资源:write。
+func personalTokenScope(method, path string) string {
+	if path == "/api/v1/users/me" {
+		if method == "GET" || method == "HEAD" {
+			return "profile:read"
+		}
+		return ""
+	}
+	for prefix, resource := range personalTokenResources {
+		if path != prefix && !strings.HasPrefix(path, prefix+"/") {
+			continue
+		}
+		if method == "GET" || method == "HEAD" {
+			return resource + ":read"
+		}
+		return resource + ":write"
+	}
+	return ""
+}
+
+// restrictedPathAllowed 密码已过期或尚未按要求启用两步验证时允许访问的接口
+func restrictedPathAllowed(err error, path string) bool {
+	if path == "/api/v1/users/me" {
+		return true
+	}
+	if errors.Is(err, service.ErrPasswordChangeRequired) {
+		return path == "/api/v1/users/me/password"
+	}
+	return strings.HasPrefix(path, "/api/v1/users/me/2fa")
+}
+
 // GetUserID 从上下文获取用户ID
 func GetUserID(c *gin.Context) int64 {
 	if userID, exists := c.Get("user_id"); exists {
@@ -63,6 +155,14 @@ func GetUsername(c *gin.Context) string {
 	return ""
 }
 
+// GetSessionID 从上下文获取当前登录会话ID
+func GetSessionID(c *gin.Context) int64 {
+	if sessionID, exists := c.Get("session_id"); exists {
+		return sessionID.(int64)
+	}
+	return 0
+}
+
 // GetRole 从上下文获取角色
 func GetRole(c *gin.Context) string {
 	if role, exists := c.Get("role"); exists {
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"github.com/FruitsAI/Orange/internal/pkg/response"
+	"github.com/FruitsAI/Orange/internal/repository"
+	"github.com/FruitsAI/Orange/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// permissionsKey 当前请求的权限集合在 Context 中的键
+const permissionsKey = "permissions"
+
+// dataScopeKey 当前请求的数据范围在 Context 中的键
+const dataScopeKey = "data_scope"
+
+// RequirePermission 权限校验中间件
+// 须挂载在 JWTAuth 之后，当前用户的角色须拥有全部指定权限，否则返回 403。
+func RequirePermission(permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, p := range permissions {
+			if !HasPermission(c, p) {
+				response.Forbidden(c, "无权操作")
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// RequireAnyPermission 权限校验中间件，当前用户的角色拥有任一指定权限即可
+func RequireAnyPermission(permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, p := range permissions {
+			if HasPermission(c, p) {
+				c.Next()
+				return
+			}
+		}
+		response.Forbidden(c, "无权操作")
+	}
+}
+
+// HasPermission 判断当前用户是否拥有指定权限
+// 首次调用时解析角色的权限集合并存入 Context，同一请求内的后续判断不再查询。
+func HasPermission(c *gin.Context, permission string) bool {
+	return GetPermissions(c)[permission]
+}
+
+// GetPermissions 从上下文获取当前用户的权限集合
+func GetPermissions(c *gin.Context) map[string]bool {
+	if perms, exists := c.Get(permissionsKey); exists {
+		return perms.(map[string]bool)
+	}
+	perms := service.NewRoleService().RolePermissions(GetRole(c)) // 角色权限缓存在进程内，见 RoleService
+	if perms == nil {
+		perms = map[string]bool{}
+	}
+	c.Set(permissionsKey, perms)
+	return perms
+}
+
+// GetDataScope 从上下文获取当前用户的数据范围
+// 首次调用时按角色的数据范围与用户所属部门解析并存入 Context。
+func GetDataScope(c *gin.Context) repository.DataScope {
+	if scope, exists := c.Get(dataScopeKey); exists {
+		return scope.(repository.DataScope)
+	}
+	scope := service.NewDataScopeService().Resolve(GetUserID(c), GetRole(c))
+	c.Set(dataScopeKey, scope)
+	return scope
+}
+
+// GetProjectActor 从上下文获取项目与收款操作的发起人 (用户、是否可管理全部项目及数据范围)
+func GetProjectActor(c *gin.Context) service.ProjectActor {
+	return service.ProjectActor{
+		UserID:  GetUserID(c),
+		Manager: HasPermission(c, service.PermProjectManage),
+		Scope:   GetDataScope(c),
+	}
+}
@@ -8,20 +8,28 @@ import (
 // 对应可能是系统管理员或普通员工。
 // 包含用户的基本信息、登录凭证（密码Hash）以及角色权限信息。
 type User struct {
-	ID            int64      `json:"id" gorm:"primaryKey;autoIncrement"`
-	Username      string     `json:"username" gorm:"size:50;not null;uniqueIndex"` // 用户名，唯一
-	Password      string     `json:"-" gorm:"size:100;not null"`                   // 密码 Hash 值，JSON 序列化时忽略
-	Name          string     `json:"name" gorm:"size:50;not null"`                 // 真实姓名
-	Email         string     `json:"email" gorm:"size:100"`                        // 邮箱
-	Phone         string     `json:"phone" gorm:"size:20"`                         // 手机号
-	Avatar        string     `json:"avatar" gorm:"size:255"`                       // 头像 URL
-	Role          string     `json:"role" gorm:"size:20;not null;default:'user'"`  // 角色: admin, user
-	Department    string     `json:"department" gorm:"size:50"`                    // 部门
-	Position      string     `json:"position" gorm:"size:50"`                      // 职位
-	Status        int        `json:"status" gorm:"default:1"`                      // 状态: 1=正常, 0=禁用
-	LastLoginTime *time.Time `json:"last_login_time"`                              // 最后登录时间
-	CreateTime    time.Time  `json:"create_time" gorm:"autoCreateTime"`            // 创建时间
-	UpdateTime    time.Time  `json:"update_time" gorm:"autoUpdateTime"`            // 更新时间
+	ID                 int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	Username           string     `json:"username" gorm:"size:50;not null;uniqueIndex"`        // 用户名，唯一
+	Password           string     `json:"-" gorm:"size:100;not null"`                          // 密码 Hash 值，JSON 序列化时忽略
+	Name               string     `json:"name" gorm:"size:50;not null"`                        // 真实姓名
+	Email              string     `json:"email" gorm:"size:100"`                               // 邮箱
+	Phone              string     `json:"phone" gorm:"size:20"`                                // 手机号
+	Avatar             string     `json:"avatar" gorm:"size:255"`                              // 头像 URL
+	Role               string     `json:"role" gorm:"size:20;not null;default:'user'"`         // 角色: admin, user
+	Department         string     `json:"department" gorm:"size:50"`                           // 部门名称 (随 departments 表同步，便于展示)
+	DepartmentID       int64      `json:"department_id" gorm:"not null;default:0;index"`       // 所属部门ID (与 Department 同步维护，0 表示未分配)
+	Position           string     `json:"position" gorm:"size:50"`                             // 职位名称 (随 positions 表同步，便于展示)
+	PositionID         int64      `json:"position_id" gorm:"not null;default:0;index"`         // 职位ID (与 Position 同步维护，0 表示未分配)
+	Status             int        `json:"status" gorm:"default:1"`                             // 状态: 1=正常, 0=禁用
+	LastLoginTime      *time.Time `json:"last_login_time"`                                     // 最后登录时间
+	TokenVersion       int        `json:"-" gorm:"not null;default:0"`                         // 令牌版本，递增后已签发的访问令牌全部失效
+	TwoFactorEnabled   bool       `json:"two_factor_enabled" gorm:"not null;default:false"`    // 是否已启用两步验证 (TOTP)
+	TOTPSecret         string     `json:"-" gorm:"column:totp_secret;size:255"`                // TOTP 密钥 (本机密钥加密)，启用前为待绑定密钥
+	TOTPLastStep       int64      `json:"-" gorm:"column:totp_last_step;not null;default:0"`   // 最近一次使用的 TOTP 时间步，防止验证码重放
+	PasswordChangeTime *time.Time `json:"password_change_time"`                                // 最近修改密码时间 (用于密码有效期)
+	AuthSource         string     `json:"auth_source" gorm:"size:20;not null;default:'local'"` // 认证来源: local (本地密码), ldap (目录账户), oidc (单点登录账户)
+	CreateTime         time.Time  `json:"create_time" gorm:"autoCreateTime"`                   // 创建时间
+	UpdateTime         time.Time  `json:"update_time" gorm:"autoUpdateTime"`                   // 更新时间
 }
 
 // TableName 指定表名
@@ -85,6 +93,25 @@ func (Payment) TableName() string {
 	return "payments"
 }
 
+// ProjectMember 项目成员
+// 项目负责人 (projects.user_id) 即为所有者，不记录在成员表中；成员按项目角色参与协作。
+type ProjectMember struct {
+	ID         int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	ProjectID  int64     `json:"project_id" gorm:"not null;uniqueIndex:idx_project_members_project_user"`    // 项目ID
+	UserID     int64     `json:"user_id" gorm:"not null;uniqueIndex:idx_project_members_project_user;index"` // 成员用户ID
+	Role       string    `json:"role" gorm:"size:20;not null"`                                               // 项目角色: editor, viewer, finance
+	CreateTime time.Time `json:"create_time" gorm:"autoCreateTime"`                                          // 加入时间
+	UpdateTime time.Time `json:"update_time" gorm:"autoUpdateTime"`                                          // 更新时间
+
+	// 关联
+	User *User `json:"user,omitempty" gorm:"foreignKey:UserID"` // 成员信息
+}
+
+// TableName 指定表名
+func (ProjectMember) TableName() string {
+	return "project_members"
+}
+
 // Dictionary 字典主表 (分类)
 // 用于管理系统中的枚举值配置，如项目类型、支付方式等。
 type Dictionary struct {
@@ -152,11 +179,406 @@ type UserNotification struct {
 	ID             int64      `json:"id" gorm:"primaryKey;autoIncrement"`
 	UserID         int64      `json:"user_id" gorm:"not null;uniqueIndex:idx_user_notification"`
 	NotificationID int64      `json:"notification_id" gorm:"not null;uniqueIndex:idx_user_notification"`
-	IsRead         int        `json:"is_read" gorm:"default:0"` // 阅读状态: 0:未读, 1:已读
-	ReadTime       *time.Time `json:"read_time"`                // 阅读时间
+	IsRead         int        `json:"is_read" gorm:"default:0"`          // 阅读状态: 0:未读, 1:已读
+	ReadTime       *time.Time `json:"read_time"`                         // 阅读时间
+	UpdateTime     time.Time  `json:"update_time" gorm:"autoUpdateTime"` // 更新时间 (用于增量同步)
 }
 
 // TableName 指定表名
 func (UserNotification) TableName() string {
 	return "user_notifications"
 }
+
+// SyncState 同步状态 (增量同步水位线)
+// 按 "云端目标 + 表" 记录最近一次成功同步时已推送/已拉取数据的高水位。
+// 本地变更按本机的 update_time / delete_time 判断；云端变更按云端分配的变更序号判断 (见 SyncChange)，
+// 不依赖其他设备的时钟。
+type SyncState struct {
+	ID                int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	Target            string     `json:"target" gorm:"size:255;not null;uniqueIndex:idx_sync_state_target_table"`                      // 同步目标标识 (db_type://user@host:port/db_name)
+	Table             string     `json:"table_name" gorm:"column:table_name;size:50;not null;uniqueIndex:idx_sync_state_target_table"` // 表名
+	LastUpdateTime    *time.Time `json:"last_update_time"`                                                                             // 已推送记录的最大 update_time
+	LastTombstoneTime *time.Time `json:"last_tombstone_time"`                                                                          // 已推送删除墓碑的最大 delete_time
+	LastRemoteSeq     int64      `json:"last_remote_seq" gorm:"not null;default:0"`                                                    // 已拉取的云端变更序号
+	LastRemoteUpdate  *time.Time `json:"last_remote_update"`                                                                           // 已拉取云端记录的最大 update_time (云端启用变更序号前的水位线)
+	LastRemoteDelete  *time.Time `json:"last_remote_delete"`                                                                           // 已拉取云端删除墓碑的最大 delete_time (云端启用变更序号前的水位线)
+	LastSyncTime      *time.Time `json:"last_sync_time"`                                                                               // 最近一次成功同步时间
+	UpdateTime        time.Time  `json:"update_time" gorm:"autoUpdateTime"`                                                            // 更新时间
+}
+
+// TableName 指定表名
+func (SyncState) TableName() string {
+	return "sync_states"
+}
+
+// SyncTombstone 删除墓碑
+// 业务表中的记录被删除时写入一条墓碑，增量同步据此将删除操作推送到云端。
+type SyncTombstone struct {
+	ID         int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	Table      string    `json:"table_name" gorm:"column:table_name;size:50;not null;uniqueIndex:idx_sync_tombstone_record"` // 被删除记录所在表
+	RecordID   int64     `json:"record_id" gorm:"not null;uniqueIndex:idx_sync_tombstone_record"`                            // 被删除记录ID
+	DeleteTime time.Time `json:"delete_time" gorm:"not null;index"`                                                          // 删除时间
+}
+
+// TableName 指定表名
+func (SyncTombstone) TableName() string {
+	return "sync_tombstones"
+}
+
+// SyncChange 云端变更日志 (仅在云端库中创建)
+// 每次推送为写入或删除的记录登记云端分配的变更序号，每条记录只保留最近一次的序号。
+// 拉取时按序号而不是记录的 update_time 查找新变更，其他设备的时钟偏差或延迟推送不会导致漏拉。
+type SyncChange struct {
+	ID       int64  `json:"id" gorm:"primaryKey;autoIncrement"`
+	Table    string `json:"table_name" gorm:"column:table_name;size:50;not null;uniqueIndex:idx_sync_change_record;index:idx_sync_change_seq,priority:1"` // 变更记录所在表
+	RecordID int64  `json:"record_id" gorm:"not null;uniqueIndex:idx_sync_change_record"`                                                                 // 变更记录ID
+	Seq      int64  `json:"seq" gorm:"not null;index:idx_sync_change_seq,priority:2"`                                                                     // 变更序号
+}
+
+// TableName 指定表名
+func (SyncChange) TableName() string {
+	return "sync_changes"
+}
+
+// SyncSequence 云端变更序号计数器 (仅在云端库中创建，只有 ID 为 1 的一行)
+// 推送事务中递增并持有行锁直到提交，多个设备同时推送时序号按提交顺序分配。
+type SyncSequence struct {
+	ID  int64 `json:"id" gorm:"primaryKey"`
+	Seq int64 `json:"seq" gorm:"not null;default:0"` // 已分配的最大变更序号
+}
+
+// TableName 指定表名
+func (SyncSequence) TableName() string {
+	return "sync_sequences"
+}
+
+// SyncRun 同步执行记录
+// 每次手动或定时同步执行后写入一条，用于查看同步历史。
+type SyncRun struct {
+	ID            int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	Trigger       string     `json:"trigger" gorm:"size:20;not null"`      // 触发方式: manual (手动), schedule (定时)
+	Mode          string     `json:"mode" gorm:"size:20;not null"`         // 同步方向: push, pull, merge
+	Tables        string     `json:"tables" gorm:"type:text"`              // 同步的表 (逗号分隔)
+	Status        string     `json:"status" gorm:"size:20;not null;index"` // 状态: running, success, partial, failed, skipped, canceled
+	Attempts      int        `json:"attempts" gorm:"default:1"`            // 尝试次数 (含连接失败后的重试)
+	SyncedCount   int64      `json:"synced_count" gorm:"default:0"`        // 推送到云端的记录数
+	PulledCount   int64      `json:"pulled_count" gorm:"default:0"`        // 拉取到本地的记录数
+	DeletedCount  int64      `json:"deleted_count" gorm:"default:0"`       // 删除的记录数 (云端 + 本地)
+	ConflictCount int64      `json:"conflict_count" gorm:"default:0"`      // 冲突记录数
+	ErrorMessage  string     `json:"error_message" gorm:"type:text"`       // 错误信息
+	Detail        string     `json:"detail" gorm:"type:text"`              // 各表同步结果 (JSON)
+	StartTime     time.Time  `json:"start_time" gorm:"not null;index"`     // 开始时间
+	FinishTime    *time.Time `json:"finish_time"`                          // 结束时间
+}
+
+// TableName 指定表名
+func (SyncRun) TableName() string {
+	return "sync_runs"
+}
+
+// SyncProfile 云端同步连接配置
+// 保存常用的云端数据库连接，密码使用本机密钥加密存储，不通过接口返回。
+type SyncProfile struct {
+	ID         int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name       string    `json:"name" gorm:"size:100;uniqueIndex;not null"` // 配置名称
+	DBType     string    `json:"db_type" gorm:"size:20;not null"`           // 数据库类型: postgres, mysql, sqlite
+	Path       string    `json:"path" gorm:"size:500"`                      // SQLite 文件路径 (仅 sqlite)
+	Host       string    `json:"host" gorm:"size:255"`                      // 主机地址
+	Port       int       `json:"port"`                                      // 端口号
+	User       string    `json:"user" gorm:"size:100"`                      // 用户名
+	Password   string    `json:"-" gorm:"type:text"`                        // 密码 (密文)
+	DBName     string    `json:"db_name" gorm:"size:100"`                   // 数据库名
+	SSLMode    string    `json:"ssl_mode" gorm:"size:20"`                   // SSL 模式 (postgres)
+	CreatedBy  int64     `json:"created_by"`                                // 创建人ID
+	CreateTime time.Time `json:"create_time" gorm:"autoCreateTime"`         // 创建时间
+	UpdateTime time.Time `json:"update_time" gorm:"autoUpdateTime"`         // 更新时间
+}
+
+// TableName 指定表名
+func (SyncProfile) TableName() string {
+	return "sync_profiles"
+}
+
+// RefreshToken 刷新令牌
+// 仅保存令牌的 SHA-256 哈希。每次刷新都会吊销旧令牌并签发新令牌 (轮换)，
+// 已吊销的令牌再次被使用视为泄露，吊销该用户的全部令牌。
+type RefreshToken struct {
+	ID         int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID     int64      `json:"user_id" gorm:"not null;index"`         // 用户ID
+	SessionID  int64      `json:"session_id" gorm:"index"`               // 所属登录会话ID
+	TokenHash  string     `json:"-" gorm:"size:64;not null;uniqueIndex"` // 令牌哈希 (hex)
+	ExpireTime time.Time  `json:"expire_time" gorm:"not null"`           // 过期时间
+	RevokeTime *time.Time `json:"revoke_time"`                           // 吊销时间 (为空表示有效)
+	CreateTime time.Time  `json:"create_time" gorm:"autoCreateTime"`     // 创建时间
+}
+
+// TableName 指定表名
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
+
+// UserSession 登录会话
+// 每次登录创建一个会话，刷新令牌时沿用同一会话并更新绑定的访问令牌 jti。
+// 会话被吊销后，其访问令牌与刷新令牌立即失效。
+type UserSession struct {
+	ID           int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID       int64      `json:"user_id" gorm:"not null;index"`         // 用户ID
+	TokenID      string     `json:"-" gorm:"size:36;not null;uniqueIndex"` // 当前访问令牌的 jti
+	DeviceName   string     `json:"device_name" gorm:"size:100"`           // 设备名称
+	ClientIP     string     `json:"client_ip" gorm:"size:64"`              // 客户端 IP
+	UserAgent    string     `json:"user_agent" gorm:"size:255"`            // User-Agent
+	LastSeenTime time.Time  `json:"last_seen_time"`                        // 最近活跃时间
+	RevokeTime   *time.Time `json:"revoke_time"`                           // 吊销时间 (为空表示有效)
+	CreateTime   time.Time  `json:"create_time" gorm:"autoCreateTime"`     // 登录时间
+}
+
+// TableName 指定表名
+func (UserSession) TableName() string {
+	return "user_sessions"
+}
+
+// RecoveryCode 两步验证恢复码
+// 丢失身份验证器时用于登录，每个恢复码只能使用一次，仅保存哈希。
+type RecoveryCode struct {
+	ID         int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID     int64      `json:"user_id" gorm:"not null;index"`     // 用户ID
+	CodeHash   string     `json:"-" gorm:"size:64;not null;index"`   // 恢复码哈希 (hex)
+	UsedTime   *time.Time `json:"used_time"`                         // 使用时间 (为空表示未使用)
+	CreateTime time.Time  `json:"create_time" gorm:"autoCreateTime"` // 创建时间
+}
+
+// TableName 指定表名
+func (RecoveryCode) TableName() string {
+	return "recovery_codes"
+}
+
+// LoginChallenge 两步验证登录挑战
+// 密码验证通过但尚未完成两步验证的登录，凭挑战令牌提交验证码，验证成功后删除。
+type LoginChallenge struct {
+	ID         int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID     int64     `json:"user_id" gorm:"not null;index"`         // 用户ID
+	TokenHash  string    `json:"-" gorm:"size:64;not null;uniqueIndex"` // 挑战令牌哈希 (hex)
+	DeviceName string    `json:"device_name" gorm:"size:100"`           // 登录时填写的设备名称
+	Attempts   int       `json:"attempts" gorm:"not null;default:0"`    // 已失败次数
+	ExpireTime time.Time `json:"expire_time" gorm:"not null"`           // 过期时间
+	CreateTime time.Time `json:"create_time" gorm:"autoCreateTime"`     // 创建时间
+}
+
+// TableName 指定表名
+func (LoginChallenge) TableName() string {
+	return "login_challenges"
+}
+
+// SystemSetting 系统设置 (键值对，值为 JSON)
+type SystemSetting struct {
+	Key        string    `json:"key" gorm:"primaryKey;size:64"` // 设置键
+	Value      string    `json:"value" gorm:"type:text"`        // 设置值 (JSON)
+	UpdateTime time.Time `json:"update_time" gorm:"autoUpdateTime"`
+}
+
+// TableName 指定表名
+func (SystemSetting) TableName() string {
+	return "system_settings"
+}
+
+// LoginThrottle 登录失败计数
+// 按账户 (Kind=user) 与客户端 IP (Kind=ip) 分别统计连续失败次数，达到上限后锁定一段时间。
+type LoginThrottle struct {
+	ID              int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	Kind            string     `json:"kind" gorm:"size:10;not null;uniqueIndex:idx_login_throttle_key"` // 类型: user, ip
+	Key             string     `json:"key" gorm:"size:128;not null;uniqueIndex:idx_login_throttle_key"` // 用户ID (或登录名) / IP
+	Failures        int        `json:"failures" gorm:"not null;default:0"`                              // 连续失败次数
+	LastFailureTime time.Time  `json:"last_failure_time"`                                               // 最近失败时间
+	LockedUntil     *time.Time `json:"locked_until"`                                                    // 锁定截止时间
+	UpdateTime      time.Time  `json:"update_time" gorm:"autoUpdateTime"`
+}
+
+// TableName 指定表名
+func (LoginThrottle) TableName() string {
+	return "login_throttles"
+}
+
+// AuditLog 安全审计日志
+type AuditLog struct {
+	ID         int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID     int64     `json:"user_id" gorm:"index"`                    // 相关用户ID (0 表示未知)
+	Username   string    `json:"username" gorm:"size:100"`                // 登录名 (用户不存在时为提交的登录名)
+	Action     string    `json:"action" gorm:"size:50;not null;index"`    // 事件类型，如 login_failed
+	ClientIP   string    `json:"client_ip" gorm:"size:64"`                // 客户端 IP
+	UserAgent  string    `json:"user_agent" gorm:"size:255"`              // User-Agent
+	Detail     string    `json:"detail" gorm:"size:255"`                  // 详情
+	OperatorID int64     `json:"operator_id"`                             // 操作人ID (管理员操作时)
+	CreateTime time.Time `json:"create_time" gorm:"autoCreateTime;index"` // 发生时间
+}
+
+// TableName 指定表名
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
+
+// PasswordHistory 密码历史
+// 每次设置密码时记录哈希，用于禁止重复使用最近的密码。
+type PasswordHistory struct {
+	ID           int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID       int64     `json:"user_id" gorm:"not null;index"`     // 用户ID
+	PasswordHash string    `json:"-" gorm:"size:100;not null"`        // 密码 Hash 值
+	CreateTime   time.Time `json:"create_time" gorm:"autoCreateTime"` // 设置时间
+}
+
+// TableName 指定表名
+func (PasswordHistory) TableName() string {
+	return "password_histories"
+}
+
+// UserIdentity 外部身份绑定
+// 记录 OIDC 等外部身份提供方的用户标识 (签发方 + sub) 与本地用户的对应关系。
+type UserIdentity struct {
+	ID         int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID     int64     `json:"user_id" gorm:"not null;index"`                                           // 用户ID
+	Provider   string    `json:"provider" gorm:"size:255;not null;uniqueIndex:idx_user_identity_subject"` // 身份提供方 (OIDC 签发方 URL)
+	Subject    string    `json:"subject" gorm:"size:255;not null;uniqueIndex:idx_user_identity_subject"`  // 提供方中的用户标识 (sub)
+	Email      string    `json:"email" gorm:"size:100"`                                                   // 最近一次登录时的邮箱
+	CreateTime time.Time `json:"create_time" gorm:"autoCreateTime"`                                       // 绑定时间
+	UpdateTime time.Time `json:"update_time" gorm:"autoUpdateTime"`                                       // 最近登录时间
+}
+
+// TableName 指定表名
+func (UserIdentity) TableName() string {
+	return "user_identities"
+}
+
+// OIDCLogin 进行中的 OIDC 单点登录
+// 发起登录时保存 state、nonce 与 PKCE code_verifier；回调验证通过后写入用户与一次性登录票据，
+// 前端凭票据换取令牌后删除。
+type OIDCLogin struct {
+	ID           int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	StateHash    string    `json:"-" gorm:"size:64;not null;uniqueIndex"` // state 哈希 (hex)
+	Nonce        string    `json:"-" gorm:"size:100;not null"`            // nonce
+	CodeVerifier string    `json:"-" gorm:"size:100;not null"`            // PKCE code_verifier
+	UserID       int64     `json:"user_id" gorm:"not null;default:0"`     // 回调验证通过的用户ID
+	TicketHash   string    `json:"-" gorm:"size:64;index"`                // 登录票据哈希 (hex)
+	ExpireTime   time.Time `json:"expire_time" gorm:"not null"`           // 过期时间
+	CreateTime   time.Time `json:"create_time" gorm:"autoCreateTime"`     // 创建时间
+}
+
+// TableName 指定表名
+func (OIDCLogin) TableName() string {
+	return "oidc_logins"
+}
+
+// PersonalAccessToken 个人访问令牌
+// 用于脚本与系统集成，以 Bearer 方式代替登录令牌访问授权范围内的接口。只保存令牌哈希，明文仅在创建时返回一次。
+type PersonalAccessToken struct {
+	ID           int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID       int64      `json:"user_id" gorm:"not null;index"`         // 用户ID
+	Name         string     `json:"name" gorm:"size:100;not null"`         // 令牌名称
+	TokenHash    string     `json:"-" gorm:"size:64;not null;uniqueIndex"` // 令牌哈希 (hex)
+	TokenPrefix  string     `json:"token_prefix" gorm:"size:16"`           // 令牌前缀 (便于识别，如 orp_AbCd)
+	Scopes       string     `json:"-" gorm:"size:500;not null"`            // 授权范围 (逗号分隔)
+	ExpireTime   *time.Time `json:"expire_time"`                           // 过期时间 (为空表示永不过期)
+	LastUsedTime *time.Time `json:"last_used_time"`                        // 最近使用时间
+	LastUsedIP   string     `json:"last_used_ip" gorm:"size:64"`           // 最近使用的客户端 IP
+	CreateTime   time.Time  `json:"create_time" gorm:"autoCreateTime"`     // 创建时间
+}
+
+// TableName 指定表名
+func (PersonalAccessToken) TableName() string {
+	return "personal_access_tokens"
+}
+
+// PasswordResetToken 找回密码的重置令牌
+// 通过邮件发送给用户，只保存令牌哈希；一次性使用，过期或使用后失效。发送记录同时用于按邮箱限制发送频率。
+type PasswordResetToken struct {
+	ID         int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID     int64      `json:"user_id" gorm:"not null;index"`         // 用户ID
+	Email      string     `json:"email" gorm:"size:100;not null;index"`  // 接收邮箱 (小写)
+	TokenHash  string     `json:"-" gorm:"size:64;not null;uniqueIndex"` // 令牌哈希 (hex)
+	RequestIP  string     `json:"request_ip" gorm:"size:64"`             // 申请重置的客户端 IP
+	ExpireTime time.Time  `json:"expire_time" gorm:"not null"`           // 过期时间
+	UsedTime   *time.Time `json:"used_time"`                             // 使用时间 (为空表示未使用)
+	CreateTime time.Time  `json:"create_time" gorm:"autoCreateTime"`     // 创建时间
+}
+
+// TableName 指定表名
+func (PasswordResetToken) TableName() string {
+	return "password_reset_tokens"
+}
+
+// Role 角色
+// 用户通过 users.role 引用角色编码，角色拥有的权限见 RolePermission。内置角色 (admin、user) 不可删除。
+type Role struct {
+	ID          int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	Code        string    `json:"code" gorm:"size:50;not null;uniqueIndex"`          // 角色编码 (users.role)
+	Name        string    `json:"name" gorm:"size:50;not null"`                      // 角色名称
+	Description string    `json:"description" gorm:"size:255"`                       // 描述
+	BuiltIn     bool      `json:"built_in" gorm:"not null;default:false"`            // 是否为内置角色
+	DataScope   string    `json:"data_scope" gorm:"size:30;not null;default:'self'"` // 数据范围: self, department, department_and_children, all
+	CreateTime  time.Time `json:"create_time" gorm:"autoCreateTime"`                 // 创建时间
+	UpdateTime  time.Time `json:"update_time" gorm:"autoUpdateTime"`                 // 更新时间
+}
+
+// TableName 指定表名
+func (Role) TableName() string {
+	return "roles"
+}
+
+// Permission 权限
+// 权限由程序定义 (编码格式为 模块.操作，如 project.delete)，随版本迁移写入，不可通过接口增删。
+type Permission struct {
+	ID     int64  `json:"id" gorm:"primaryKey;autoIncrement"`
+	Code   string `json:"code" gorm:"size:100;not null;uniqueIndex"` // 权限编码
+	Name   string `json:"name" gorm:"size:100;not null"`             // 权限名称
+	Module string `json:"module" gorm:"size:50;not null"`            // 所属模块 (用于分组展示)
+	Sort   int    `json:"sort" gorm:"default:0"`                     // 排序
+}
+
+// TableName 指定表名
+func (Permission) TableName() string {
+	return "permissions"
+}
+
+// RolePermission 角色与权限的关联
+type RolePermission struct {
+	ID           int64 `json:"id" gorm:"primaryKey;autoIncrement"`
+	RoleID       int64 `json:"role_id" gorm:"not null;uniqueIndex:idx_role_permission"`             // 角色ID
+	PermissionID int64 `json:"permission_id" gorm:"not null;uniqueIndex:idx_role_permission;index"` // 权限ID
+}
+
+// TableName 指定表名
+func (RolePermission) TableName() string {
+	return "role_permissions"
+}
+
+// Department 部门
+// 部门构成以 ParentID 关联的树 (0 为顶级部门)，用户通过 users.department_id 归属部门，
+// 角色的数据范围 (Role.DataScope) 按部门树确定可访问哪些用户的项目与收款。
+type Department struct {
+	ID         int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name       string    `json:"name" gorm:"size:50;not null"`              // 部门名称
+	ParentID   int64     `json:"parent_id" gorm:"not null;default:0;index"` // 上级部门ID，0 表示顶级部门
+	ManagerID  int64     `json:"manager_id" gorm:"not null;default:0"`      // 部门负责人用户ID，0 表示未指定
+	Sort       int       `json:"sort" gorm:"default:0"`                     // 排序
+	CreateTime time.Time `json:"create_time" gorm:"autoCreateTime"`         // 创建时间
+	UpdateTime time.Time `json:"update_time" gorm:"autoUpdateTime"`         // 更新时间
+}
+
+// TableName 指定表名
+func (Department) TableName() string {
+	return "departments"
+}
+
+// Position 职位
+// 用户通过 users.position_id 引用职位，职位名称唯一。
+type Position struct {
+	ID          int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name        string    `json:"name" gorm:"size:50;not null;uniqueIndex"` // 职位名称
+	Description string    `json:"description" gorm:"size:255"`              // 描述
+	Sort        int       `json:"sort" gorm:"default:0"`                    // 排序
+	CreateTime  time.Time `json:"create_time" gorm:"autoCreateTime"`        // 创建时间
+	UpdateTime  time.Time `json:"update_time" gorm:"autoUpdateTime"`        // 更新时间
+}
+
+// TableName 指定表名
+func (Position) TableName() string {
+	return "positions"
+}
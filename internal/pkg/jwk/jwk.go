@@ -0,0 +1,139 @@
+package jwk
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Key JSON Web Key (RFC 7517)，仅包含公钥参数
+type Key struct {
+	Kty string `json:"kty"`           // 密钥类型: RSA, EC, OKP
+	Kid string `json:"kid,omitempty"` // 密钥ID
+	Use string `json:"use,omitempty"` // 用途: sig
+	Alg string `json:"alg,omitempty"` // 签名算法
+	N   string `json:"n,omitempty"`   // RSA 模数
+	E   string `json:"e,omitempty"`   // RSA 指数
+	Crv string `json:"crv,omitempty"` // 曲线: P-256, P-384, P-521, Ed25519
+	X   string `json:"x,omitempty"`   // EC/OKP 公钥 X
+	Y   string `json:"y,omitempty"`   // EC 公钥 Y
+}
+
+// Set JSON Web Key Set
+type Set struct {
+	Keys []Key `json:"keys"`
+}
+
+// Find 按 kid 查找密钥，kid 为空且只有一个密钥时返回该密钥
+func (s Set) Find(kid string) (Key, bool) {
+	if kid == "" && len(s.Keys) == 1 {
+		return s.Keys[0], true
+	}
+	for _, k := range s.Keys {
+		if k.Kid == kid {
+			return k, true
+		}
+	}
+	return Key{}, false
+}
+
+// PublicKey 解析为 Go 公钥 (*rsa.PublicKey, *ecdsa.PublicKey 或 ed25519.PublicKey)
+func (k Key) PublicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decode(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decode(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if len(e) == 0 || len(e) > 4 {
+			return nil, errors.New("jwk: invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("jwk: unsupported curve %q", k.Crv)
+		}
+		x, err := decode(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decode(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		key := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(key.X, key.Y) {
+			return nil, errors.New("jwk: point is not on curve")
+		}
+		return key, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("jwk: unsupported curve %q", k.Crv)
+		}
+		x, err := decode(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("jwk: invalid Ed25519 key size")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("jwk: unsupported key type %q", k.Kty)
+	}
+}
+
+// FromPublicKey 将公钥转换为 JWK (用于发布 JWKS)
+func FromPublicKey(pub crypto.PublicKey, kid, alg string) (Key, error) {
+	key := Key{Kid: kid, Use: "sig", Alg: alg}
+	switch pk := pub.(type) {
+	case *rsa.PublicKey:
+		key.Kty = "RSA"
+		key.N = encode(pk.N.Bytes())
+		key.E = encode(big.NewInt(int64(pk.E)).Bytes())
+	case *ecdsa.PublicKey:
+		size := (pk.Curve.Params().BitSize + 7) / 8
+		key.Kty = "EC"
+		key.Crv = pk.Curve.Params().Name
+		key.X = encode(pk.X.FillBytes(make([]byte, size)))
+		key.Y = encode(pk.Y.FillBytes(make([]byte, size)))
+	case ed25519.PublicKey:
+		key.Kty = "OKP"
+		key.Crv = "Ed25519"
+		key.X = encode(pk)
+	default:
+		return Key{}, fmt.Errorf("jwk: unsupported public key type %T", pub)
+	}
+	return key, nil
+}
+
+// encode Base64url (无填充) 编码
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decode Base64url (无填充) 解码
+func decode(s string) ([]byte, error) {
+	if s == "" {
+		return nil, errors.New("jwk: missing key parameter")
+	}
+	return base64.RawURLEncoding.DecodeString(s)
+}
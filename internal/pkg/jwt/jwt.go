@@ -8,13 +8,11 @@ import (
 )
 
 // JWT 基础配置
+// 签名密钥见 keys.go (每个安装自动生成的非对称密钥集，按 kid 区分，支持轮换)。
 var (
-	// SecretKey JWT 签名密钥
-	// 注意: 生产环境应从配置文件或环境变量读取，而非硬编码。
-	SecretKey = []byte("orange-secret-key-xu")
-
-	// TokenExpiry Token 有效期时长
+	// TokenExpiry 访问令牌有效期时长
 	// 此变量通常由 main.go 在启动时根据配置注入初始化。
+	// 访问令牌有效期较短，过期后由客户端使用刷新令牌换取新的访问令牌。
 	TokenExpiry time.Duration
 )
 
@@ -24,6 +22,7 @@ type Claims struct {
 	UserID               int64  `json:"user_id"`  // 用户ID
 	Username             string `json:"username"` // 用户名
 	Role                 string `json:"role"`     // 用户角色
+	TokenVersion         int    `json:"tv"`       // 用户令牌版本，与数据库不一致时令牌失效 (用于吊销)
 	jwt.RegisteredClaims        // 内嵌标准声明 (如过期时间、签发人等)
 }
 
@@ -32,16 +31,20 @@ type Claims struct {
 //   - userID: 用户ID
 //   - username: 用户名
 //   - role: 用户角色
+//   - tokenVersion: 用户当前的令牌版本
+//   - tokenID: 令牌唯一标识 (jti)，用于关联登录会话
 //
 // 返回:
 //   - string: 签名后的 Token 字符串
 //   - error: 签名过程中可能出现的错误
-func GenerateToken(userID int64, username, role string) (string, error) {
+func GenerateToken(userID int64, username, role string, tokenVersion int, tokenID string) (string, error) {
 	claims := Claims{
-		UserID:   userID,
-		Username: username,
-		Role:     role,
+		UserID:       userID,
+		Username:     username,
+		Role:         role,
+		TokenVersion: tokenVersion,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,                                         // 令牌唯一标识 (jti)
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(TokenExpiry)), // 过期时间
 			IssuedAt:  jwt.NewNumericDate(time.Now()),                  // 签发时间
 			NotBefore: jwt.NewNumericDate(time.Now()),                  // 生效时间
@@ -49,22 +52,33 @@ func GenerateToken(userID int64, username, role string) (string, error) {
 		},
 	}
 
-	// 使用 HS256 签名算法创建 Token 对象
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	// 使用密钥进行签名并生成字符串
-	return token.SignedString(SecretKey)
+	// 使用当前签名密钥创建 Token 对象，header 中的 kid 标识签名密钥
+	key, err := currentKey()
+	if err != nil {
+		return "", err
+	}
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.kid
+	// 使用私钥进行签名并生成字符串
+	return token.SignedString(key.private)
 }
 
 // ParseToken 解析并验证 JWT Token
 // 验证主要包括: 签名有效性、是否过期、格式是否正确。
 func ParseToken(tokenString string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		// 安全检查: 验证签名算法是否为预期的 HMAC (HS256)
-		// 防止算法降级攻击 (如攻击者将 header 中的 alg 改为 None)
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		// 按 kid 查找校验密钥 (轮换后旧密钥在保留期内仍可校验)
+		kid, _ := token.Header["kid"].(string)
+		key, err := verificationKey(kid)
+		if err != nil {
+			return nil, err
+		}
+		// 安全检查: 签名算法必须与密钥一致
+		// 防止算法混淆攻击 (如攻击者将 header 中的 alg 改为 none 或 HS256)
+		if token.Method.Alg() != key.alg {
 			return nil, errors.New("invalid signing method")
 		}
-		return SecretKey, nil
+		return key.private.Public(), nil
 	})
 
 	if err != nil {
@@ -0,0 +1,374 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/pkg/jwk"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// 支持的签名算法
+const (
+	AlgRS256 = "RS256" // RSA 2048 + SHA-256
+	AlgEdDSA = "EdDSA" // Ed25519
+)
+
+// 签名密钥配置
+var (
+	// KeyDir 签名密钥目录
+	// 此变量通常由 main.go 在启动时根据配置注入 (应用数据目录下的 jwt-keys)。
+	// 每个密钥保存为 <kid>.pem (PKCS#8，权限 0600)，目录中没有当前算法的密钥时首次使用自动生成。
+	KeyDir = "jwt-keys"
+
+	// Algorithm 签名算法 (RS256 或 EdDSA)，更换算法后自动生成新算法的密钥并用于签名
+	Algorithm = AlgRS256
+
+	// RotationInterval 签名密钥自动轮换周期，0 表示不自动轮换
+	RotationInterval time.Duration
+)
+
+// pemCreatedHeader PEM 头中记录密钥创建时间的字段
+const pemCreatedHeader = "Created"
+
+// ErrUnknownKey 令牌的 kid 不在当前密钥集中 (密钥已被清理或令牌来自其他安装)
+var ErrUnknownKey = errors.New("unknown signing key")
+
+// KeyInfo 签名密钥信息 (不含私钥)
+type KeyInfo struct {
+	Kid        string    `json:"kid"`         // 密钥ID
+	Algorithm  string    `json:"algorithm"`   // 签名算法
+	CreateTime time.Time `json:"create_time"` // 创建时间
+	Active     bool      `json:"active"`      // 是否为当前签名密钥 (其余仅用于校验轮换前签发的令牌)
+}
+
+// signingKey 签名密钥
+type signingKey struct {
+	kid     string
+	alg     string
+	method  jwt.SigningMethod
+	private crypto.Signer
+	created time.Time
+}
+
+// keyStore 签名密钥集
+// 按创建时间升序保存全部密钥；最新的当前算法密钥用于签名，其余密钥在轮换后保留一段时间 (访问令牌有效期) 用于校验，
+// 使轮换密钥时已签发的令牌不会立即失效。
+type keyStore struct {
+	mu     sync.RWMutex
+	loaded bool
+	keys   []*signingKey
+}
+
+var store keyStore
+
+// LoadKeys 加载签名密钥 (应用启动时调用，也会在首次签发或校验令牌时自动调用)
+// 没有当前算法的密钥或当前密钥已超过轮换周期时生成新密钥，并清理已过保留期的旧密钥。
+func LoadKeys() error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return store.load()
+}
+
+// RotateKey 立即生成新的签名密钥，旧密钥在访问令牌有效期内继续用于校验
+func RotateKey() (KeyInfo, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if err := store.ensureLoaded(); err != nil {
+		return KeyInfo{}, err
+	}
+	key, err := store.generate()
+	if err != nil {
+		return KeyInfo{}, err
+	}
+	store.prune()
+	return key.info(true), nil
+}
+
+// ListKeys 列出当前密钥集
+func ListKeys() ([]KeyInfo, error) {
+	if err := ensureLoaded(); err != nil {
+		return nil, err
+	}
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	active := store.active()
+	list := make([]KeyInfo, 0, len(store.keys))
+	for _, k := range store.keys {
+		list = append(list, k.info(k == active))
+	}
+	return list, nil
+}
+
+// JWKS 当前密钥集的公钥 (JSON Web Key Set)，供其他服务校验 Orange 签发的令牌
+func JWKS() (jwk.Set, error) {
+	if err := ensureLoaded(); err != nil {
+		return jwk.Set{}, err
+	}
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	set := jwk.Set{Keys: make([]jwk.Key, 0, len(store.keys))}
+	for _, k := range store.keys {
+		key, err := jwk.FromPublicKey(k.private.Public(), k.kid, k.alg)
+		if err != nil {
+			return jwk.Set{}, err
+		}
+		set.Keys = append(set.Keys, key)
+	}
+	return set, nil
+}
+
+// currentKey 当前签名密钥 (超过轮换周期时先轮换)
+func currentKey() (*signingKey, error) {
+	if err := ensureLoaded(); err != nil {
+		return nil, err
+	}
+	store.mu.RLock()
+	key := store.active()
+	store.mu.RUnlock()
+	if key != nil && !store.due(key) {
+		return key, nil
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if err := store.load(); err != nil {
+		return nil, err
+	}
+	return store.active(), nil
+}
+
+// verificationKey 按 kid 查找校验密钥
+func verificationKey(kid string) (*signingKey, error) {
+	if err := ensureLoaded(); err != nil {
+		return nil, err
+	}
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	for _, k := range store.keys {
+		if k.kid == kid {
+			return k, nil
+		}
+	}
+	return nil, ErrUnknownKey
+}
+
+// ensureLoaded 首次使用时加载密钥
+func ensureLoaded() error {
+	store.mu.RLock()
+	loaded := store.loaded
+	store.mu.RUnlock()
+	if loaded {
+		return nil
+	}
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return store.ensureLoaded()
+}
+
+// ensureLoaded 未加载时加载 (调用方需持有写锁)
+func (s *keyStore) ensureLoaded() error {
+	if s.loaded {
+		return nil
+	}
+	return s.load()
+}
+
+// load 读取密钥目录，按需生成新密钥并清理旧密钥 (调用方需持有写锁)
+func (s *keyStore) load() error {
+	if _, err := methodFor(Algorithm); err != nil {
+		return err
+	}
+	files, err := filepath.Glob(filepath.Join(KeyDir, "*.pem"))
+	if err != nil {
+		return err
+	}
+	keys := make([]*signingKey, 0, len(files))
+	for _, file := range files {
+		key, err := readKey(file)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].created.Equal(keys[j].created) {
+			return keys[i].kid < keys[j].kid
+		}
+		return keys[i].created.Before(keys[j].created)
+	})
+	s.keys = keys
+	s.loaded = true
+
+	if active := s.active(); active == nil || s.due(active) {
+		if _, err := s.generate(); err != nil {
+			return err
+		}
+	}
+	s.prune()
+	return nil
+}
+
+// active 当前签名密钥: 最新的当前算法密钥
+func (s *keyStore) active() *signingKey {
+	for i := len(s.keys) - 1; i >= 0; i-- {
+		if s.keys[i].alg == Algorithm {
+			return s.keys[i]
+		}
+	}
+	return nil
+}
+
+// due 密钥是否已超过轮换周期
+func (s *keyStore) due(key *signingKey) bool {
+	return RotationInterval > 0 && time.Since(key.created) >= RotationInterval
+}
+
+// generate 生成当前算法的新密钥并写入密钥目录 (调用方需持有写锁)
+func (s *keyStore) generate() (*signingKey, error) {
+	method, err := methodFor(Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	var private crypto.Signer
+	switch Algorithm {
+	case AlgRS256:
+		private, err = rsa.GenerateKey(rand.Reader, 2048)
+	case AlgEdDSA:
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("生成签名密钥失败: %w", err)
+	}
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	key := &signingKey{
+		kid:     now.UTC().Format("20060102150405") + "-" + hex.EncodeToString(suffix),
+		alg:     Algorithm,
+		method:  method,
+		private: private,
+		created: now,
+	}
+	if err := writeKey(key); err != nil {
+		return nil, err
+	}
+	s.keys = append(s.keys, key)
+	slog.Info("JWT signing key generated", "kid", key.kid, "algorithm", key.alg)
+	return key, nil
+}
+
+// prune 删除已过保留期的旧密钥 (调用方需持有写锁)
+// 密钥被新密钥取代后，用它签发的访问令牌最长还能使用 TokenExpiry，之后即可删除。
+func (s *keyStore) prune() {
+	active := s.active()
+	retention := TokenExpiry + time.Minute
+	kept := s.keys[:0]
+	for i, k := range s.keys {
+		if k != active && i+1 < len(s.keys) && time.Since(s.keys[i+1].created) > retention {
+			if err := os.Remove(keyPath(k.kid)); err != nil && !errors.Is(err, os.ErrNotExist) {
+				slog.Warn("Failed to remove retired JWT signing key", "kid", k.kid, "error", err)
+				kept = append(kept, k)
+				continue
+			}
+			slog.Info("Retired JWT signing key removed", "kid", k.kid)
+			continue
+		}
+		kept = append(kept, k)
+	}
+	s.keys = kept
+}
+
+// info 转换为对外展示的密钥信息
+func (k *signingKey) info(active bool) KeyInfo {
+	return KeyInfo{Kid: k.kid, Algorithm: k.alg, CreateTime: k.created, Active: active}
+}
+
+// methodFor 签名算法对应的 SigningMethod
+func methodFor(alg string) (jwt.SigningMethod, error) {
+	switch alg {
+	case AlgRS256:
+		return jwt.SigningMethodRS256, nil
+	case AlgEdDSA:
+		return jwt.SigningMethodEdDSA, nil
+	default:
+		return nil, fmt.Errorf("不支持的 JWT 签名算法: %s (可选 RS256、EdDSA)", alg)
+	}
+}
+
+// keyPath 密钥文件路径
+func keyPath(kid string) string {
+	return filepath.Join(KeyDir, kid+".pem")
+}
+
+// readKey 读取 PEM 格式的私钥文件
+func readKey(path string) (*signingKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取签名密钥失败: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("签名密钥格式错误: %s", path)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("签名密钥格式错误: %s: %w", path, err)
+	}
+
+	key := &signingKey{kid: strings.TrimSuffix(filepath.Base(path), ".pem")}
+	switch pk := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.alg, key.private = AlgRS256, pk
+	case ed25519.PrivateKey:
+		key.alg, key.private = AlgEdDSA, pk
+	default:
+		return nil, fmt.Errorf("不支持的签名密钥类型: %s", path)
+	}
+	key.method, _ = methodFor(key.alg)
+
+	if created, err := time.Parse(time.RFC3339Nano, block.Headers[pemCreatedHeader]); err == nil {
+		key.created = created
+	} else if stat, err := os.Stat(path); err == nil {
+		key.created = stat.ModTime()
+	}
+	return key, nil
+}
+
+// writeKey 以 PKCS#8 PEM 格式写入私钥 (权限 0600)
+func writeKey(key *signingKey) error {
+	der, err := x509.MarshalPKCS8PrivateKey(key.private)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(KeyDir, 0700); err != nil {
+		return fmt.Errorf("创建签名密钥目录失败: %w", err)
+	}
+	f, err := os.OpenFile(keyPath(key.kid), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return fmt.Errorf("写入签名密钥失败: %w", err)
+	}
+	defer f.Close()
+	return pem.Encode(f, &pem.Block{
+		Type:    "PRIVATE KEY",
+		Headers: map[string]string{pemCreatedHeader: key.created.UTC().Format(time.RFC3339Nano)},
+		Bytes:   der,
+	})
+}
@@ -0,0 +1,46 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+// unsafeFileChars 文件名中需替换的字符 (收件人地址可能包含路径分隔符等)
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9@._+-]`)
+
+// FileMailer 将邮件写入本地目录 (不实际发送)
+// 用于未配置邮件服务器的离线桌面环境与开发调试，每封邮件保存为一个 .eml 文件并记录日志。
+type FileMailer struct {
+	dir  string
+	from string
+}
+
+// NewFileMailer 创建写入本地文件的邮件发送器
+func NewFileMailer(dir, from string) *FileMailer {
+	return &FileMailer{dir: dir, from: from}
+}
+
+// Send 将邮件写入 <dir>/<时间>-<收件人>.eml
+func (m *FileMailer) Send(_ context.Context, msg Message) error {
+	now := time.Now()
+	_, to, data, err := compose(m.from, msg, now)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(m.dir, 0700); err != nil {
+		return fmt.Errorf("创建邮件目录失败: %w", err)
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", now.Format("20060102-150405.000000"), unsafeFileChars.ReplaceAllString(to, "_"))
+	path := filepath.Join(m.dir, name)
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("保存邮件失败: %w", err)
+	}
+	slog.Info("Mail saved to file", "to", to, "subject", msg.Subject, "path", path)
+	return nil
+}
@@ -0,0 +1,80 @@
+// Package mailer 邮件发送
+// 提供 SMTP 发送与写入本地文件两种实现：后者用于未配置邮件服务器的离线桌面环境，
+// 邮件以 .eml 文件保存在数据目录下，可直接用邮件客户端打开。
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// Message 待发送的邮件 (纯文本)
+type Message struct {
+	To      string // 收件人地址
+	Subject string // 主题
+	Body    string // 正文 (纯文本)
+}
+
+// Mailer 邮件发送接口
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// ErrInvalidAddress 发件人或收件人地址格式错误
+var ErrInvalidAddress = errors.New("邮件地址格式错误")
+
+// compose 按 RFC 5322 组装邮件，返回收件人地址与完整邮件内容
+// 主题使用 MIME encoded-word 编码，正文使用 UTF-8 + base64，避免中文乱码与头部注入。
+func compose(from string, msg Message, now time.Time) (string, string, []byte, error) {
+	sender, err := mail.ParseAddress(from)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("%w: %s", ErrInvalidAddress, from)
+	}
+	recipient, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("%w: %s", ErrInvalidAddress, msg.To)
+	}
+
+	var buf bytes.Buffer
+	header := func(key, value string) {
+		buf.WriteString(key + ": " + value + "\r\n")
+	}
+	header("From", sender.String())
+	header("To", recipient.String())
+	header("Subject", mime.BEncoding.Encode("UTF-8", msg.Subject))
+	header("Date", now.Format(time.RFC1123Z))
+	header("Message-ID", messageID(sender.Address))
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=UTF-8")
+	header("Content-Transfer-Encoding", "base64")
+	buf.WriteString("\r\n")
+
+	body := base64.StdEncoding.EncodeToString([]byte(strings.ReplaceAll(msg.Body, "\n", "\r\n")))
+	for len(body) > 76 {
+		buf.WriteString(body[:76] + "\r\n")
+		body = body[76:]
+	}
+	buf.WriteString(body + "\r\n")
+
+	return sender.Address, recipient.Address, buf.Bytes(), nil
+}
+
+// messageID 生成 Message-ID (随机值@发件人域名)
+func messageID(from string) string {
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	domain := "localhost"
+	if i := strings.LastIndex(from, "@"); i >= 0 {
+		domain = from[i+1:]
+	}
+	return "<" + hex.EncodeToString(b) + "@" + domain + ">"
+}
@@ -0,0 +1,119 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// SMTP 连接加密方式
+const (
+	SecurityStartTLS = "starttls" // 明文连接后升级 (通常为 587 端口)
+	SecurityTLS      = "tls"      // 直接建立 TLS 连接 (通常为 465 端口)
+	SecurityNone     = "none"     // 不加密 (仅限本机或内网中继)
+)
+
+// SMTPConfig SMTP 服务器配置
+type SMTPConfig struct {
+	Host     string        // 服务器地址
+	Port     int           // 端口
+	Username string        // 用户名 (为空表示无需认证)
+	Password string        // 密码
+	From     string        // 发件人，如 "Orange <noreply@example.com>"
+	Security string        // 加密方式: starttls, tls, none
+	Timeout  time.Duration // 连接与发送超时
+}
+
+// SMTPMailer 通过 SMTP 服务器发送邮件
+type SMTPMailer struct {
+	cfg SMTPConfig
+}
+
+// NewSMTPMailer 创建 SMTP 邮件发送器
+func NewSMTPMailer(cfg SMTPConfig) *SMTPMailer {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	if cfg.Security == "" {
+		cfg.Security = SecurityStartTLS
+	}
+	return &SMTPMailer{cfg: cfg}
+}
+
+// Send 发送邮件
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	from, to, data, err := compose(m.cfg.From, msg, time.Now())
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, m.cfg.Timeout)
+	defer cancel()
+
+	client, err := m.dial(ctx)
+	if err != nil {
+		return fmt.Errorf("连接邮件服务器失败: %w", err)
+	}
+	defer client.Close()
+
+	if m.cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)); err != nil {
+			return fmt.Errorf("邮件服务器认证失败: %w", err)
+		}
+	}
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// dial 按加密方式建立 SMTP 连接
+func (m *SMTPMailer) dial(ctx context.Context) (*smtp.Client, error) {
+	addr := net.JoinHostPort(m.cfg.Host, strconv.Itoa(m.cfg.Port))
+	tlsConfig := &tls.Config{ServerName: m.cfg.Host}
+
+	var conn net.Conn
+	var err error
+	dialer := &net.Dialer{}
+	if m.cfg.Security == SecurityTLS {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, m.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if m.cfg.Security == SecurityStartTLS {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			client.Close()
+			return nil, err
+		}
+	}
+	return client, nil
+}
@@ -0,0 +1,334 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/pkg/jwk"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// clockSkew 校验 ID Token 时间声明时允许的时钟偏差
+const clockSkew = time.Minute
+
+// jwksRefreshInterval 遇到未知 kid 时重新获取 JWKS 的最短间隔
+// 防止携带伪造 kid 的令牌反复触发对身份提供方的请求。
+var jwksRefreshInterval = time.Minute
+
+// ErrInvalidIDToken ID Token 无效 (签名、签发方、受众、有效期或 nonce 校验失败)
+var ErrInvalidIDToken = errors.New("oidc: invalid id token")
+
+// Config OIDC 客户端配置
+type Config struct {
+	Issuer       string   // 签发方 (Issuer) URL，用于服务发现
+	ClientID     string   // 客户端ID
+	ClientSecret string   // 客户端密钥 (公共客户端为空，仅依赖 PKCE)
+	RedirectURL  string   // 回调地址
+	Scopes       []string // 申请的 scope (须包含 openid)
+}
+
+// Discovery OpenID Provider 元数据 (/.well-known/openid-configuration)
+type Discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Claims ID Token 中使用的声明
+type Claims struct {
+	Nonce         string `json:"nonce"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Name          string `json:"name"`
+	jwt.RegisteredClaims
+	Raw map[string]interface{} `json:"-"` // 全部声明 (用于读取可配置的用户名、组声明)
+}
+
+// String 读取字符串类型的声明
+func (c *Claims) String(name string) string {
+	if v, ok := c.Raw[name].(string); ok {
+		return v
+	}
+	return ""
+}
+
+// Strings 读取字符串数组类型的声明 (单个字符串视为只有一个元素)
+func (c *Claims) Strings(name string) []string {
+	switch v := c.Raw[name].(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// Provider OIDC 身份提供方客户端
+// 首次使用时读取服务发现元数据，公钥集 (JWKS) 缓存在内存中，遇到未知的 kid 时重新获取 (应对密钥轮换)；
+// 重新获取至多每 jwksRefreshInterval 一次，并发的请求共用同一次获取。
+type Provider struct {
+	cfg    Config
+	client *http.Client
+
+	mu        sync.Mutex
+	discovery *Discovery
+	keys      jwk.Set
+	keysTime  time.Time // 最近一次成功获取 JWKS 的时间
+
+	fetchMu sync.Mutex // 串行化 JWKS 获取
+}
+
+// NewProvider 创建 OIDC 客户端，client 为 nil 时使用 10 秒超时的默认客户端
+func NewProvider(cfg Config, client *http.Client) *Provider {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &Provider{cfg: cfg, client: client}
+}
+
+// Issuer 签发方 URL
+func (p *Provider) Issuer() string {
+	return p.cfg.Issuer
+}
+
+// AuthCodeURL 生成授权请求地址 (授权码模式 + PKCE S256)
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	d, err := p.Discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	u, err := url.Parse(d.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("oidc: invalid authorization endpoint: %w", err)
+	}
+	q := u.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", p.cfg.ClientID)
+	q.Set("redirect_uri", p.cfg.RedirectURL)
+	q.Set("scope", strings.Join(p.cfg.Scopes, " "))
+	q.Set("state", state)
+	q.Set("nonce", nonce)
+	q.Set("code_challenge", CodeChallenge(verifier))
+	q.Set("code_challenge_method", "S256")
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// Exchange 用授权码换取令牌，返回 ID Token 原文
+func (p *Provider) Exchange(ctx context.Context, code, verifier string) (string, error) {
+	d, err := p.Discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.cfg.RedirectURL},
+		"client_id":     {p.cfg.ClientID},
+		"code_verifier": {verifier},
+	}
+	if p.cfg.ClientSecret != "" {
+		form.Set("client_secret", p.cfg.ClientSecret)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	var token struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	status, err := p.do(req, &token)
+	if err != nil {
+		return "", err
+	}
+	if token.Error != "" {
+		return "", fmt.Errorf("oidc: token endpoint error: %s %s", token.Error, token.ErrorDescription)
+	}
+	if status != http.StatusOK {
+		return "", fmt.Errorf("oidc: token endpoint returned %d", status)
+	}
+	if token.IDToken == "" {
+		return "", errors.New("oidc: token response has no id_token")
+	}
+	return token.IDToken, nil
+}
+
+// VerifyIDToken 校验 ID Token 的签名、签发方、受众、有效期与 nonce
+func (p *Provider) VerifyIDToken(ctx context.Context, raw, nonce string) (*Claims, error) {
+	d, err := p.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(raw, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.publicKey(ctx, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}),
+		jwt.WithIssuer(d.Issuer),
+		jwt.WithAudience(p.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(clockSkew),
+	)
+	if err != nil || !token.Valid {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: missing sub", ErrInvalidIDToken)
+	}
+	if claims.Nonce != nonce {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	}
+
+	// 再解析一次载荷，保留全部声明 (签名已校验)
+	parts := strings.Split(raw, ".")
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+	if err := json.Unmarshal(payload, &claims.Raw); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+	return claims, nil
+}
+
+// Discover 读取服务发现元数据 (成功后缓存)
+func (p *Provider) Discover(ctx context.Context) (*Discovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	wellKnown := strings.TrimSuffix(p.cfg.Issuer, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, wellKnown, nil)
+	if err != nil {
+		return nil, err
+	}
+	var d Discovery
+	status, err := p.do(req, &d)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("oidc: discovery returned %d", status)
+	}
+	// 元数据中的 issuer 必须与配置一致 (OpenID Connect Discovery 4.3)
+	if strings.TrimSuffix(d.Issuer, "/") != strings.TrimSuffix(p.cfg.Issuer, "/") {
+		return nil, fmt.Errorf("oidc: issuer mismatch: %s", d.Issuer)
+	}
+	if d.AuthorizationEndpoint == "" || d.TokenEndpoint == "" || d.JWKSURI == "" {
+		return nil, errors.New("oidc: incomplete discovery document")
+	}
+	p.discovery = &d
+	return p.discovery, nil
+}
+
+// publicKey 按 kid 查找签名公钥，缓存中不存在时重新获取 JWKS
+// 距上次获取不足 jwksRefreshInterval 时不再请求，直接视为未知的 kid。
+func (p *Provider) publicKey(ctx context.Context, kid string) (interface{}, error) {
+	if key, ok := p.cachedKey(kid); ok {
+		return key.PublicKey()
+	}
+
+	// 同一时间只有一个请求获取 JWKS，等待者拿到锁后先查看已刷新的缓存
+	p.fetchMu.Lock()
+	defer p.fetchMu.Unlock()
+	if key, ok := p.cachedKey(kid); ok {
+		return key.PublicKey()
+	}
+	p.mu.Lock()
+	jwksURI := p.discovery.JWKSURI
+	recent := !p.keysTime.IsZero() && time.Since(p.keysTime) < jwksRefreshInterval
+	p.mu.Unlock()
+	if recent {
+		return nil, fmt.Errorf("oidc: unknown key id %q", kid)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, jwksURI, nil)
+	if err != nil {
+		return nil, err
+	}
+	var set jwk.Set
+	status, err := p.do(req, &set)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("oidc: jwks returned %d", status)
+	}
+	p.mu.Lock()
+	p.keys = set
+	p.keysTime = time.Now()
+	p.mu.Unlock()
+
+	key, ok := set.Find(kid)
+	if !ok {
+		return nil, fmt.Errorf("oidc: unknown key id %q", kid)
+	}
+	return key.PublicKey()
+}
+
+// cachedKey 在已缓存的 JWKS 中按 kid 查找密钥
+func (p *Provider) cachedKey(kid string) (jwk.Key, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.keys.Find(kid)
+}
+
+// do 发送请求并解析 JSON 响应 (限制 1MB)
+func (p *Provider) do(req *http.Request, v interface{}) (int, error) {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return resp.StatusCode, err
+	}
+	if err := json.Unmarshal(body, v); err != nil && resp.StatusCode == http.StatusOK {
+		return resp.StatusCode, fmt.Errorf("oidc: invalid response from %s: %w", req.URL.Host, err)
+	}
+	return resp.StatusCode, nil
+}
+
+// RandomString 生成 URL 安全的随机字符串 (用于 state、nonce 与 PKCE code_verifier)
+func RandomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// CodeChallenge 计算 PKCE S256 code_challenge
+func CodeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
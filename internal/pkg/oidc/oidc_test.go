@@ -0,0 +1,98 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/pkg/jwk"
+)
+
+// newJWKSProvider 返回使用本地 JWKS 端点的客户端，以及该端点收到的请求数
+func newJWKSProvider(t *testing.T, kid string) (*Provider, *atomic.Int32) {
+	t.Helper()
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := jwk.FromPublicKey(pub, kid, "EdDSA")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(jwk.Set{Keys: []jwk.Key{key}})
+	}))
+	t.Cleanup(server.Close)
+
+	p := NewProvider(Config{Issuer: server.URL}, server.Client())
+	p.discovery = &Discovery{Issuer: server.URL, JWKSURI: server.URL}
+	return p, &hits
+}
+
+func TestPublicKeyUnknownKidRefetchIsRateLimited(t *testing.T) {
+	p, hits := newJWKSProvider(t, "idp-key-1")
+	ctx := context.Background()
+
+	if _, err := p.publicKey(ctx, "idp-key-1"); err != nil {
+		t.Fatalf("已知 kid: %v", err)
+	}
+
+	// 间隔内携带未知 kid 的请求 (含并发请求) 不再访问 JWKS 端点
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := p.publicKey(ctx, "forged-kid"); err == nil {
+				t.Error("未知 kid 应返回错误")
+			}
+		}()
+	}
+	wg.Wait()
+	if n := hits.Load(); n != 1 {
+		t.Fatalf("JWKS 请求 %d 次，期望 1", n)
+	}
+
+	// 超过间隔后可重新获取 (应对密钥轮换)
+	p.mu.Lock()
+	p.keysTime = time.Now().Add(-jwksRefreshInterval)
+	p.mu.Unlock()
+	if _, err := p.publicKey(ctx, "forged-kid"); err == nil {
+		t.Error("未知 kid 应返回错误")
+	}
+	if n := hits.Load(); n != 2 {
+		t.Errorf("JWKS 请求 %d 次，期望 2", n)
+	}
+}
+
+func TestPublicKeyConcurrentFetchIsShared(t *testing.T) {
+	p, hits := newJWKSProvider(t, "idp-key-2")
+	ctx := context.Background()
+
+	// 缓存为空时并发校验只获取一次 JWKS
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := p.publicKey(ctx, "idp-key-2"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := hits.Load(); n != 1 {
+		t.Errorf("JWKS 请求 %d 次，期望 1", n)
+	}
+}
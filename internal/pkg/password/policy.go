@@ -0,0 +1,169 @@
+package password
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// MaxHistoryCount 密码历史的最大保留条数
+const MaxHistoryCount = 24
+
+// 密码规则标识 (Violation.Rule)
+const (
+	RuleMinLength = "min_length" // 长度不足
+	RuleUpper     = "upper"      // 缺少大写字母
+	RuleLower     = "lower"      // 缺少小写字母
+	RuleDigit     = "digit"      // 缺少数字
+	RuleSymbol    = "symbol"     // 缺少特殊字符
+	RuleCommon    = "common"     // 属于常见弱密码
+	RuleReused    = "reused"     // 与最近使用过的密码相同
+)
+
+//go:embed common_passwords.txt
+var commonPasswordList string
+
+// commonPasswords 常见弱密码 (小写)
+var commonPasswords = func() map[string]bool {
+	set := make(map[string]bool)
+	scanner := bufio.NewScanner(strings.NewReader(commonPasswordList))
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" && !strings.HasPrefix(line, "#") {
+			set[strings.ToLower(line)] = true
+		}
+	}
+	return set
+}()
+
+// Policy 密码策略
+type Policy struct {
+	MinLength     int  `json:"min_length"`     // 最小长度 (按字符计)
+	RequireUpper  bool `json:"require_upper"`  // 必须包含大写字母
+	RequireLower  bool `json:"require_lower"`  // 必须包含小写字母
+	RequireDigit  bool `json:"require_digit"`  // 必须包含数字
+	RequireSymbol bool `json:"require_symbol"` // 必须包含特殊字符
+	BanCommon     bool `json:"ban_common"`     // 禁止使用常见弱密码
+	HistoryCount  int  `json:"history_count"`  // 不能与最近 N 个密码相同 (0 表示不限制)
+	MaxAgeDays    int  `json:"max_age_days"`   // 密码最长有效天数，到期后下次登录须修改 (0 表示不过期)
+}
+
+// DefaultPolicy 默认密码策略
+func DefaultPolicy() Policy {
+	return Policy{
+		MinLength: 8,
+		BanCommon: true,
+	}
+}
+
+// Violation 未满足的密码规则
+type Violation struct {
+	Rule    string `json:"rule"`    // 规则标识
+	Message string `json:"message"` // 提示信息
+}
+
+// PolicyError 密码不符合策略，列出全部未满足的规则
+type PolicyError struct {
+	Violations []Violation
+}
+
+func (e *PolicyError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		messages[i] = v.Message
+	}
+	return "密码不符合要求: " + strings.Join(messages, "；")
+}
+
+// Normalize 修正越界的配置值
+func (p Policy) Normalize() Policy {
+	if p.MinLength < 1 {
+		p.MinLength = 1
+	}
+	if p.HistoryCount < 0 {
+		p.HistoryCount = 0
+	}
+	if p.HistoryCount > MaxHistoryCount {
+		p.HistoryCount = MaxHistoryCount
+	}
+	if p.MaxAgeDays < 0 {
+		p.MaxAgeDays = 0
+	}
+	return p
+}
+
+// Validate 校验密码是否符合策略
+// history 为该用户最近使用过的密码哈希 (由新到旧)，只比对前 HistoryCount 个。
+// 不符合时返回 *PolicyError。
+func (p Policy) Validate(password string, history []string) error {
+	var violations []Violation
+	add := func(rule, message string) {
+		violations = append(violations, Violation{Rule: rule, Message: message})
+	}
+
+	if n := len([]rune(password)); n < p.MinLength {
+		add(RuleMinLength, fmt.Sprintf("长度至少 %d 位", p.MinLength))
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || r == ' ':
+			hasSymbol = true
+		}
+	}
+	if p.RequireUpper && !hasUpper {
+		add(RuleUpper, "需包含大写字母")
+	}
+	if p.RequireLower && !hasLower {
+		add(RuleLower, "需包含小写字母")
+	}
+	if p.RequireDigit && !hasDigit {
+		add(RuleDigit, "需包含数字")
+	}
+	if p.RequireSymbol && !hasSymbol {
+		add(RuleSymbol, "需包含特殊字符")
+	}
+
+	if p.BanCommon && IsCommon(password) {
+		add(RuleCommon, "不能使用常见弱密码")
+	}
+
+	if p.HistoryCount > 0 {
+		for i, hash := range history {
+			if i >= p.HistoryCount {
+				break
+			}
+			if CheckPassword(password, hash) {
+				add(RuleReused, fmt.Sprintf("不能与最近 %d 次使用过的密码相同", p.HistoryCount))
+				break
+			}
+		}
+	}
+
+	if len(violations) > 0 {
+		return &PolicyError{Violations: violations}
+	}
+	return nil
+}
+
+// Expired 密码是否已超过最长有效期
+func (p Policy) Expired(changedAt time.Time) bool {
+	if p.MaxAgeDays <= 0 || changedAt.IsZero() {
+		return false
+	}
+	return time.Since(changedAt) > time.Duration(p.MaxAgeDays)*24*time.Hour
+}
+
+// IsCommon 是否为常见弱密码 (不区分大小写)
+func IsCommon(password string) bool {
+	return commonPasswords[strings.ToLower(password)]
+}
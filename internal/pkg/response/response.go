@@ -98,6 +98,15 @@ func Error(c *gin.Context, code int, message ...string) {
 	})
 }
 
+// ErrorWithData 发送带业务数据的错误响应 (如校验失败的明细)
+func ErrorWithData(c *gin.Context, code int, message string, data interface{}) {
+	c.JSON(http.StatusOK, Response{
+		Code:    code,
+		Message: message,
+		Data:    data,
+	})
+}
+
 // ParamError 参数错误
 func ParamError(c *gin.Context, message ...string) {
 	Error(c, CodeParamError, message...)
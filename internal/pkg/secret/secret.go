@@ -0,0 +1,144 @@
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// 密钥配置
+var (
+	// KeyFile 本机密钥文件路径
+	// 此变量通常由 main.go 在启动时根据配置注入 (应用数据目录下的 secret.key)。
+	// 文件不存在时首次使用自动生成 32 字节随机密钥，权限 0600。
+	KeyFile = "secret.key"
+)
+
+// version 密文格式版本前缀，便于日后更换算法
+const version = "v1:"
+
+var (
+	keyOnce sync.Once
+	key     []byte
+	keyErr  error
+)
+
+// ErrInvalidCiphertext 密文格式错误或无法解密 (如本机密钥已变更)
+var ErrInvalidCiphertext = errors.New("密文无效或本机密钥不匹配")
+
+// Encrypt 使用本机密钥加密明文 (AES-256-GCM)
+// 返回 "v1:" + base64(nonce || ciphertext)。空字符串原样返回。
+func Encrypt(plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+	aead, err := newAEAD()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return version + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt 解密 Encrypt 生成的密文。空字符串原样返回。
+func Decrypt(ciphertext string) (string, error) {
+	if ciphertext == "" {
+		return "", nil
+	}
+	if !strings.HasPrefix(ciphertext, version) {
+		return "", ErrInvalidCiphertext
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(ciphertext, version))
+	if err != nil {
+		return "", ErrInvalidCiphertext
+	}
+
+	aead, err := newAEAD()
+	if err != nil {
+		return "", err
+	}
+	if len(data) < aead.NonceSize() {
+		return "", ErrInvalidCiphertext
+	}
+	nonce, sealed := data[:aead.NonceSize()], data[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return "", ErrInvalidCiphertext
+	}
+	return string(plaintext), nil
+}
+
+// newAEAD 基于派生密钥创建 AES-GCM 实例
+func newAEAD() (cipher.AEAD, error) {
+	k, err := encryptionKey()
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(k)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encryptionKey 读取本机密钥并通过 HKDF 派生加密密钥 (只计算一次)
+func encryptionKey() ([]byte, error) {
+	keyOnce.Do(func() {
+		var machine []byte
+		machine, keyErr = loadMachineSecret(KeyFile)
+		if keyErr != nil {
+			return
+		}
+		key, keyErr = hkdf.Key(sha256.New, machine, nil, "orange/secret/v1", 32)
+	})
+	return key, keyErr
+}
+
+// loadMachineSecret 读取本机密钥文件，不存在时生成
+func loadMachineSecret(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err == nil {
+		if len(data) < 32 {
+			return nil, fmt.Errorf("本机密钥文件已损坏: %s", path)
+		}
+		return data, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("读取本机密钥失败: %w", err)
+	}
+
+	data = make([]byte, 32)
+	if _, err := rand.Read(data); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("创建本机密钥目录失败: %w", err)
+	}
+	// O_EXCL: 并发首次生成时以先写入者为准
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if errors.Is(err, os.ErrExist) {
+		return loadMachineSecret(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("写入本机密钥失败: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(data); err != nil {
+		return nil, fmt.Errorf("写入本机密钥失败: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/models"
+	"gorm.io/gorm"
+)
+
+// AuditLogRepository 安全审计日志数据仓库
+type AuditLogRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditLogRepository 创建审计日志仓库
+func NewAuditLogRepository() *AuditLogRepository {
+	return &AuditLogRepository{db: database.GetDB()}
+}
+
+// Create 写入审计日志
+func (r *AuditLogRepository) Create(log *models.AuditLog) error {
+	return r.db.Create(log).Error
+}
+
+// List 分页查询审计日志 (按时间倒序)，action 与 userID 为空/0 时不过滤
+func (r *AuditLogRepository) List(page, pageSize int, action string, userID int64) ([]models.AuditLog, int64, error) {
+	var logs []models.AuditLog
+	var total int64
+	offset := (page - 1) * pageSize
+
+	query := r.db.Model(&models.AuditLog{})
+	if action != "" {
+		query = query.Where("action = ?", action)
+	}
+	if userID > 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Offset(offset).Limit(pageSize).Order("id DESC").Find(&logs).Error; err != nil {
+		return nil, 0, err
+	}
+	return logs, total, nil
+}
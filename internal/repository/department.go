@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/models"
+	"gorm.io/gorm"
+)
+
+// DepartmentRepository 部门数据仓库
+type DepartmentRepository struct {
+	db *gorm.DB
+}
+
+// NewDepartmentRepository 创建部门仓库
+func NewDepartmentRepository() *DepartmentRepository {
+	return &DepartmentRepository{db: database.GetDB()}
+}
+
+// List 获取全部部门 (按排序与ID)
+func (r *DepartmentRepository) List() ([]models.Department, error) {
+	var departments []models.Department
+	err := r.db.Order("sort ASC, id ASC").Find(&departments).Error
+	return departments, err
+}
+
+// FindByID 根据ID查找部门
+func (r *DepartmentRepository) FindByID(id int64) (*models.Department, error) {
+	var dept models.Department
+	if err := r.db.First(&dept, id).Error; err != nil {
+		return nil, err
+	}
+	return &dept, nil
+}
+
+// ExistsByName 检查同一上级部门下是否已有同名部门
+func (r *DepartmentRepository) ExistsByName(parentID int64, name string, excludeID int64) bool {
+	var count int64
+	query := r.db.Model(&models.Department{}).Where("parent_id = ? AND name = ?", parentID, name)
+	if excludeID > 0 {
+		query = query.Where("id != ?", excludeID)
+	}
+	query.Count(&count)
+	return count > 0
+}
+
+// EnsureByName 根据名称获取部门ID，不存在时创建为顶级部门 (名称为空返回 0)
+func (r *DepartmentRepository) EnsureByName(name string) (int64, error) {
+	if name == "" {
+		return 0, nil
+	}
+	var dept models.Department
+	if err := r.db.Where("name = ?", name).Order("parent_id, id").
+		Attrs(models.Department{Name: name}).FirstOrCreate(&dept).Error; err != nil {
+		return 0, err
+	}
+	return dept.ID, nil
+}
+
+// Create 创建部门
+func (r *DepartmentRepository) Create(dept *models.Department) error {
+	return r.db.Create(dept).Error
+}
+
+// Update 更新部门，同时同步成员的部门名称
+func (r *DepartmentRepository) Update(dept *models.Department) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(dept).Select("name", "parent_id", "manager_id", "sort").Updates(dept).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.User{}).Where("department_id = ?", dept.ID).Update("department", dept.Name).Error
+	})
+}
+
+// Delete 删除部门
+func (r *DepartmentRepository) Delete(id int64) error {
+	return r.db.Delete(&models.Department{}, id).Error
+}
+
+// CountChildren 统计直接下级部门数
+func (r *DepartmentRepository) CountChildren(id int64) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Department{}).Where("parent_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+// CountUsers 按部门统计用户数 (部门ID -> 用户数)
+func (r *DepartmentRepository) CountUsers() (map[int64]int64, error) {
+	var rows []struct {
+		DepartmentID int64
+		Total        int64
+	}
+	if err := r.db.Model(&models.User{}).Select("department_id, COUNT(*) AS total").
+		Where("department_id > 0").Group("department_id").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	counts := make(map[int64]int64, len(rows))
+	for _, row := range rows {
+		counts[row.DepartmentID] = row.Total
+	}
+	return counts, nil
+}
+
+// ClearManager 清除用户担任的部门负责人 (删除用户时调用)
+func (r *DepartmentRepository) ClearManager(userID int64) error {
+	return r.db.Model(&models.Department{}).Where("manager_id = ?", userID).Update("manager_id", 0).Error
+}
+
+// ListChildIDs 获取指定部门的直接下级部门ID
+func (r *DepartmentRepository) ListChildIDs(parentIDs []int64) ([]int64, error) {
+	var ids []int64
+	err := r.db.Model(&models.Department{}).Where("parent_id IN ?", parentIDs).Pluck("id", &ids).Error
+	return ids, err
+}
+
+// ListUserIDs 获取属于指定部门的用户ID
+func (r *DepartmentRepository) ListUserIDs(departmentIDs []int64) ([]int64, error) {
+	var ids []int64
+	err := r.db.Model(&models.User{}).Where("department_id IN ?", departmentIDs).Pluck("id", &ids).Error
+	return ids, err
+}
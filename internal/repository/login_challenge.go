@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/models"
+	"gorm.io/gorm"
+)
+
+// LoginChallengeRepository 两步验证登录挑战数据仓库
+type LoginChallengeRepository struct {
+	db *gorm.DB
+}
+
+// NewLoginChallengeRepository 创建登录挑战仓库
+func NewLoginChallengeRepository() *LoginChallengeRepository {
+	return &LoginChallengeRepository{db: database.GetDB()}
+}
+
+// Create 保存登录挑战
+func (r *LoginChallengeRepository) Create(challenge *models.LoginChallenge) error {
+	return r.db.Create(challenge).Error
+}
+
+// FindByHash 根据挑战令牌哈希查找
+func (r *LoginChallengeRepository) FindByHash(hash string) (*models.LoginChallenge, error) {
+	var challenge models.LoginChallenge
+	if err := r.db.Where("token_hash = ?", hash).First(&challenge).Error; err != nil {
+		return nil, err
+	}
+	return &challenge, nil
+}
+
+// IncrementAttempts 失败次数加一
+func (r *LoginChallengeRepository) IncrementAttempts(id int64) error {
+	return r.db.Model(&models.LoginChallenge{}).Where("id = ?", id).
+		UpdateColumn("attempts", gorm.Expr("attempts + 1")).Error
+}
+
+// Delete 删除登录挑战，返回是否删除成功 (并发提交时只有一方成功)
+func (r *LoginChallengeRepository) Delete(id int64) (bool, error) {
+	result := r.db.Delete(&models.LoginChallenge{}, id)
+	return result.RowsAffected > 0, result.Error
+}
+
+// DeleteExpired 清理已过期的登录挑战
+func (r *LoginChallengeRepository) DeleteExpired() error {
+	return r.db.Where("expire_time < ?", time.Now()).Delete(&models.LoginChallenge{}).Error
+}
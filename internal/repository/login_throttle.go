@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/models"
+	"gorm.io/gorm"
+)
+
+// LoginThrottleRepository 登录失败计数数据仓库
+type LoginThrottleRepository struct {
+	db *gorm.DB
+}
+
+// NewLoginThrottleRepository 创建登录失败计数仓库
+func NewLoginThrottleRepository() *LoginThrottleRepository {
+	return &LoginThrottleRepository{db: database.GetDB()}
+}
+
+// Find 查找计数记录
+func (r *LoginThrottleRepository) Find(kind, key string) (*models.LoginThrottle, error) {
+	var throttle models.LoginThrottle
+	if err := r.db.Where(&models.LoginThrottle{Kind: kind, Key: key}).First(&throttle).Error; err != nil {
+		return nil, err
+	}
+	return &throttle, nil
+}
+
+// Save 保存计数记录 (ID 为 0 时新增)
+func (r *LoginThrottleRepository) Save(throttle *models.LoginThrottle) error {
+	return r.db.Save(throttle).Error
+}
+
+// Delete 删除计数记录 (登录成功或管理员解锁)
+func (r *LoginThrottleRepository) Delete(kind, key string) error {
+	return r.db.Where(&models.LoginThrottle{Kind: kind, Key: key}).Delete(&models.LoginThrottle{}).Error
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/models"
+	"gorm.io/gorm"
+)
+
+// OIDCLoginRepository OIDC 单点登录状态数据仓库
+type OIDCLoginRepository struct {
+	db *gorm.DB
+}
+
+// NewOIDCLoginRepository 创建 OIDC 登录状态仓库
+func NewOIDCLoginRepository() *OIDCLoginRepository {
+	return &OIDCLoginRepository{db: database.GetDB()}
+}
+
+// Create 保存登录状态
+func (r *OIDCLoginRepository) Create(login *models.OIDCLogin) error {
+	return r.db.Create(login).Error
+}
+
+// FindByStateHash 根据 state 哈希查找
+func (r *OIDCLoginRepository) FindByStateHash(hash string) (*models.OIDCLogin, error) {
+	var login models.OIDCLogin
+	if err := r.db.Where("state_hash = ?", hash).First(&login).Error; err != nil {
+		return nil, err
+	}
+	return &login, nil
+}
+
+// FindByTicketHash 根据登录票据哈希查找
+func (r *OIDCLoginRepository) FindByTicketHash(hash string) (*models.OIDCLogin, error) {
+	var login models.OIDCLogin
+	if err := r.db.Where("ticket_hash = ?", hash).First(&login).Error; err != nil {
+		return nil, err
+	}
+	return &login, nil
+}
+
+// IssueTicket 回调验证通过后写入用户与登录票据，返回是否更新成功 (state 只能使用一次)
+func (r *OIDCLoginRepository) IssueTicket(id, userID int64, ticketHash string, expireTime time.Time) (bool, error) {
+	result := r.db.Model(&models.OIDCLogin{}).
+		Where("id = ? AND user_id = ?", id, 0).
+		Updates(map[string]interface{}{
+			"user_id":     userID,
+			"ticket_hash": ticketHash,
+			"expire_time": expireTime,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Delete 删除登录状态，返回是否删除成功 (并发提交时只有一方成功)
+func (r *OIDCLoginRepository) Delete(id int64) (bool, error) {
+	result := r.db.Delete(&models.OIDCLogin{}, id)
+	return result.RowsAffected > 0, result.Error
+}
+
+// DeleteExpired 清理已过期的登录状态
+func (r *OIDCLoginRepository) DeleteExpired() error {
+	return r.db.Where("expire_time < ?", time.Now()).Delete(&models.OIDCLogin{}).Error
+}
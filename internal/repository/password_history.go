@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/models"
+	"gorm.io/gorm"
+)
+
+// PasswordHistoryRepository 密码历史数据仓库
+type PasswordHistoryRepository struct {
+	db *gorm.DB
+}
+
+// NewPasswordHistoryRepository 创建密码历史仓库
+func NewPasswordHistoryRepository() *PasswordHistoryRepository {
+	return &PasswordHistoryRepository{db: database.GetDB()}
+}
+
+// Add 记录一次密码设置，只保留最近 keep 条
+func (r *PasswordHistoryRepository) Add(userID int64, hash string, keep int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&models.PasswordHistory{UserID: userID, PasswordHash: hash}).Error; err != nil {
+			return err
+		}
+		var ids []int64
+		if err := tx.Model(&models.PasswordHistory{}).
+			Where("user_id = ?", userID).
+			Order("id DESC").
+			Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) <= keep {
+			return nil
+		}
+		return tx.Delete(&models.PasswordHistory{}, ids[keep:]).Error
+	})
+}
+
+// ListRecent 获取用户最近使用过的密码哈希 (由新到旧)
+func (r *PasswordHistoryRepository) ListRecent(userID int64, limit int) ([]string, error) {
+	var hashes []string
+	err := r.db.Model(&models.PasswordHistory{}).
+		Where("user_id = ?", userID).
+		Order("id DESC").
+		Limit(limit).
+		Pluck("password_hash", &hashes).Error
+	return hashes, err
+}
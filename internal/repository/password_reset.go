@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/models"
+	"gorm.io/gorm"
+)
+
+// PasswordResetRepository 密码重置令牌数据仓库
+type PasswordResetRepository struct {
+	db *gorm.DB
+}
+
+// NewPasswordResetRepository 创建密码重置令牌仓库
+func NewPasswordResetRepository() *PasswordResetRepository {
+	return &PasswordResetRepository{db: database.GetDB()}
+}
+
+// Create 保存重置令牌
+func (r *PasswordResetRepository) Create(token *models.PasswordResetToken) error {
+	return r.db.Create(token).Error
+}
+
+// FindByHash 根据令牌哈希查找
+func (r *PasswordResetRepository) FindByHash(hash string) (*models.PasswordResetToken, error) {
+	var token models.PasswordResetToken
+	if err := r.db.Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// CountSince 统计某邮箱自指定时间以来的重置令牌数 (用于限制发送频率)
+func (r *PasswordResetRepository) CountSince(email string, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.PasswordResetToken{}).
+		Where("email = ? AND create_time >= ?", email, since).
+		Count(&count).Error
+	return count, err
+}
+
+// MarkUsed 标记令牌已使用，返回是否标记成功 (令牌只能使用一次，并发提交时只有一方成功)
+func (r *PasswordResetRepository) MarkUsed(id int64) (bool, error) {
+	result := r.db.Model(&models.PasswordResetToken{}).
+		Where("id = ? AND used_time IS NULL", id).
+		Update("used_time", time.Now())
+	return result.RowsAffected > 0, result.Error
+}
+
+// InvalidateByUser 使用户其余未使用的重置令牌失效
+func (r *PasswordResetRepository) InvalidateByUser(userID int64) error {
+	return r.db.Model(&models.PasswordResetToken{}).
+		Where("user_id = ? AND used_time IS NULL", userID).
+		Update("used_time", time.Now()).Error
+}
+
+// DeleteBefore 清理指定时间之前创建的令牌
+func (r *PasswordResetRepository) DeleteBefore(before time.Time) error {
+	return r.db.Where("create_time < ?", before).Delete(&models.PasswordResetToken{}).Error
+}
+
+// DeleteByUser 删除用户的全部重置令牌
+func (r *PasswordResetRepository) DeleteByUser(userID int64) error {
+	return r.db.Where("user_id = ?", userID).Delete(&models.PasswordResetToken{}).Error
+}
@@ -87,12 +87,12 @@ func (r *PaymentRepository) ListByProject(projectID int64) ([]models.Payment, er
 }
 
 // ListUpcoming 获取指定天数内即将到期待收款项
-func (r *PaymentRepository) ListUpcoming(userID int64, days int, limit int) ([]models.Payment, error) {
+func (r *PaymentRepository) ListUpcoming(scope DataScope, days int, limit int) ([]models.Payment, error) {
 	var payments []models.Payment
 	endDate := time.Now().AddDate(0, 0, days).Format("2006-01-02")
 
-	if err := r.db.Preload("Project").
-		Where("user_id = ? AND status = ? AND plan_date <= ?", userID, "pending", endDate).
+	if err := scope.apply(r.db.Preload("Project"), "project_id").
+		Where("status = ? AND plan_date <= ?", "pending", endDate).
 		Order("plan_date ASC").
 		Limit(limit).
 		Find(&payments).Error; err != nil {
@@ -103,11 +103,11 @@ func (r *PaymentRepository) ListUpcoming(userID int64, days int, limit int) ([]m
 
 // ListOverdue 获取当前已逾期的待收款项
 // 逾期定义: status="pending" 且 plan_date 小于今天
-func (r *PaymentRepository) ListOverdue(userID int64) ([]models.Payment, error) {
+func (r *PaymentRepository) ListOverdue(scope DataScope) ([]models.Payment, error) {
 	var payments []models.Payment
 	today := time.Now().Format("2006-01-02")
 
-	if err := r.db.Where("user_id = ? AND status = ? AND plan_date < ?", userID, "pending", today).
+	if err := scope.apply(r.db, "project_id").Where("status = ? AND plan_date < ?", "pending", today).
 		Find(&payments).Error; err != nil {
 		return nil, err
 	}
@@ -141,29 +141,29 @@ func (r *PaymentRepository) Confirm(id int64, actualDate, method string) error {
 }
 
 // SumByStatus 按状态统计金额
-func (r *PaymentRepository) SumByStatus(userID int64, status string) float64 {
+func (r *PaymentRepository) SumByStatus(scope DataScope, status string) float64 {
 	var sum float64
-	r.db.Model(&models.Payment{}).
-		Where("user_id = ? AND status = ?", userID, status).
+	scope.apply(r.db.Model(&models.Payment{}), "project_id").
+		Where("status = ?", status).
 		Select("COALESCE(SUM(amount), 0)").Scan(&sum)
 	return sum
 }
 
 // SumOverdue 统计逾期金额
-func (r *PaymentRepository) SumOverdue(userID int64) float64 {
+func (r *PaymentRepository) SumOverdue(scope DataScope) float64 {
 	var sum float64
 	today := time.Now().Format("2006-01-02")
-	r.db.Model(&models.Payment{}).
-		Where("user_id = ? AND status = ? AND plan_date < ?", userID, "pending", today).
+	scope.apply(r.db.Model(&models.Payment{}), "project_id").
+		Where("status = ? AND plan_date < ?", "pending", today).
 		Select("COALESCE(SUM(amount), 0)").Scan(&sum)
 	return sum
 }
 
 // ListByDateRange 根据日期范围获取收款列表
-func (r *PaymentRepository) ListByDateRange(userID int64, startDate, endDate string) ([]models.Payment, error) {
+func (r *PaymentRepository) ListByDateRange(scope DataScope, startDate, endDate string) ([]models.Payment, error) {
 	var payments []models.Payment
-	if err := r.db.Preload("Project").
-		Where("user_id = ? AND plan_date BETWEEN ? AND ?", userID, startDate, endDate).
+	if err := scope.apply(r.db.Preload("Project"), "project_id").
+		Where("plan_date BETWEEN ? AND ?", startDate, endDate).
 		Order("plan_date ASC").
 		Find(&payments).Error; err != nil {
 		return nil, err
@@ -176,7 +176,7 @@ func (r *PaymentRepository) ListByDateRange(userID int64, startDate, endDate str
 // 返回:
 //   - expected: map[日期]计划收款金额
 //   - actual: map[日期]实际已收款金额
-func (r *PaymentRepository) GetIncomeStats(userID int64, startDate, endDate, interval string) (map[string]float64, map[string]float64, error) {
+func (r *PaymentRepository) GetIncomeStats(scope DataScope, startDate, endDate, interval string) (map[string]float64, map[string]float64, error) {
 	expected := make(map[string]float64)
 	actual := make(map[string]float64)
 
@@ -192,9 +192,9 @@ func (r *PaymentRepository) GetIncomeStats(userID int64, startDate, endDate, int
 
 	// 1. 预期收入: 依据 plan_date 统计所有款项
 	var expectedResults []Result
-	if err := r.db.Model(&models.Payment{}).
+	if err := scope.apply(r.db.Model(&models.Payment{}), "project_id").
 		Select(dateExpr+" as date, COALESCE(SUM(amount), 0) as total").
-		Where("user_id = ? AND plan_date BETWEEN ? AND ?", userID, startDate, endDate).
+		Where("plan_date BETWEEN ? AND ?", startDate, endDate).
 		Group("date").
 		Scan(&expectedResults).Error; err != nil {
 		return nil, nil, err
@@ -205,9 +205,9 @@ func (r *PaymentRepository) GetIncomeStats(userID int64, startDate, endDate, int
 
 	// 2. 实际收入: 依据 actual_date 统计已完成(paid)的款项
 	var actualResults []Result
-	if err := r.db.Model(&models.Payment{}).
+	if err := scope.apply(r.db.Model(&models.Payment{}), "project_id").
 		Select(actualDateExpr+" as date, COALESCE(SUM(amount), 0) as total").
-		Where("user_id = ? AND status = 'paid' AND actual_date BETWEEN ? AND ?", userID, startDate, endDate).
+		Where("status = 'paid' AND actual_date BETWEEN ? AND ?", startDate, endDate).
 		Group("date").
 		Scan(&actualResults).Error; err != nil {
 		return nil, nil, err
@@ -219,42 +219,42 @@ func (r *PaymentRepository) GetIncomeStats(userID int64, startDate, endDate, int
 	return expected, actual, nil
 }
 
-// GetStatsByPeriod 获取指定时间周期内的综合指标
+// GetStatsByPeriod 获取数据范围内指定时间周期的综合指标
 // 返回值:
 //   - totalExpected: 计划在此期间应收总额
 //   - paid: 实际在此期间收到的金额
 //   - pending: 计划在此期间但尚未收到的金额 (包含逾期)
 //   - overdue: 计划在此期间且已逾期的金额 (plan_date < today)
 //   - avgPeriod: 平均回款周期 (天)
-func (r *PaymentRepository) GetStatsByPeriod(userID int64, startDate, endDate string) (total, paid, pending, overdue, avgPeriod float64, err error) {
+func (r *PaymentRepository) GetStatsByPeriod(scope DataScope, startDate, endDate string) (total, paid, pending, overdue, avgPeriod float64, err error) {
 	// 1. Total (TotalExpected): 计划日期在范围内的款项总和
-	r.db.Model(&models.Payment{}).
-		Where("user_id = ? AND plan_date BETWEEN ? AND ?", userID, startDate, endDate).
+	scope.apply(r.db.Model(&models.Payment{}), "project_id").
+		Where("plan_date BETWEEN ? AND ?", startDate, endDate).
 		Select("COALESCE(SUM(amount), 0)").Scan(&total)
 
 	// 2. Paid: 实际日期在范围内已支付的款项
-	r.db.Model(&models.Payment{}).
-		Where("user_id = ? AND status = 'paid' AND actual_date BETWEEN ? AND ?", userID, startDate, endDate).
+	scope.apply(r.db.Model(&models.Payment{}), "project_id").
+		Where("status = 'paid' AND actual_date BETWEEN ? AND ?", startDate, endDate).
 		Select("COALESCE(SUM(amount), 0)").Scan(&paid)
 
 	// 3. Pending: 计划日期在范围内，当前状态仍为 pending 的款项
-	r.db.Model(&models.Payment{}).
-		Where("user_id = ? AND status = 'pending' AND plan_date BETWEEN ? AND ?", userID, startDate, endDate).
+	scope.apply(r.db.Model(&models.Payment{}), "project_id").
+		Where("status = 'pending' AND plan_date BETWEEN ? AND ?", startDate, endDate).
 		Select("COALESCE(SUM(amount), 0)").Scan(&pending)
 
 	// 4. Overdue: 计划日期在范围内，且已逾期 (plan_date < today)
 	//    这是 Pending 的子集
 	today := time.Now().Format("2006-01-02")
-	r.db.Model(&models.Payment{}).
-		Where("user_id = ? AND status = 'pending' AND plan_date BETWEEN ? AND ? AND plan_date < ?", userID, startDate, endDate, today).
+	scope.apply(r.db.Model(&models.Payment{}), "project_id").
+		Where("status = 'pending' AND plan_date BETWEEN ? AND ? AND plan_date < ?", startDate, endDate, today).
 		Select("COALESCE(SUM(amount), 0)").Scan(&overdue)
 
 	// 5. AvgPeriod: 平均回款周期 (Actual Date - Plan Date)
 	//    仅统计在此期间实际到账的款项
 	dbType := database.GetDBType()
 	dateDiffExpr := getDateDiffExpr("actual_date", "plan_date", dbType)
-	r.db.Model(&models.Payment{}).
-		Where("user_id = ? AND status = 'paid' AND actual_date BETWEEN ? AND ?", userID, startDate, endDate).
+	scope.apply(r.db.Model(&models.Payment{}), "project_id").
+		Where("status = 'paid' AND actual_date BETWEEN ? AND ?", startDate, endDate).
 		Select("COALESCE(AVG(" + dateDiffExpr + "), 0)").Scan(&avgPeriod)
 
 	return total, paid, pending, overdue, avgPeriod, nil
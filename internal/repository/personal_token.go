@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/models"
+	"gorm.io/gorm"
+)
+
+// PersonalTokenRepository 个人访问令牌数据仓库
+type PersonalTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewPersonalTokenRepository 创建个人访问令牌仓库
+func NewPersonalTokenRepository() *PersonalTokenRepository {
+	return &PersonalTokenRepository{db: database.GetDB()}
+}
+
+// Create 保存个人访问令牌
+func (r *PersonalTokenRepository) Create(token *models.PersonalAccessToken) error {
+	return r.db.Create(token).Error
+}
+
+// FindByID 根据ID查找
+func (r *PersonalTokenRepository) FindByID(id int64) (*models.PersonalAccessToken, error) {
+	var token models.PersonalAccessToken
+	if err := r.db.First(&token, id).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// FindByHash 根据令牌哈希查找
+func (r *PersonalTokenRepository) FindByHash(hash string) (*models.PersonalAccessToken, error) {
+	var token models.PersonalAccessToken
+	if err := r.db.Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// ListByUser 获取用户的全部个人访问令牌 (最近创建的在前)
+func (r *PersonalTokenRepository) ListByUser(userID int64) ([]models.PersonalAccessToken, error) {
+	var tokens []models.PersonalAccessToken
+	err := r.db.Where("user_id = ?", userID).Order("create_time DESC").Find(&tokens).Error
+	return tokens, err
+}
+
+// CountByUser 统计用户的个人访问令牌数量
+func (r *PersonalTokenRepository) CountByUser(userID int64) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.PersonalAccessToken{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// UpdateFields 更新指定字段
+func (r *PersonalTokenRepository) UpdateFields(id int64, fields map[string]interface{}) error {
+	return r.db.Model(&models.PersonalAccessToken{}).Where("id = ?", id).Updates(fields).Error
+}
+
+// Delete 删除个人访问令牌
+func (r *PersonalTokenRepository) Delete(id int64) error {
+	return r.db.Delete(&models.PersonalAccessToken{}, id).Error
+}
+
+// DeleteByUser 删除用户的全部个人访问令牌
+func (r *PersonalTokenRepository) DeleteByUser(userID int64) error {
+	return r.db.Where("user_id = ?", userID).Delete(&models.PersonalAccessToken{}).Error
+}
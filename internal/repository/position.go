@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/models"
+	"gorm.io/gorm"
+)
+
+// PositionRepository 职位数据仓库
+type PositionRepository struct {
+	db *gorm.DB
+}
+
+// NewPositionRepository 创建职位仓库
+func NewPositionRepository() *PositionRepository {
+	return &PositionRepository{db: database.GetDB()}
+}
+
+// List 获取全部职位 (按排序与ID)
+func (r *PositionRepository) List() ([]models.Position, error) {
+	var positions []models.Position
+	err := r.db.Order("sort ASC, id ASC").Find(&positions).Error
+	return positions, err
+}
+
+// FindByID 根据ID查找职位
+func (r *PositionRepository) FindByID(id int64) (*models.Position, error) {
+	var position models.Position
+	if err := r.db.First(&position, id).Error; err != nil {
+		return nil, err
+	}
+	return &position, nil
+}
+
+// ExistsByName 检查职位名称是否存在
+func (r *PositionRepository) ExistsByName(name string, excludeID int64) bool {
+	var count int64
+	query := r.db.Model(&models.Position{}).Where("name = ?", name)
+	if excludeID > 0 {
+		query = query.Where("id != ?", excludeID)
+	}
+	query.Count(&count)
+	return count > 0
+}
+
+// EnsureByName 根据名称获取职位ID，不存在时创建 (名称为空返回 0)
+func (r *PositionRepository) EnsureByName(name string) (int64, error) {
+	if name == "" {
+		return 0, nil
+	}
+	var position models.Position
+	if err := r.db.Where("name = ?", name).Attrs(models.Position{Name: name}).FirstOrCreate(&position).Error; err != nil {
+		return 0, err
+	}
+	return position.ID, nil
+}
+
+// Create 创建职位
+func (r *PositionRepository) Create(position *models.Position) error {
+	return r.db.Create(position).Error
+}
+
+// Update 更新职位，同时同步用户的职位名称
+func (r *PositionRepository) Update(position *models.Position) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(position).Select("name", "description", "sort").Updates(position).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.User{}).Where("position_id = ?", position.ID).Update("position", position.Name).Error
+	})
+}
+
+// Delete 删除职位
+func (r *PositionRepository) Delete(id int64) error {
+	return r.db.Delete(&models.Position{}, id).Error
+}
+
+// CountUsers 按职位统计用户数 (职位ID -> 用户数)
+func (r *PositionRepository) CountUsers() (map[int64]int64, error) {
+	var rows []struct {
+		PositionID int64
+		Total      int64
+	}
+	if err := r.db.Model(&models.User{}).Select("position_id, COUNT(*) AS total").
+		Where("position_id > 0").Group("position_id").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	counts := make(map[int64]int64, len(rows))
+	for _, row := range rows {
+		counts[row.PositionID] = row.Total
+	}
+	return counts, nil
+}
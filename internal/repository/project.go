@@ -37,14 +37,14 @@ func (r *ProjectRepository) FindByIDWithPayments(id int64) (*models.Project, err
 }
 
 // List 分页查询项目列表
-// 支持按用户ID(数据隔离)、状态、关键词(名称或公司名)进行筛选。
+// 支持按数据范围(数据隔离)、状态、关键词(名称或公司名)进行筛选。
 // Preload("User"): 预加载关联的用户信息。
-func (r *ProjectRepository) List(userID int64, status, keyword string, page, pageSize int) ([]models.Project, int64, error) {
+func (r *ProjectRepository) List(scope DataScope, status, keyword string, page, pageSize int) ([]models.Project, int64, error) {
 	var projects []models.Project
 	var total int64
 
-	// 构建基础查询：限定用户，预加载关联
-	query := r.db.Model(&models.Project{}).Preload("User").Where("user_id = ?", userID)
+	// 构建基础查询：限定数据范围，预加载关联
+	query := scope.apply(r.db.Model(&models.Project{}).Preload("User"), "id")
 
 	// 动态条件筛选
 	if status != "" && status != "all" {
@@ -66,10 +66,10 @@ func (r *ProjectRepository) List(userID int64, status, keyword string, page, pag
 	return projects, total, nil
 }
 
-// ListRecent 获取最近项目
-func (r *ProjectRepository) ListRecent(userID int64, limit int) ([]models.Project, error) {
+// ListRecent 获取数据范围内的最近项目
+func (r *ProjectRepository) ListRecent(scope DataScope, limit int) ([]models.Project, error) {
 	var projects []models.Project
-	if err := r.db.Where("user_id = ?", userID).
+	if err := scope.apply(r.db, "id").
 		Order("create_time DESC").
 		Limit(limit).
 		Find(&projects).Error; err != nil {
@@ -78,6 +78,15 @@ func (r *ProjectRepository) ListRecent(userID int64, limit int) ([]models.Projec
 	return projects, nil
 }
 
+// InScope 判断项目是否在数据范围内
+func (r *ProjectRepository) InScope(id int64, scope DataScope) (bool, error) {
+	var count int64
+	if err := scope.apply(r.db.Model(&models.Project{}), "id").Where("id = ?", id).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
 // Create 创建项目
 func (r *ProjectRepository) Create(project *models.Project) error {
 	return r.db.Create(project).Error
@@ -98,21 +107,20 @@ func (r *ProjectRepository) UpdateStatus(id int64, status string) error {
 	return r.db.Model(&models.Project{}).Where("id = ?", id).Update("status", status).Error
 }
 
-// GetStats 获取用户维度的项目财务统计
+// GetStats 获取数据范围内的项目财务统计
 // 返回:
 //   - totalAmount: 所有项目的总合同金额之和
 //   - paidAmount: 所有实收金额之和 (关联 Payments 表统计)
 //   - pendingAmount: 待收金额 (total - paid)
-func (r *ProjectRepository) GetStats(userID int64) (totalAmount, paidAmount, pendingAmount float64, err error) {
+func (r *ProjectRepository) GetStats(scope DataScope) (totalAmount, paidAmount, pendingAmount float64, err error) {
 	// 1. 统计总合同金额 (SUM project.total_amount)
-	r.db.Model(&models.Project{}).Where("user_id = ?", userID).
+	scope.apply(r.db.Model(&models.Project{}), "id").
 		Select("COALESCE(SUM(total_amount), 0)").Scan(&totalAmount)
 
-	// 2. 统计已收金额 (关联查询 payment 表中 status='paid' 的记录)
-	r.db.Model(&models.Payment{}).
-		Joins("JOIN projects ON payments.project_id = projects.id").
-		Where("projects.user_id = ? AND payments.status = ?", userID, "paid").
-		Select("COALESCE(SUM(payments.amount), 0)").Scan(&paidAmount)
+	// 2. 统计已收金额 (范围内项目的 payment 表中 status='paid' 的记录)
+	scope.apply(r.db.Model(&models.Payment{}), "project_id").
+		Where("status = ?", "paid").
+		Select("COALESCE(SUM(amount), 0)").Scan(&paidAmount)
 
 	// 3. 计算待收金额
 	pendingAmount = totalAmount - paidAmount
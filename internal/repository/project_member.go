@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/models"
+	"gorm.io/gorm"
+)
+
+// ProjectMemberRepository 项目成员数据仓库
+type ProjectMemberRepository struct {
+	db *gorm.DB
+}
+
+// NewProjectMemberRepository 创建项目成员仓库
+func NewProjectMemberRepository() *ProjectMemberRepository {
+	return &ProjectMemberRepository{db: database.GetDB()}
+}
+
+// ListByProject 获取项目的全部成员 (含成员简要信息，按加入时间排序)
+func (r *ProjectMemberRepository) ListByProject(projectID int64) ([]models.ProjectMember, error) {
+	var members []models.ProjectMember
+	if err := r.db.Preload("User", selectUserBrief).
+		Where("project_id = ?", projectID).
+		Order("id").
+		Find(&members).Error; err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// Find 查找用户在项目中的成员记录
+func (r *ProjectMemberRepository) Find(projectID, userID int64) (*models.ProjectMember, error) {
+	var member models.ProjectMember
+	if err := r.db.Where("project_id = ? AND user_id = ?", projectID, userID).First(&member).Error; err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// Create 添加成员
+func (r *ProjectMemberRepository) Create(member *models.ProjectMember) error {
+	return r.db.Create(member).Error
+}
+
+// UpdateRole 修改成员的项目角色
+func (r *ProjectMemberRepository) UpdateRole(projectID, userID int64, role string) error {
+	return r.db.Model(&models.ProjectMember{}).
+		Where("project_id = ? AND user_id = ?", projectID, userID).
+		Update("role", role).Error
+}
+
+// Delete 移除成员
+func (r *ProjectMemberRepository) Delete(projectID, userID int64) error {
+	return r.db.Where("project_id = ? AND user_id = ?", projectID, userID).Delete(&models.ProjectMember{}).Error
+}
+
+// DeleteByUser 移除用户参与的全部项目 (删除用户时调用)
+func (r *ProjectMemberRepository) DeleteByUser(userID int64) error {
+	return r.db.Where("user_id = ?", userID).Delete(&models.ProjectMember{}).Error
+}
+
+// TransferOwner 转让项目
+// 新负责人原有的成员记录移除，原负责人以 formerRole 角色保留为成员 (为空表示不保留)。
+func (r *ProjectMemberRepository) TransferOwner(projectID, fromUserID, toUserID int64, formerRole string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Project{}).Where("id = ?", projectID).
+			Update("user_id", toUserID).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("project_id = ? AND user_id = ?", projectID, toUserID).
+			Delete(&models.ProjectMember{}).Error; err != nil {
+			return err
+		}
+		if formerRole == "" {
+			return nil
+		}
+		return tx.Create(&models.ProjectMember{ProjectID: projectID, UserID: fromUserID, Role: formerRole}).Error
+	})
+}
+
+// selectUserBrief 预加载用户时仅查询简要信息
+func selectUserBrief(db *gorm.DB) *gorm.DB {
+	return db.Select("id", "username", "name", "department", "position")
+}
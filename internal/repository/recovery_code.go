@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/models"
+	"gorm.io/gorm"
+)
+
+// RecoveryCodeRepository 两步验证恢复码数据仓库
+type RecoveryCodeRepository struct {
+	db *gorm.DB
+}
+
+// NewRecoveryCodeRepository 创建恢复码仓库
+func NewRecoveryCodeRepository() *RecoveryCodeRepository {
+	return &RecoveryCodeRepository{db: database.GetDB()}
+}
+
+// Replace 删除用户原有恢复码并保存新的一组
+func (r *RecoveryCodeRepository) Replace(userID int64, codes []models.RecoveryCode) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+// Use 使用恢复码，返回是否使用成功 (不存在或已使用时为 false)
+func (r *RecoveryCodeRepository) Use(userID int64, hash string) (bool, error) {
+	result := r.db.Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_time IS NULL", userID, hash).
+		Update("used_time", time.Now())
+	return result.RowsAffected > 0, result.Error
+}
+
+// CountUnused 统计用户未使用的恢复码数量
+func (r *RecoveryCodeRepository) CountUnused(userID int64) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND used_time IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// DeleteByUser 删除用户的全部恢复码
+func (r *RecoveryCodeRepository) DeleteByUser(userID int64) error {
+	return r.db.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/models"
+	"gorm.io/gorm"
+)
+
+// RefreshTokenRepository 刷新令牌数据仓库
+type RefreshTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewRefreshTokenRepository 创建刷新令牌仓库
+func NewRefreshTokenRepository() *RefreshTokenRepository {
+	return &RefreshTokenRepository{db: database.GetDB()}
+}
+
+// Create 保存刷新令牌
+func (r *RefreshTokenRepository) Create(token *models.RefreshToken) error {
+	return r.db.Create(token).Error
+}
+
+// FindByHash 根据令牌哈希查找
+func (r *RefreshTokenRepository) FindByHash(hash string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	if err := r.db.Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Revoke 吊销单个令牌
+// 仅当令牌尚未吊销时生效，返回是否由本次调用吊销 (用于并发刷新时保证只有一方成功)。
+func (r *RefreshTokenRepository) Revoke(id int64) (bool, error) {
+	result := r.db.Model(&models.RefreshToken{}).
+		Where("id = ? AND revoke_time IS NULL", id).
+		Update("revoke_time", time.Now())
+	return result.RowsAffected > 0, result.Error
+}
+
+// RevokeAllByUser 吊销用户的全部有效令牌
+func (r *RefreshTokenRepository) RevokeAllByUser(userID int64) error {
+	return r.db.Model(&models.RefreshToken{}).
+		Where("user_id = ? AND revoke_time IS NULL", userID).
+		Update("revoke_time", time.Now()).Error
+}
+
+// RevokeBySession 吊销会话下的全部有效令牌
+func (r *RefreshTokenRepository) RevokeBySession(sessionID int64) error {
+	return r.db.Model(&models.RefreshToken{}).
+		Where("session_id = ? AND revoke_time IS NULL", sessionID).
+		Update("revoke_time", time.Now()).Error
+}
+
+// DeleteExpired 清理已过期的令牌
+func (r *RefreshTokenRepository) DeleteExpired() error {
+	return r.db.Where("expire_time < ?", time.Now()).Delete(&models.RefreshToken{}).Error
+}
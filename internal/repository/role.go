@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/models"
+	"gorm.io/gorm"
+)
+
+// RoleRepository 角色与权限数据仓库
+type RoleRepository struct {
+	db *gorm.DB
+}
+
+// NewRoleRepository 创建角色仓库
+func NewRoleRepository() *RoleRepository {
+	return &RoleRepository{db: database.GetDB()}
+}
+
+// List 获取全部角色 (内置角色在前)
+func (r *RoleRepository) List() ([]models.Role, error) {
+	var roles []models.Role
+	err := r.db.Order("built_in DESC, id ASC").Find(&roles).Error
+	return roles, err
+}
+
+// FindByID 根据ID查找角色
+func (r *RoleRepository) FindByID(id int64) (*models.Role, error) {
+	var role models.Role
+	if err := r.db.First(&role, id).Error; err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// ExistsByCode 检查角色编码是否存在
+func (r *RoleRepository) ExistsByCode(code string) bool {
+	var count int64
+	r.db.Model(&models.Role{}).Where("code = ?", code).Count(&count)
+	return count > 0
+}
+
+// Create 创建角色并设置权限
+func (r *RoleRepository) Create(role *models.Role, permissionIDs []int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(role).Error; err != nil {
+			return err
+		}
+		return setRolePermissions(tx, role.ID, permissionIDs)
+	})
+}
+
+// Update 更新角色信息，permissionIDs 为 nil 时不修改权限
+func (r *RoleRepository) Update(role *models.Role, permissionIDs []int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(role).Select("name", "description", "data_scope").Updates(role).Error; err != nil {
+			return err
+		}
+		if permissionIDs == nil {
+			return nil
+		}
+		return setRolePermissions(tx, role.ID, permissionIDs)
+	})
+}
+
+// Delete 删除角色及其权限关联
+func (r *RoleRepository) Delete(id int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("role_id = ?", id).Delete(&models.RolePermission{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Role{}, id).Error
+	})
+}
+
+// CountUsers 统计使用该角色的用户数
+func (r *RoleRepository) CountUsers(code string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.User{}).Where("role = ?", code).Count(&count).Error
+	return count, err
+}
+
+// ListPermissions 获取全部权限 (按排序)
+func (r *RoleRepository) ListPermissions() ([]models.Permission, error) {
+	var permissions []models.Permission
+	err := r.db.Order("sort ASC, id ASC").Find(&permissions).Error
+	return permissions, err
+}
+
+// ListRolePermissions 获取全部角色的权限编码 (角色编码 -> 权限编码)
+func (r *RoleRepository) ListRolePermissions() (map[string][]string, error) {
+	var rows []struct {
+		RoleCode       string
+		PermissionCode string
+	}
+	err := r.db.Table("role_permissions").
+		Select("roles.code AS role_code, permissions.code AS permission_code").
+		Joins("JOIN roles ON roles.id = role_permissions.role_id").
+		Joins("JOIN permissions ON permissions.id = role_permissions.permission_id").
+		Order("permissions.sort ASC, permissions.id ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string][]string)
+	for _, row := range rows {
+		result[row.RoleCode] = append(result[row.RoleCode], row.PermissionCode)
+	}
+	return result, nil
+}
+
+// setRolePermissions 替换角色的全部权限
+func setRolePermissions(tx *gorm.DB, roleID int64, permissionIDs []int64) error {
+	if err := tx.Where("role_id = ?", roleID).Delete(&models.RolePermission{}).Error; err != nil {
+		return err
+	}
+	if len(permissionIDs) == 0 {
+		return nil
+	}
+	links := make([]models.RolePermission, 0, len(permissionIDs))
+	for _, id := range permissionIDs {
+		links = append(links, models.RolePermission{RoleID: roleID, PermissionID: id})
+	}
+	return tx.Create(&links).Error
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"github.com/FruitsAI/Orange/internal/models"
+	"gorm.io/gorm"
+)
+
+// DataScope 数据范围
+// 由调用方角色的数据范围解析得到，限定项目与收款查询可访问哪些用户负责或参与的项目。
+type DataScope struct {
+	All     bool    // 不限制 (全部数据)
+	UserIDs []int64 // 可访问的用户ID (All 为 false 时生效)
+}
+
+// SelfScope 仅本人的数据范围
+func SelfScope(userID int64) DataScope {
+	return DataScope{UserIDs: []int64{userID}}
+}
+
+// Contains 判断指定用户的数据是否在范围内
+func (s DataScope) Contains(userID int64) bool {
+	if s.All {
+		return true
+	}
+	for _, id := range s.UserIDs {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// apply 为查询追加数据范围条件，column 为项目ID列 (如 projects.id、payments.project_id)
+// 范围内的用户负责或作为成员参与的项目均可访问。
+func (s DataScope) apply(db *gorm.DB, column string) *gorm.DB {
+	if s.All {
+		return db
+	}
+	return db.Where(column+" IN (?)", s.projectIDs(db))
+}
+
+// projectIDs 数据范围内可访问的项目ID子查询
+func (s DataScope) projectIDs(db *gorm.DB) *gorm.DB {
+	sub := db.Session(&gorm.Session{NewDB: true})
+	members := sub.Model(&models.ProjectMember{}).Select("project_id").Where("user_id IN ?", s.UserIDs)
+	return sub.Model(&models.Project{}).Select("id").Where("user_id IN ? OR id IN (?)", s.UserIDs, members)
+}
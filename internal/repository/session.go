@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/models"
+	"gorm.io/gorm"
+)
+
+// SessionRepository 登录会话数据仓库
+type SessionRepository struct {
+	db *gorm.DB
+}
+
+// NewSessionRepository 创建登录会话仓库
+func NewSessionRepository() *SessionRepository {
+	return &SessionRepository{db: database.GetDB()}
+}
+
+// Create 保存会话
+func (r *SessionRepository) Create(session *models.UserSession) error {
+	return r.db.Create(session).Error
+}
+
+// FindByID 根据ID查找会话
+func (r *SessionRepository) FindByID(id int64) (*models.UserSession, error) {
+	var session models.UserSession
+	if err := r.db.First(&session, id).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// FindByTokenID 根据访问令牌 jti 查找会话
+func (r *SessionRepository) FindByTokenID(tokenID string) (*models.UserSession, error) {
+	var session models.UserSession
+	if err := r.db.Where("token_id = ?", tokenID).First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// ListActiveByUser 获取用户的有效会话 (按最近活跃倒序)
+func (r *SessionRepository) ListActiveByUser(userID int64) ([]models.UserSession, error) {
+	var sessions []models.UserSession
+	err := r.db.Where("user_id = ? AND revoke_time IS NULL", userID).
+		Order("last_seen_time DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// UpdateFields 更新会话字段
+func (r *SessionRepository) UpdateFields(id int64, fields map[string]interface{}) error {
+	return r.db.Model(&models.UserSession{}).Where("id = ?", id).Updates(fields).Error
+}
+
+// Revoke 吊销会话
+func (r *SessionRepository) Revoke(id int64) error {
+	return r.db.Model(&models.UserSession{}).
+		Where("id = ? AND revoke_time IS NULL", id).
+		Update("revoke_time", time.Now()).Error
+}
+
+// RevokeAllByUser 吊销用户的全部会话
+func (r *SessionRepository) RevokeAllByUser(userID int64) error {
+	return r.db.Model(&models.UserSession{}).
+		Where("user_id = ? AND revoke_time IS NULL", userID).
+		Update("revoke_time", time.Now()).Error
+}
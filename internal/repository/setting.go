@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SettingRepository 系统设置数据仓库
+type SettingRepository struct {
+	db *gorm.DB
+}
+
+// NewSettingRepository 创建系统设置仓库
+func NewSettingRepository() *SettingRepository {
+	return &SettingRepository{db: database.GetDB()}
+}
+
+// Get 读取设置值，不存在时返回空字符串
+func (r *SettingRepository) Get(key string) (string, error) {
+	var setting models.SystemSetting
+	err := r.db.Where(&models.SystemSetting{Key: key}).First(&setting).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", nil
+	}
+	return setting.Value, err
+}
+
+// Set 保存设置值 (不存在时插入)
+func (r *SettingRepository) Set(key, value string) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "update_time"}),
+	}).Create(&models.SystemSetting{Key: key, Value: value}).Error
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/models"
+	"gorm.io/gorm"
+)
+
+// SyncRepository 同步状态数据仓库
+// 负责增量同步水位线 (sync_states) 与删除墓碑 (sync_tombstones) 的读写。
+type SyncRepository struct {
+	db *gorm.DB
+}
+
+// NewSyncRepository 创建同步状态仓库
+func NewSyncRepository() *SyncRepository {
+	return &SyncRepository{db: database.GetDB()}
+}
+
+// FindState 获取指定目标、指定表的同步状态
+// 从未同步过时返回一个未持久化的空状态 (ID=0)。
+func (r *SyncRepository) FindState(target, table string) (*models.SyncState, error) {
+	var state models.SyncState
+	err := r.db.Where("target = ? AND table_name = ?", target, table).First(&state).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &models.SyncState{Target: target, Table: table}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// SaveState 保存同步状态 (新增或更新)
+func (r *SyncRepository) SaveState(state *models.SyncState) error {
+	return r.db.Save(state).Error
+}
+
+// ListTombstones 获取指定表在 since 之后产生的删除墓碑 (按删除时间升序)
+// since 为 nil 时返回该表全部墓碑。
+func (r *SyncRepository) ListTombstones(table string, since *time.Time) ([]models.SyncTombstone, error) {
+	var tombstones []models.SyncTombstone
+	query := r.db.Where("table_name = ?", table)
+	if since != nil {
+		query = query.Where("delete_time > ?", *since)
+	}
+	if err := query.Order("delete_time ASC").Find(&tombstones).Error; err != nil {
+		return nil, err
+	}
+	return tombstones, nil
+}
+
+// LatestTombstoneTime 获取指定表最近一条墓碑的删除时间，没有墓碑时返回 nil
+func (r *SyncRepository) LatestTombstoneTime(table string) (*time.Time, error) {
+	var tombstone models.SyncTombstone
+	err := r.db.Where("table_name = ?", table).Order("delete_time DESC").First(&tombstone).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tombstone.DeleteTime, nil
+}
+
+// CountTombstones 统计指定表在 since 之后产生的墓碑数量
+func (r *SyncRepository) CountTombstones(table string, since *time.Time) (int64, error) {
+	var count int64
+	query := r.db.Model(&models.SyncTombstone{}).Where("table_name = ?", table)
+	if since != nil {
+		query = query.Where("delete_time > ?", *since)
+	}
+	err := query.Count(&count).Error
+	return count, err
+}
+
+// CreateRun 新增同步执行记录
+func (r *SyncRepository) CreateRun(run *models.SyncRun) error {
+	return r.db.Create(run).Error
+}
+
+// SaveRun 更新同步执行记录
+func (r *SyncRepository) SaveRun(run *models.SyncRun) error {
+	return r.db.Save(run).Error
+}
+
+// ListRuns 分页获取同步执行记录 (按开始时间倒序)
+// status 为空时返回全部状态。
+func (r *SyncRepository) ListRuns(status string, offset, limit int) ([]models.SyncRun, int64, error) {
+	var runs []models.SyncRun
+	var total int64
+
+	query := r.db.Model(&models.SyncRun{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Order("start_time DESC").Offset(offset).Limit(limit).Find(&runs).Error; err != nil {
+		return nil, 0, err
+	}
+	return runs, total, nil
+}
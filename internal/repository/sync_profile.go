@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/models"
+	"gorm.io/gorm"
+)
+
+// SyncProfileRepository 同步连接配置数据仓库
+type SyncProfileRepository struct {
+	db *gorm.DB
+}
+
+// NewSyncProfileRepository 创建同步连接配置仓库
+func NewSyncProfileRepository() *SyncProfileRepository {
+	return &SyncProfileRepository{db: database.GetDB()}
+}
+
+// List 获取全部连接配置 (按名称排序)
+func (r *SyncProfileRepository) List() ([]models.SyncProfile, error) {
+	var profiles []models.SyncProfile
+	if err := r.db.Order("name ASC").Find(&profiles).Error; err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
+
+// FindByID 根据ID查找连接配置
+func (r *SyncProfileRepository) FindByID(id int64) (*models.SyncProfile, error) {
+	var profile models.SyncProfile
+	if err := r.db.First(&profile, id).Error; err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// ExistsByName 检查名称是否已被其他配置使用
+func (r *SyncProfileRepository) ExistsByName(name string, excludeID int64) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.SyncProfile{}).Where("name = ? AND id <> ?", name, excludeID).Count(&count).Error
+	return count > 0, err
+}
+
+// Create 新增连接配置
+func (r *SyncProfileRepository) Create(profile *models.SyncProfile) error {
+	return r.db.Create(profile).Error
+}
+
+// Update 更新连接配置
+func (r *SyncProfileRepository) Update(profile *models.SyncProfile) error {
+	return r.db.Save(profile).Error
+}
+
+// Delete 删除连接配置
+func (r *SyncProfileRepository) Delete(id int64) error {
+	return r.db.Delete(&models.SyncProfile{}, id).Error
+}
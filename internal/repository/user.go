@@ -25,6 +25,16 @@ func (r *UserRepository) FindByID(id int64) (*models.User, error) {
 	return &user, nil
 }
 
+// FindByIDs 根据ID批量查找用户
+func (r *UserRepository) FindByIDs(ids []int64) ([]models.User, error) {
+	var users []models.User
+	if len(ids) == 0 {
+		return users, nil
+	}
+	err := r.db.Where("id IN ?", ids).Find(&users).Error
+	return users, err
+}
+
 // FindByUsername 根据用户名查找用户
 func (r *UserRepository) FindByUsername(username string) (*models.User, error) {
 	var user models.User
@@ -86,6 +96,35 @@ func (r *UserRepository) UpdateFields(id int64, fields map[string]interface{}) e
 	return r.db.Model(&models.User{}).Where("id = ?", id).Updates(fields).Error
 }
 
+// IncrementTokenVersion 递增用户令牌版本，使已签发的访问令牌全部失效
+func (r *UserRepository) IncrementTokenVersion(id int64) error {
+	return r.db.Model(&models.User{}).Where("id = ?", id).
+		UpdateColumn("token_version", gorm.Expr("token_version + 1")).Error
+}
+
+// AdvanceTOTPStep 记录已使用的 TOTP 时间步，返回是否记录成功
+// 时间步不大于已记录值时返回 false (验证码已被使用)。
+func (r *UserRepository) AdvanceTOTPStep(id, step int64) (bool, error) {
+	result := r.db.Model(&models.User{}).
+		Where("id = ? AND totp_last_step < ?", id, step).
+		UpdateColumn("totp_last_step", step)
+	return result.RowsAffected > 0, result.Error
+}
+
+// SearchBrief 按用户名或姓名搜索正常状态的用户 (仅返回简要信息)
+func (r *UserRepository) SearchBrief(keyword string, limit int) ([]models.User, error) {
+	var users []models.User
+	query := selectUserBrief(r.db).Where("status = 1")
+	if keyword != "" {
+		likePattern := "%" + keyword + "%"
+		query = query.Where("username LIKE ? OR name LIKE ?", likePattern, likePattern)
+	}
+	if err := query.Order("id").Limit(limit).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
 // List 获取用户列表 (支持分页和搜索)
 func (r *UserRepository) List(page, pageSize int, keyword string) ([]models.User, int64, error) {
 	var users []models.User
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/models"
+	"gorm.io/gorm"
+)
+
+// UserIdentityRepository 外部身份绑定数据仓库
+type UserIdentityRepository struct {
+	db *gorm.DB
+}
+
+// NewUserIdentityRepository 创建外部身份绑定仓库
+func NewUserIdentityRepository() *UserIdentityRepository {
+	return &UserIdentityRepository{db: database.GetDB()}
+}
+
+// Find 根据身份提供方与用户标识查找
+func (r *UserIdentityRepository) Find(provider, subject string) (*models.UserIdentity, error) {
+	var identity models.UserIdentity
+	if err := r.db.Where(&models.UserIdentity{Provider: provider, Subject: subject}).First(&identity).Error; err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+// Create 保存外部身份绑定
+func (r *UserIdentityRepository) Create(identity *models.UserIdentity) error {
+	return r.db.Create(identity).Error
+}
+
+// UpdateEmail 更新最近一次登录时的邮箱 (同时刷新更新时间)
+func (r *UserIdentityRepository) UpdateEmail(id int64, email string) error {
+	return r.db.Model(&models.UserIdentity{ID: id}).Update("email", email).Error
+}
+
+// DeleteByUser 删除用户的全部外部身份绑定
+func (r *UserIdentityRepository) DeleteByUser(userID int64) error {
+	return r.db.Where("user_id = ?", userID).Delete(&models.UserIdentity{}).Error
+}
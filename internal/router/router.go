@@ -5,6 +5,7 @@ import (
 
 	"github.com/FruitsAI/Orange/internal/handler"
 	"github.com/FruitsAI/Orange/internal/middleware"
+	"github.com/FruitsAI/Orange/internal/service"
 	"github.com/gin-gonic/gin"
 )
 
@@ -30,13 +31,23 @@ func NewRouter() *gin.Engine {
 		auth := v1.Group("/auth")
 		{
 			authHandler := handler.NewAuthHandler()
-			auth.POST("/login", authHandler.Login)       // 登录获取 Token
-			auth.POST("/register", authHandler.Register) // 用户注册
-			auth.POST("/logout", authHandler.Logout)     // 注销 (客户端清除)
+			auth.POST("/login", authHandler.Login)                    // 登录获取 Token
+			auth.POST("/login/2fa", authHandler.VerifyTwoFactor)      // 两步验证登录
+			auth.POST("/refresh", authHandler.Refresh)                // 刷新令牌 (轮换)
+			auth.POST("/register", authHandler.Register)              // 用户注册
+			auth.POST("/logout", authHandler.Logout)                  // 注销 (吊销当前登录会话)
+			auth.GET("/password-policy", authHandler.PasswordPolicy)  // 密码策略
+			auth.POST("/forgot-password", authHandler.ForgotPassword) // 找回密码 (发送重置邮件)
+			auth.POST("/reset-password", authHandler.ResetPassword)   // 凭重置码设置新密码
+			auth.GET("/oidc", authHandler.OIDCInfo)                   // 单点登录配置
+			auth.GET("/oidc/login", authHandler.OIDCLogin)            // 发起单点登录 (重定向到身份提供方)
+			auth.GET("/oidc/callback", authHandler.OIDCCallback)      // 单点登录回调 (重定向到登录页)
+			auth.POST("/oidc/token", authHandler.OIDCToken)           // 凭单点登录票据换取令牌
+			auth.GET("/jwks", authHandler.JWKS)                       // 访问令牌签名公钥集 (JWKS)
 		}
 
 		// 3.2 受保护路由 (需要 JWT 鉴权)
-		// 使用 JWTAuth 中间件验证 Authorization 头
+		// 使用 JWTAuth 中间件验证 Authorization 头，RequirePermission 按角色权限控制访问
 		authorized := v1.Group("")
 		authorized.Use(middleware.JWTAuth())
 		{
@@ -50,50 +61,107 @@ func NewRouter() *gin.Engine {
 				users.GET("/me", authHandler.GetCurrentUser)
 				users.PUT("/me", authHandler.UpdateProfile)
 				users.PUT("/me/password", authHandler.ChangePassword)
+				users.GET("/me/sessions", authHandler.ListSessions)
+				users.DELETE("/me/sessions/:sid", authHandler.RevokeSession)
+				users.GET("/me/2fa", authHandler.TwoFactorStatus)
+				users.POST("/me/2fa/setup", authHandler.SetupTwoFactor)
+				users.POST("/me/2fa/enable", authHandler.EnableTwoFactor)
+				users.POST("/me/2fa/disable", authHandler.DisableTwoFactor)
+				users.POST("/me/2fa/recovery-codes", authHandler.RegenerateRecoveryCodes)
+				users.GET("/me/tokens", authHandler.ListPersonalTokens)
+				users.POST("/me/tokens", authHandler.CreatePersonalToken)
+				users.GET("/me/tokens/scopes", authHandler.PersonalTokenScopes)
+				users.DELETE("/me/tokens/:tid", authHandler.RevokePersonalToken)
 
-				// 管理员接口 (内部已做权限校验)
-				users.GET("", userHandler.List)
-				users.POST("", userHandler.Create)
-				users.PUT("/:id", userHandler.Update)
-				users.DELETE("/:id", userHandler.Delete)
-				users.PUT("/:id/password", userHandler.ResetPassword)
+				// 用户管理接口
+				manage := users.Group("", middleware.RequirePermission(service.PermUserManage))
+				manage.GET("", userHandler.List)
+				manage.POST("", userHandler.Create)
+				manage.PUT("/:id", userHandler.Update)
+				manage.DELETE("/:id", userHandler.Delete)
+				manage.PUT("/:id/password", userHandler.ResetPassword)
+				manage.GET("/:id/sessions", userHandler.ListSessions)
+				manage.DELETE("/:id/sessions", userHandler.RevokeAllSessions)
+				manage.DELETE("/:id/sessions/:sid", userHandler.RevokeSession)
+				manage.DELETE("/:id/2fa", userHandler.ResetTwoFactor)
+				manage.POST("/:id/unlock", userHandler.Unlock)
+				manage.GET("/:id/tokens", userHandler.ListPersonalTokens)
+				manage.DELETE("/:id/tokens/:tid", userHandler.RevokePersonalToken)
+			}
+
+			// 角色与权限模块
+			roleHandler := handler.NewRoleHandler()
+			roles := authorized.Group("/roles")
+			{
+				roles.GET("", middleware.RequireAnyPermission(service.PermRoleManage, service.PermUserManage), roleHandler.List) // 角色列表
+				roles.POST("", middleware.RequirePermission(service.PermRoleManage), roleHandler.Create)                         // 创建角色
+				roles.PUT("/:id", middleware.RequirePermission(service.PermRoleManage), roleHandler.Update)                      // 更新角色
+				roles.DELETE("/:id", middleware.RequirePermission(service.PermRoleManage), roleHandler.Delete)                   // 删除角色
+			}
+			authorized.GET("/permissions", middleware.RequirePermission(service.PermRoleManage), roleHandler.Permissions) // 权限列表
+
+			// 组织架构模块 (部门与职位)
+			departmentHandler := handler.NewDepartmentHandler()
+			departments := authorized.Group("/departments")
+			{
+				departments.GET("", middleware.RequireAnyPermission(service.PermOrgManage, service.PermUserManage), departmentHandler.Tree) // 部门树
+				departments.POST("", middleware.RequirePermission(service.PermOrgManage), departmentHandler.Create)                         // 创建部门
+				departments.PUT("/:id", middleware.RequirePermission(service.PermOrgManage), departmentHandler.Update)                      // 更新部门
+				departments.DELETE("/:id", middleware.RequirePermission(service.PermOrgManage), departmentHandler.Delete)                   // 删除部门
+			}
+			positionHandler := handler.NewPositionHandler()
+			positions := authorized.Group("/positions")
+			{
+				positions.GET("", middleware.RequireAnyPermission(service.PermOrgManage, service.PermUserManage), positionHandler.List) // 职位列表
+				positions.POST("", middleware.RequirePermission(service.PermOrgManage), positionHandler.Create)                         // 创建职位
+				positions.PUT("/:id", middleware.RequirePermission(service.PermOrgManage), positionHandler.Update)                      // 更新职位
+				positions.DELETE("/:id", middleware.RequirePermission(service.PermOrgManage), positionHandler.Delete)                   // 删除职位
 			}
 
 			// 项目管理模块
 			projects := authorized.Group("/projects")
 			{
 				projectHandler := handler.NewProjectHandler()
-				projects.GET("", projectHandler.List) // 项目列表
+				memberHandler := handler.NewProjectMemberHandler()
+				projects.GET("", middleware.RequirePermission(service.PermProjectView), projectHandler.List) // 项目列表
 
 				// 工具类接口：合同编号检查与生成
 				// 注意：这两个特定路径的路由必须放在 /:id 通配符之前，否则会被 /:id 优先匹配拦截
-				projects.GET("/check-contract-number", projectHandler.CheckContractNumber)
-				projects.GET("/generate-contract-number", projectHandler.GenerateContractNumber)
+				projects.GET("/check-contract-number", middleware.RequirePermission(service.PermProjectView), projectHandler.CheckContractNumber)
+				projects.GET("/generate-contract-number", middleware.RequirePermission(service.PermProjectCreate), projectHandler.GenerateContractNumber)
+				projects.GET("/member-candidates", middleware.RequirePermission(service.PermProjectView), memberHandler.Candidates)
+
+				projects.GET("/:id", middleware.RequirePermission(service.PermProjectView), projectHandler.Get)                 // 项目详情
+				projects.POST("", middleware.RequirePermission(service.PermProjectCreate), projectHandler.Create)               // 创建项目
+				projects.PUT("/:id", middleware.RequirePermission(service.PermProjectEdit), projectHandler.Update)              // 更新项目
+				projects.DELETE("/:id", middleware.RequirePermission(service.PermProjectDelete), projectHandler.Delete)         // 删除项目
+				projects.POST("/:id/archive", middleware.RequirePermission(service.PermProjectArchive), projectHandler.Archive) // 归档项目
 
-				projects.GET("/:id", projectHandler.Get)              // 项目详情
-				projects.POST("", projectHandler.Create)              // 创建项目
-				projects.PUT("/:id", projectHandler.Update)           // 更新项目
-				projects.DELETE("/:id", projectHandler.Delete)        // 删除项目
-				projects.POST("/:id/archive", projectHandler.Archive) // 归档项目
+				// 项目成员 (项目内的操作另按成员的项目角色校验)
+				projects.GET("/:id/members", middleware.RequirePermission(service.PermProjectView), memberHandler.List)            // 成员列表
+				projects.POST("/:id/members", middleware.RequirePermission(service.PermProjectEdit), memberHandler.Add)            // 添加成员
+				projects.PUT("/:id/members/:uid", middleware.RequirePermission(service.PermProjectEdit), memberHandler.UpdateRole) // 修改成员角色
+				projects.DELETE("/:id/members/:uid", middleware.RequirePermission(service.PermProjectView), memberHandler.Remove)  // 移除成员 (成员可退出)
+				projects.POST("/:id/transfer", middleware.RequirePermission(service.PermProjectEdit), memberHandler.Transfer)      // 转让项目
 
 				// 项目收款
 				paymentHandler := handler.NewPaymentHandler()
-				projects.GET("/:id/payments", paymentHandler.GetByProject)
+				projects.GET("/:id/payments", middleware.RequirePermission(service.PermPaymentView), paymentHandler.GetByProject)
 			}
 
 			// 款项管理模块
 			payments := authorized.Group("/payments")
 			{
 				paymentHandler := handler.NewPaymentHandler()
-				payments.GET("", paymentHandler.List)                 // 款项列表
-				payments.POST("", paymentHandler.Create)              // 创建款项
-				payments.PUT("/:id", paymentHandler.Update)           // 更新款项
-				payments.DELETE("/:id", paymentHandler.Delete)        // 删除款项
-				payments.POST("/:id/confirm", paymentHandler.Confirm) // 确认收款
+				payments.GET("", middleware.RequirePermission(service.PermPaymentView), paymentHandler.List)                    // 款项列表
+				payments.POST("", middleware.RequirePermission(service.PermPaymentCreate), paymentHandler.Create)               // 创建款项
+				payments.PUT("/:id", middleware.RequirePermission(service.PermPaymentEdit), paymentHandler.Update)              // 更新款项
+				payments.DELETE("/:id", middleware.RequirePermission(service.PermPaymentDelete), paymentHandler.Delete)         // 删除款项
+				payments.POST("/:id/confirm", middleware.RequirePermission(service.PermPaymentConfirm), paymentHandler.Confirm) // 确认收款
 			}
 
 			// 仪表盘统计模块
-			dashboard := authorized.Group("/dashboard")
+			dashboard := authorized.Group("/dashboard", middleware.RequirePermission(service.PermDashboardView))
 			{
 				dashboardHandler := handler.NewDashboardHandler()
 				dashboard.GET("/stats", dashboardHandler.Stats)
@@ -106,11 +174,13 @@ func NewRouter() *gin.Engine {
 			dictionaries := authorized.Group("/dictionaries")
 			{
 				dictHandler := handler.NewDictionaryHandler()
-				dictionaries.GET("", dictHandler.List)                          // 字典类型列表
-				dictionaries.GET("/:code/items", dictHandler.GetItems)          // 获取指定字典的选项
-				dictionaries.POST("/:code/items", dictHandler.CreateItem)       // 新增选项
-				dictionaries.PUT("/:code/items/:id", dictHandler.UpdateItem)    // 更新选项
-				dictionaries.DELETE("/:code/items/:id", dictHandler.DeleteItem) // 删除选项
+				dictionaries.GET("", dictHandler.List)                 // 字典类型列表
+				dictionaries.GET("/:code/items", dictHandler.GetItems) // 获取指定字典的选项
+
+				edit := dictionaries.Group("", middleware.RequirePermission(service.PermDictionaryEdit))
+				edit.POST("/:code/items", dictHandler.CreateItem)       // 新增选项
+				edit.PUT("/:code/items/:id", dictHandler.UpdateItem)    // 更新选项
+				edit.DELETE("/:code/items/:id", dictHandler.DeleteItem) // 删除选项
 			}
 
 			// 通知中心模块
@@ -118,13 +188,30 @@ func NewRouter() *gin.Engine {
 			{
 				notificationHandler := handler.NewNotificationHandler()
 				notifications.GET("", notificationHandler.List)                     // 通知列表
-				notifications.POST("", notificationHandler.Create)                  // 发送通知 (私信/广播)
 				notifications.GET("/:id", notificationHandler.Get)                  // 通知详情
-				notifications.PUT("/:id", notificationHandler.Update)               // 更新通知
 				notifications.GET("/unread-count", notificationHandler.UnreadCount) // 未读数
-				notifications.GET("/users", notificationHandler.ListUsers)          // 可通知用户列表
 				notifications.PUT("/:id/read", notificationHandler.MarkAsRead)      // 标记已读
-				notifications.DELETE("/:id", notificationHandler.Delete)            // 删除通知
+
+				send := notifications.Group("", middleware.RequirePermission(service.PermNotificationSend))
+				send.POST("", notificationHandler.Create)         // 发送通知 (私信/广播)
+				send.PUT("/:id", notificationHandler.Update)      // 更新通知
+				send.GET("/users", notificationHandler.ListUsers) // 可通知用户列表
+				send.DELETE("/:id", notificationHandler.Delete)   // 删除通知
+			}
+
+			// 系统设置模块
+			settings := authorized.Group("/settings", middleware.RequirePermission(service.PermSettingManage))
+			{
+				settingHandler := handler.NewSettingHandler()
+				settings.GET("/security", settingHandler.GetSecurity)    // 获取安全设置
+				settings.PUT("/security", settingHandler.UpdateSecurity) // 更新安全设置
+			}
+
+			// 安全审计日志
+			auditLogs := authorized.Group("/audit-logs", middleware.RequirePermission(service.PermAuditView))
+			{
+				auditHandler := handler.NewAuditHandler()
+				auditLogs.GET("", auditHandler.List) // 审计日志列表
 			}
 
 			// 系统级功能模块
@@ -132,16 +219,36 @@ func NewRouter() *gin.Engine {
 			{
 				systemHandler := handler.NewSystemHandler()
 				system.GET("/updates/check", systemHandler.CheckUpdate)
+
+				authHandler := handler.NewAuthHandler()
+				keys := system.Group("/signing-keys", middleware.RequirePermission(service.PermSettingManage))
+				keys.GET("", authHandler.SigningKeys)              // 签名密钥列表
+				keys.POST("/rotate", authHandler.RotateSigningKey) // 轮换签名密钥
 			}
 
 			// 数据同步模块
-			sync := authorized.Group("/sync")
+			sync := authorized.Group("/sync", middleware.RequirePermission(service.PermSyncExecute))
 			{
 				syncHandler := handler.NewSyncHandler()
 				sync.GET("/config", syncHandler.GetConfig)                // 获取配置
 				sync.POST("/test-connection", syncHandler.TestConnection) // 测试云端数据库连接
 				sync.POST("/compare", syncHandler.Compare)                // 对比本地与云端数据
+				sync.POST("/diff", syncHandler.Diff)                      // 逐行对比本地与云端数据
 				sync.POST("/execute", syncHandler.Execute)                // 执行数据同步
+				sync.POST("/schema", syncHandler.Schema)                  // 创建/迁移云端表结构 (支持 dry-run)
+				sync.POST("/jobs", syncHandler.SubmitJob)                 // 提交后台同步任务
+				sync.GET("/jobs/:id", syncHandler.GetJob)                 // 查询同步任务进度
+				sync.GET("/jobs/:id/events", syncHandler.JobEvents)       // 订阅同步任务进度 (SSE)
+				sync.POST("/jobs/:id/cancel", syncHandler.CancelJob)      // 取消同步任务
+				sync.GET("/runs", syncHandler.ListRuns)                   // 同步执行历史
+
+				syncProfileHandler := handler.NewSyncProfileHandler()
+				sync.GET("/profiles", syncProfileHandler.List) // 连接配置列表
+
+				profiles := sync.Group("/profiles", middleware.RequirePermission(service.PermSyncManage))
+				profiles.POST("", syncProfileHandler.Create)       // 新增连接配置
+				profiles.PUT("/:id", syncProfileHandler.Update)    // 更新连接配置
+				profiles.DELETE("/:id", syncProfileHandler.Delete) // 删除连接配置
 			}
 		}
 	}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"log/slog"
+
+	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/repository"
+)
+
+// 审计事件类型
+const (
+	AuditLoginFailed       = "login_failed"        // 用户名或密码错误
+	AuditLoginBlocked      = "login_blocked"       // 账户或 IP 锁定/等待期内的登录尝试
+	AuditAccountLocked     = "account_locked"      // 账户因连续失败被锁定
+	AuditIPLocked          = "ip_locked"           // IP 因连续失败被锁定
+	AuditAccountUnlocked   = "account_unlocked"    // 管理员解锁账户
+	AuditTwoFactorFailed   = "two_factor_failed"   // 两步验证码错误
+	AuditOIDCFailed        = "oidc_failed"         // 单点登录回调校验失败 (ID Token 无效、账户冲突等)
+	AuditSigningKeyRotated = "signing_key_rotated" // 管理员轮换令牌签名密钥
+	AuditPasswordResetSent = "password_reset_sent" // 发送找回密码邮件 (或因频率限制未发送)
+	AuditPasswordReset     = "password_reset"      // 通过邮件重置密码
+)
+
+// AuditService 安全审计服务
+// 审计写入失败只记录日志，不影响业务流程。
+//
+// 依赖:
+//   - AuditLogRepository: 审计日志的读写
+type AuditService struct {
+	auditRepo *repository.AuditLogRepository
+}
+
+// NewAuditService 创建审计服务实例
+func NewAuditService() *AuditService {
+	return &AuditService{
+		auditRepo: repository.NewAuditLogRepository(),
+	}
+}
+
+// Record 记录审计事件
+func (s *AuditService) Record(action string, userID int64, username string, client dto.ClientInfo, detail string) {
+	entry := &models.AuditLog{
+		UserID:    userID,
+		Username:  truncate(username, 100),
+		Action:    action,
+		ClientIP:  truncate(client.ClientIP, 64),
+		UserAgent: truncate(client.UserAgent, 255),
+		Detail:    truncate(detail, 255),
+	}
+	if err := s.auditRepo.Create(entry); err != nil {
+		slog.Error("Failed to write audit log", "action", action, "user_id", userID, "error", err)
+	}
+}
+
+// RecordOperation 记录管理员操作
+func (s *AuditService) RecordOperation(action string, operatorID, userID int64, detail string) {
+	entry := &models.AuditLog{
+		UserID:     userID,
+		Action:     action,
+		Detail:     truncate(detail, 255),
+		OperatorID: operatorID,
+	}
+	if err := s.auditRepo.Create(entry); err != nil {
+		slog.Error("Failed to write audit log", "action", action, "user_id", userID, "error", err)
+	}
+}
+
+// List 分页查询审计日志 (管理员)
+func (s *AuditService) List(page, pageSize int, action string, userID int64) (*dto.AuditLogPageResult, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	logs, total, err := s.auditRepo.List(page, pageSize, action, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.AuditLogPageResult{List: logs, Total: total}, nil
+}
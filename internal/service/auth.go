@@ -6,7 +6,7 @@ import (
 
 	"github.com/FruitsAI/Orange/internal/dto"
 	"github.com/FruitsAI/Orange/internal/models"
-	"github.com/FruitsAI/Orange/internal/pkg/jwt"
+	"github.com/FruitsAI/Orange/internal/pkg/mailer"
 	"github.com/FruitsAI/Orange/internal/pkg/password"
 	"github.com/FruitsAI/Orange/internal/repository"
 )
@@ -16,8 +16,38 @@ import (
 //
 // 依赖:
 //   - UserRepository: 用户数据操作接口
+//   - RefreshTokenRepository: 刷新令牌的存储与吊销
+//   - SessionRepository: 登录会话的记录与吊销
+//   - RecoveryCodeRepository / LoginChallengeRepository: 两步验证恢复码与登录挑战
+//   - SettingService: 安全设置 (是否要求管理员启用两步验证)
+//   - LoginThrottleRepository / AuditService: 登录失败计数与锁定、安全审计
+//   - PasswordHistoryRepository: 密码历史 (配合密码策略禁止重复使用最近的密码)
+//   - Authenticator: 认证方式 (本地密码、LDAP)，由 AUTH_PROVIDERS 配置
+//   - UserIdentityRepository / OIDCLoginRepository: OIDC 单点登录的身份绑定与登录状态，由 OIDC_* 配置
+//   - PersonalTokenRepository: 个人访问令牌 (脚本与系统集成)
+//   - PasswordResetRepository / Mailer: 通过邮件找回密码，由 MAIL_* / SMTP_* 配置
+//   - RoleService: 校验用户角色，登录结果与当前用户信息附带角色权限
 type AuthService struct {
-	userRepo *repository.UserRepository
+	userRepo       *repository.UserRepository
+	tokenRepo      *repository.RefreshTokenRepository
+	sessionRepo    *repository.SessionRepository
+	recoveryRepo   *repository.RecoveryCodeRepository
+	challengeRepo  *repository.LoginChallengeRepository
+	settingService *SettingService
+	throttleRepo   *repository.LoginThrottleRepository
+	auditService   *AuditService
+	historyRepo    *repository.PasswordHistoryRepository
+	authenticators []Authenticator
+	identityRepo   *repository.UserIdentityRepository
+	oidcLoginRepo  *repository.OIDCLoginRepository
+	sso            *oidcSSO
+	patRepo        *repository.PersonalTokenRepository
+	resetRepo      *repository.PasswordResetRepository
+	mailer         mailer.Mailer
+	roleService    *RoleService
+	departmentRepo *repository.DepartmentRepository
+	positionRepo   *repository.PositionRepository
+	memberRepo     *repository.ProjectMemberRepository
 }
 
 // NewAuthService 创建认证服务实例
@@ -26,61 +56,115 @@ type AuthService struct {
 //   - *AuthService: 初始化的服务实例
 func NewAuthService() *AuthService {
 	return &AuthService{
-		userRepo: repository.NewUserRepository(),
+		userRepo:       repository.NewUserRepository(),
+		tokenRepo:      repository.NewRefreshTokenRepository(),
+		sessionRepo:    repository.NewSessionRepository(),
+		recoveryRepo:   repository.NewRecoveryCodeRepository(),
+		challengeRepo:  repository.NewLoginChallengeRepository(),
+		settingService: NewSettingService(),
+		throttleRepo:   repository.NewLoginThrottleRepository(),
+		auditService:   NewAuditService(),
+		historyRepo:    repository.NewPasswordHistoryRepository(),
+		authenticators: newAuthenticators(),
+		identityRepo:   repository.NewUserIdentityRepository(),
+		oidcLoginRepo:  repository.NewOIDCLoginRepository(),
+		sso:            newOIDCSSO(),
+		patRepo:        repository.NewPersonalTokenRepository(),
+		resetRepo:      repository.NewPasswordResetRepository(),
+		mailer:         newMailer(),
+		roleService:    NewRoleService(),
+		departmentRepo: repository.NewDepartmentRepository(),
+		positionRepo:   repository.NewPositionRepository(),
+		memberRepo:     repository.NewProjectMemberRepository(),
 	}
 }
 
 // Login 用户登录
-// 验证用户名和密码，成功后颁发 JWT Token 并更新最后登录时间。
+// 依次通过已启用的认证方式 (本地密码、LDAP) 验证用户名和密码，成功后创建登录会话、颁发访问令牌和刷新令牌，并更新最后登录时间。
+// 已启用两步验证的用户只返回挑战令牌，需调用 VerifyTwoFactorLogin 完成登录。
+// 账户与来源 IP 分别统计连续失败次数，失败后需等待的时间逐次翻倍，达到上限后临时锁定。
 //
 // 参数:
 //   - username: 用户名
 //   - pwd: 密码 (明文)
+//   - client: 客户端信息 (设备名称、IP、User-Agent)，记录到登录会话
 //
 // 返回:
-//   - *dto.LoginResult: 包含 Token 和用户信息的结构体
-//   - error: 认证失败（用户名/密码错误或账户被禁用）
-func (s *AuthService) Login(username, pwd string) (*dto.LoginResult, error) {
-	// 1. 查找用户
+//   - *dto.LoginResult: 包含令牌和用户信息的结构体 (或两步验证挑战)
+//   - error: 认证失败（用户名/密码错误、账户被禁用或锁定）
+func (s *AuthService) Login(username, pwd string, client dto.ClientInfo) (*dto.LoginResult, error) {
+	// 1. 查找用户 (目录账户首次登录前本地不存在)
 	user, err := s.userRepo.FindByCredential(username)
 	if err != nil {
-		return nil, errors.New("用户名或密码错误")
+		user = nil
 	}
 
-	// 2. 验证密码 (比对哈希)
-	if !password.CheckPassword(pwd, user.Password) {
-		return nil, errors.New("用户名或密码错误")
+	// 2. 检查来源 IP 与账户是否被锁定
+	if err := s.checkLoginThrottles(user, username, client); err != nil {
+		return nil, err
 	}
 
-	// 3. 检查账户状态
+	// 3. 验证密码
+	authenticated, err := s.authenticate(username, pwd)
+	if errors.Is(err, ErrInvalidCredentials) {
+		return nil, s.loginFailed(user, username, client)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// 账户的失败计数在全部验证通过后 (completeLogin) 才清空，两步验证码错误同样计入
+	return s.finishLogin(authenticated, client)
+}
+
+// finishLogin 身份验证通过后完成登录 (密码登录与单点登录共用)
+// 检查账户状态；已启用两步验证时先返回挑战令牌，否则创建登录会话并签发令牌。
+func (s *AuthService) finishLogin(user *models.User, client dto.ClientInfo) (*dto.LoginResult, error) {
+	// 1. 检查账户状态
 	if user.Status != 1 {
 		return nil, errors.New("账户已被禁用")
 	}
 
-	// 4. 生成 JWT Token
-	// Payload 包含: ID, Username, Role
-	token, err := jwt.GenerateToken(user.ID, user.Username, user.Role)
+	// 2. 已启用两步验证时先返回挑战令牌
+	if user.TwoFactorEnabled {
+		return s.newLoginChallenge(user.ID, client.DeviceName)
+	}
+
+	// 3. 创建登录会话，签发访问令牌 (JWT) 与刷新令牌
+	return s.completeLogin(user, client)
+}
+
+// completeLogin 认证通过后创建登录会话、签发令牌、清空账户的登录失败计数并更新最后登录时间
+func (s *AuthService) completeLogin(user *models.User, client dto.ClientInfo) (*dto.LoginResult, error) {
+	_ = s.throttleRepo.Delete(throttleUser, loginAccountKey(user, ""))
+
+	session, err := s.newSession(user.ID, client)
 	if err != nil {
-		return nil, errors.New("生成Token失败")
+		return nil, err
 	}
+	result, err := s.issueTokens(user, session)
+	if err != nil {
+		return nil, err
+	}
+	result.TwoFactorSetupRequired = !user.TwoFactorEnabled && s.twoFactorRequired(user.Role)
+	result.PasswordChangeRequired = s.passwordExpired(user)
 
-	// 5. 异步更新最后登录时间 (非关键路径，暂同步执行，可优化)
+	// 更新最后登录时间 (非关键路径，暂同步执行，可优化)
 	now := time.Now()
 	s.userRepo.UpdateFields(user.ID, map[string]interface{}{
 		"last_login_time": now,
 	})
 
-	return &dto.LoginResult{Token: token, User: user}, nil
+	return result, nil
 }
 
 // Register 用户注册
-// 创建新用户账号，检查用户名和邮箱唯一性，并对密码进行加密存储。
+// 创建新用户账号，检查用户名和邮箱唯一性，按密码策略校验密码后加密存储。
 //
 // 参数:
 //   - input: 注册请求DTO
 //
 // 返回:
-//   - error: 注册失败（如信息已存在或加密失败）
+//   - error: 注册失败（如信息已存在、密码不符合策略或加密失败），密码不符合策略时为 *password.PolicyError
 func (s *AuthService) Register(input dto.RegisterRequest) error {
 	// 1. 唯一性检查
 	if s.userRepo.ExistsByUsername(input.Username) {
@@ -90,34 +174,31 @@ func (s *AuthService) Register(input dto.RegisterRequest) error {
 		return errors.New("邮箱已被注册")
 	}
 
-	// 2. 密码加密 (Bcrypt)
-	hashedPassword, err := password.HashPassword(input.Password)
-	if err != nil {
-		return errors.New("密码加密失败")
-	}
-
-	// 3. 构建用户实体
+	// 2. 构建用户实体
 	user := &models.User{
 		Username: input.Username,
 		Name:     input.Name,
 		Email:    input.Email,
 		Phone:    input.Phone,
-		Password: hashedPassword,
 		Role:     "user", // 默认为普通用户
 		Status:   1,      // 默认启用
 	}
 
-	// 4. 保存至数据库
-	return s.userRepo.Create(user)
+	// 3. 校验并加密密码 (Bcrypt)，保存至数据库
+	return s.createUser(user, input.Password)
 }
 
 // GetCurrentUser 获取当前登录用户详情
-func (s *AuthService) GetCurrentUser(userID int64) (*models.User, error) {
-	return s.userRepo.FindByID(userID)
+func (s *AuthService) GetCurrentUser(userID int64) (*dto.CurrentUser, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.CurrentUser{User: user, Permissions: s.roleService.PermissionCodes(user.Role)}, nil
 }
 
 // UpdateProfile 更新个人资料
-// 支持部分更新（Name, Email, Phone, Department, Position）。
+// 支持部分更新（Name, Email, Phone），部门与职位由管理员分配。
 //
 // 参数:
 //   - userID: 用户ID
@@ -126,7 +207,7 @@ func (s *AuthService) GetCurrentUser(userID int64) (*models.User, error) {
 // 返回:
 //   - *models.User: 更新后的用户实体
 //   - error: 数据库错误
-func (s *AuthService) UpdateProfile(userID int64, name, email, phone, department, position string) (*models.User, error) {
+func (s *AuthService) UpdateProfile(userID int64, name, email, phone string) (*models.User, error) {
 	updates := map[string]interface{}{}
 
 	if name != "" {
@@ -138,12 +219,6 @@ func (s *AuthService) UpdateProfile(userID int64, name, email, phone, department
 	if phone != "" {
 		updates["phone"] = phone
 	}
-	if department != "" {
-		updates["department"] = department
-	}
-	if position != "" {
-		updates["position"] = position
-	}
 
 	if len(updates) > 0 {
 		if err := s.userRepo.UpdateFields(userID, updates); err != nil {
@@ -155,36 +230,56 @@ func (s *AuthService) UpdateProfile(userID int64, name, email, phone, department
 }
 
 // ChangePassword 修改密码
-// 验证旧密码正确性后，更新为新密码（加密存储）。
+// 验证旧密码正确性后，按密码策略校验新密码并更新（加密存储）。
+// 修改成功后吊销该用户的全部登录，并为当前客户端重新签发令牌。
 //
 // 参数:
 //   - userID: 用户ID
 //   - oldPassword: 旧密码
 //   - newPassword: 新密码
+//   - client: 当前客户端信息，用于创建新的登录会话
 //
 // 返回:
+//   - *dto.LoginResult: 当前客户端的新令牌
 //   - error: 验证失败或更新错误
-func (s *AuthService) ChangePassword(userID int64, oldPassword, newPassword string) error {
+func (s *AuthService) ChangePassword(userID int64, oldPassword, newPassword string, client dto.ClientInfo) (*dto.LoginResult, error) {
 	user, err := s.userRepo.FindByID(userID)
 	if err != nil {
-		return errors.New("用户不存在")
+		return nil, errors.New("用户不存在")
+	}
+	if !isLocalUser(user) {
+		return nil, ErrExternalAccount
 	}
 
 	// 1. 验证旧密码
 	if !password.CheckPassword(oldPassword, user.Password) {
-		return errors.New("原密码错误")
+		return nil, errors.New("原密码错误")
 	}
 
-	// 2. 加密新密码
-	hashedPassword, err := password.HashPassword(newPassword)
+	// 2. 按密码策略校验并加密新密码
+	hashedPassword, err := s.hashNewPassword(user, newPassword)
 	if err != nil {
-		return errors.New("密码加密失败")
+		return nil, err
 	}
 
 	// 3. 更新数据库
-	return s.userRepo.UpdateFields(userID, map[string]interface{}{
-		"password": hashedPassword,
-	})
+	if err := s.savePassword(userID, hashedPassword); err != nil {
+		return nil, err
+	}
+
+	// 4. 吊销其他登录，为当前客户端重新签发令牌
+	if err := s.RevokeAll(userID); err != nil {
+		return nil, err
+	}
+	user, err = s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	session, err := s.newSession(userID, client)
+	if err != nil {
+		return nil, err
+	}
+	return s.issueTokens(user, session)
 }
 
 // ListUsers 获取用户列表 (管理员)
@@ -208,27 +303,35 @@ func (s *AuthService) CreateUser(input dto.CreateUserRequest) error {
 		return errors.New("邮箱已被注册")
 	}
 
-	hashedPassword, err := password.HashPassword(input.Password)
+	role := input.Role
+	if role == "" {
+		role = RoleUser
+	} else if !s.roleService.Exists(role) {
+		return ErrRoleNotFound
+	}
+	department, err := departmentName(s.departmentRepo, input.DepartmentID)
 	if err != nil {
-		return errors.New("密码加密失败")
+		return err
 	}
-
-	role := input.Role
-	if role != "admin" {
-		role = "user"
+	position, err := positionName(s.positionRepo, input.PositionID)
+	if err != nil {
+		return err
 	}
 
 	user := &models.User{
-		Username: input.Username,
-		Name:     input.Name,
-		Email:    input.Email,
-		Phone:    input.Phone,
-		Password: hashedPassword,
-		Role:     role,
-		Status:   1,
+		Username:     input.Username,
+		Name:         input.Name,
+		Email:        input.Email,
+		Phone:        input.Phone,
+		Role:         role,
+		Department:   department,
+		DepartmentID: input.DepartmentID,
+		Position:     position,
+		PositionID:   input.PositionID,
+		Status:       1,
 	}
 
-	return s.userRepo.Create(user)
+	return s.createUser(user, input.Password)
 }
 
 // UpdateUser 更新用户 (管理员)
@@ -243,13 +346,26 @@ func (s *AuthService) UpdateUser(id int64, input dto.UpdateUserRequest) error {
 	if input.Phone != "" {
 		updates["phone"] = input.Phone
 	}
-	if input.Department != "" {
-		updates["department"] = input.Department
+	if input.DepartmentID != nil {
+		name, err := departmentName(s.departmentRepo, *input.DepartmentID)
+		if err != nil {
+			return err
+		}
+		updates["department"] = name
+		updates["department_id"] = *input.DepartmentID
 	}
-	if input.Position != "" {
-		updates["position"] = input.Position
+	if input.PositionID != nil {
+		name, err := positionName(s.positionRepo, *input.PositionID)
+		if err != nil {
+			return err
+		}
+		updates["position"] = name
+		updates["position_id"] = *input.PositionID
 	}
 	if input.Role != "" {
+		if !s.roleService.Exists(input.Role) {
+			return ErrRoleNotFound
+		}
 		updates["role"] = input.Role
 	}
 	// Status always check (0 or 1)
@@ -258,23 +374,61 @@ func (s *AuthService) UpdateUser(id int64, input dto.UpdateUserRequest) error {
 	// DTO status is int.
 	updates["status"] = input.Status
 
-	return s.userRepo.UpdateFields(id, updates)
+	if err := s.userRepo.UpdateFields(id, updates); err != nil {
+		return err
+	}
+
+	// 禁用账户时吊销其全部登录；角色变更时令已签发的访问令牌失效，
+	// 刷新令牌仍可用，客户端刷新后即获得新角色。
+	if input.Status != 1 {
+		return s.RevokeAll(id)
+	}
+	if input.Role != "" {
+		return s.userRepo.IncrementTokenVersion(id)
+	}
+	return nil
 }
 
 // DeleteUser 删除用户 (管理员)
 func (s *AuthService) DeleteUser(id int64) error {
 	// Optional: Check if admin is deleting themselves?
 	// Handler layer might handle "cannot delete self" logic or here.
+	if err := s.RevokeAll(id); err != nil {
+		return err
+	}
+	if err := s.identityRepo.DeleteByUser(id); err != nil {
+		return err
+	}
+	if err := s.patRepo.DeleteByUser(id); err != nil {
+		return err
+	}
+	if err := s.resetRepo.DeleteByUser(id); err != nil {
+		return err
+	}
+	if err := s.departmentRepo.ClearManager(id); err != nil {
+		return err
+	}
+	if err := s.memberRepo.DeleteByUser(id); err != nil {
+		return err
+	}
 	return s.userRepo.Delete(id)
 }
 
 // ResetPassword 重置用户密码 (管理员)
 func (s *AuthService) ResetPassword(id int64, newPassword string) error {
-	hashedPassword, err := password.HashPassword(newPassword)
+	user, err := s.userRepo.FindByID(id)
 	if err != nil {
-		return errors.New("密码加密失败")
+		return errors.New("用户不存在")
 	}
-	return s.userRepo.UpdateFields(id, map[string]interface{}{
-		"password": hashedPassword,
-	})
+	if !isLocalUser(user) {
+		return ErrExternalAccount
+	}
+	hashedPassword, err := s.hashNewPassword(user, newPassword)
+	if err != nil {
+		return err
+	}
+	if err := s.savePassword(id, hashedPassword); err != nil {
+		return err
+	}
+	return s.RevokeAll(id)
 }
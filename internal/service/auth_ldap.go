@@ -0,0 +1,280 @@
+package service
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/config"
+	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/repository"
+	"github.com/go-ldap/ldap/v3"
+	"gorm.io/gorm"
+)
+
+// LDAPConfig LDAP 认证配置
+type LDAPConfig struct {
+	URL                string
+	StartTLS           bool
+	InsecureSkipVerify bool
+	BindDN             string // 查找用户的服务账号，为空表示匿名查找
+	BindPassword       string
+	BaseDN             string
+	UserFilter         string // {username} 替换为转义后的登录名
+	AttrUsername       string
+	AttrName           string
+	AttrEmail          string
+	AttrPhone          string
+	AttrGroups         string
+	GroupMappings      []GroupMapping // 按顺序匹配
+	Timeout            time.Duration
+}
+
+// LDAPConfigFromEnv 从全局配置读取 LDAP 认证配置
+func LDAPConfigFromEnv() (LDAPConfig, error) {
+	cfg := config.AppConfig
+	ldapCfg := LDAPConfig{
+		URL:                cfg.LDAPURL,
+		StartTLS:           cfg.LDAPStartTLS,
+		InsecureSkipVerify: cfg.LDAPInsecureSkipVerify,
+		BindDN:             cfg.LDAPBindDN,
+		BindPassword:       cfg.LDAPBindPassword,
+		BaseDN:             cfg.LDAPBaseDN,
+		UserFilter:         cfg.LDAPUserFilter,
+		AttrUsername:       cfg.LDAPAttrUsername,
+		AttrName:           cfg.LDAPAttrName,
+		AttrEmail:          cfg.LDAPAttrEmail,
+		AttrPhone:          cfg.LDAPAttrPhone,
+		AttrGroups:         cfg.LDAPAttrGroups,
+		Timeout:            time.Duration(cfg.LDAPTimeout) * time.Second,
+	}
+	if ldapCfg.URL == "" || ldapCfg.BaseDN == "" {
+		return ldapCfg, errors.New("未配置 LDAP_URL 或 LDAP_BASE_DN")
+	}
+	if !strings.Contains(ldapCfg.UserFilter, "{username}") {
+		return ldapCfg, errors.New("LDAP_USER_FILTER 必须包含 {username}")
+	}
+	if cfg.LDAPGroupMapping != "" {
+		if err := json.Unmarshal([]byte(cfg.LDAPGroupMapping), &ldapCfg.GroupMappings); err != nil {
+			return ldapCfg, fmt.Errorf("LDAP_GROUP_MAPPING 格式错误: %w", err)
+		}
+		if err := checkGroupMappings(NewRoleService(), ldapCfg.GroupMappings); err != nil {
+			return ldapCfg, fmt.Errorf("LDAP_GROUP_MAPPING 无效: %w", err)
+		}
+	}
+	return ldapCfg, nil
+}
+
+// LDAPConn LDAP 连接 (*ldap.Conn 的子集，便于在测试中替换为进程内的目录服务桩)
+type LDAPConn interface {
+	Bind(username, password string) error
+	Search(request *ldap.SearchRequest) (*ldap.SearchResult, error)
+	Close() error
+}
+
+// LDAPDialer 建立 LDAP 连接
+type LDAPDialer func(cfg LDAPConfig) (LDAPConn, error)
+
+// LDAPAuthenticator LDAP / Active Directory 认证
+// 先以服务账号查找用户条目，再以用户 DN 和密码绑定校验密码。
+// 校验通过后按目录信息创建或更新本地用户 (AuthSource=ldap)，角色、部门、职位由所属组映射得到。
+// 已存在同名本地密码账户时不接管，仍由本地密码认证；同名的单点登录账户同样不接管。
+type LDAPAuthenticator struct {
+	cfg            LDAPConfig
+	dial           LDAPDialer
+	userRepo       *repository.UserRepository
+	departmentRepo *repository.DepartmentRepository
+	positionRepo   *repository.PositionRepository
+	roleService    *RoleService
+}
+
+// NewLDAPAuthenticator 创建 LDAP 认证，dial 为 nil 时按配置连接目录服务
+func NewLDAPAuthenticator(cfg LDAPConfig, dial LDAPDialer) *LDAPAuthenticator {
+	if dial == nil {
+		dial = dialLDAP
+	}
+	return &LDAPAuthenticator{
+		cfg:            cfg,
+		dial:           dial,
+		userRepo:       repository.NewUserRepository(),
+		departmentRepo: repository.NewDepartmentRepository(),
+		positionRepo:   repository.NewPositionRepository(),
+		roleService:    NewRoleService(),
+	}
+}
+
+// Name 认证方式名称
+func (a *LDAPAuthenticator) Name() string {
+	return AuthSourceLDAP
+}
+
+// Authenticate 通过目录服务校验密码，并同步本地用户
+func (a *LDAPAuthenticator) Authenticate(credential, pwd string) (*models.User, error) {
+	credential = strings.TrimSpace(credential)
+	// 空密码在 LDAP 中会被视为匿名绑定而 "成功"，必须直接拒绝
+	if credential == "" || pwd == "" {
+		return nil, ErrInvalidCredentials
+	}
+	if user, err := a.userRepo.FindByCredential(credential); err == nil && isLocalUser(user) {
+		return nil, ErrInvalidCredentials
+	}
+
+	conn, err := a.dial(a.cfg)
+	if err != nil {
+		return nil, fmt.Errorf("连接 LDAP 服务失败: %w", err)
+	}
+	defer conn.Close()
+
+	entry, err := a.findEntry(conn, credential)
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.Bind(entry.DN, pwd); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("LDAP 绑定失败: %w", err)
+	}
+
+	return a.provision(entry, credential)
+}
+
+// findEntry 以服务账号查找唯一的用户条目
+func (a *LDAPAuthenticator) findEntry(conn LDAPConn, credential string) (*ldap.Entry, error) {
+	if a.cfg.BindDN != "" {
+		if err := conn.Bind(a.cfg.BindDN, a.cfg.BindPassword); err != nil {
+			return nil, fmt.Errorf("LDAP 服务账号绑定失败: %w", err)
+		}
+	}
+
+	filter := strings.ReplaceAll(a.cfg.UserFilter, "{username}", ldap.EscapeFilter(credential))
+	attributes := []string{"dn"}
+	for _, attr := range []string{a.cfg.AttrUsername, a.cfg.AttrName, a.cfg.AttrEmail, a.cfg.AttrPhone, a.cfg.AttrGroups} {
+		if attr != "" {
+			attributes = append(attributes, attr)
+		}
+	}
+	result, err := conn.Search(ldap.NewSearchRequest(
+		a.cfg.BaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases,
+		2, int(a.cfg.Timeout/time.Second), false,
+		filter, attributes, nil,
+	))
+	if err != nil && !ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded) {
+		return nil, fmt.Errorf("LDAP 查找用户失败: %w", err)
+	}
+	// 未找到或匹配到多个条目 (过滤器不唯一) 都不允许登录
+	if result == nil || len(result.Entries) != 1 {
+		return nil, ErrInvalidCredentials
+	}
+	return result.Entries[0], nil
+}
+
+// provision 按目录条目创建或更新本地用户
+func (a *LDAPAuthenticator) provision(entry *ldap.Entry, credential string) (*models.User, error) {
+	username := entry.GetAttributeValue(a.cfg.AttrUsername)
+	if username == "" {
+		username = credential
+	}
+	name := entry.GetAttributeValue(a.cfg.AttrName)
+	if name == "" {
+		name = username
+	}
+	role, department, position, err := mapGroups(a.roleService, a.cfg.GroupMappings, entry.GetAttributeValues(a.cfg.AttrGroups))
+	if err != nil {
+		return nil, err
+	}
+	department, position = truncate(department, 50), truncate(position, 50)
+
+	user, err := a.userRepo.FindByUsername(username)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		now := time.Now()
+		user = &models.User{
+			Username:           truncate(username, 50),
+			Name:               truncate(name, 50),
+			Email:              truncate(entry.GetAttributeValue(a.cfg.AttrEmail), 100),
+			Phone:              truncate(entry.GetAttributeValue(a.cfg.AttrPhone), 20),
+			Role:               role,
+			Department:         department,
+			Position:           position,
+			Status:             1,
+			AuthSource:         AuthSourceLDAP,
+			PasswordChangeTime: &now,
+		}
+		if user.DepartmentID, err = a.departmentRepo.EnsureByName(department); err != nil {
+			return nil, err
+		}
+		if user.PositionID, err = a.positionRepo.EnsureByName(position); err != nil {
+			return nil, err
+		}
+		if err := a.userRepo.Create(user); err != nil {
+			return nil, fmt.Errorf("创建目录用户失败: %w", err)
+		}
+		return user, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// 同名的本地或单点登录账户不由目录接管
+	if user.AuthSource != AuthSourceLDAP {
+		return nil, ErrInvalidCredentials
+	}
+
+	updates := map[string]interface{}{
+		"name": truncate(name, 50),
+		"role": role,
+	}
+	if email := entry.GetAttributeValue(a.cfg.AttrEmail); email != "" {
+		updates["email"] = truncate(email, 100)
+	}
+	if phone := entry.GetAttributeValue(a.cfg.AttrPhone); phone != "" {
+		updates["phone"] = truncate(phone, 20)
+	}
+	if department != "" {
+		if err := departmentUpdates(a.departmentRepo, updates, department); err != nil {
+			return nil, err
+		}
+	}
+	if position != "" {
+		if err := positionUpdates(a.positionRepo, updates, position); err != nil {
+			return nil, err
+		}
+	}
+	if err := a.userRepo.UpdateFields(user.ID, updates); err != nil {
+		return nil, err
+	}
+	// 目录中的角色变更后，已签发的访问令牌随之失效
+	if user.Role != role {
+		if err := a.userRepo.IncrementTokenVersion(user.ID); err != nil {
+			return nil, err
+		}
+	}
+	return a.userRepo.FindByID(user.ID)
+}
+
+// dialLDAP 按配置连接目录服务 (支持 ldaps:// 与 StartTLS)
+func dialLDAP(cfg LDAPConfig) (LDAPConn, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: cfg.InsecureSkipVerify}
+	if u, err := url.Parse(cfg.URL); err == nil {
+		tlsConfig.ServerName = u.Hostname()
+	}
+	conn, err := ldap.DialURL(cfg.URL,
+		ldap.DialWithDialer(&net.Dialer{Timeout: cfg.Timeout}),
+		ldap.DialWithTLSConfig(tlsConfig),
+	)
+	if err != nil {
+		return nil, err
+	}
+	conn.SetTimeout(cfg.Timeout)
+	if cfg.StartTLS {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
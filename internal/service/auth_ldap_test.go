@@ -0,0 +1,490 @@
+package service
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/config"
+	"github.com/FruitsAI/Orange/internal/repository"
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+)
+
+const (
+	testBindDN       = "cn=orange,ou=services,dc=example,dc=com"
+	testBindPassword = "service-secret"
+)
+
+// stubDirectory 进程内的目录服务桩
+// Search 按过滤器原文返回条目，Bind 按 DN 校验密码。
+type stubDirectory struct {
+	entries   map[string][]*ldap.Entry // 过滤器 -> 条目
+	passwords map[string]string        // DN -> 密码
+	filters   []string                 // 收到的查找过滤器
+	binds     []string                 // 绑定过的 DN
+	dials     int
+}
+
+func newStubDirectory() *stubDirectory {
+	return &stubDirectory{
+		entries:   map[string][]*ldap.Entry{},
+		passwords: map[string]string{testBindDN: testBindPassword},
+	}
+}
+
+// addUser 添加用户条目，可通过 (uid=username) 查找
+func (d *stubDirectory) addUser(username, pwd string, attributes map[string][]string) *ldap.Entry {
+	dn := "uid=" + username + ",ou=people,dc=example,dc=com"
+	attrs := map[string][]string{"uid": {username}}
+	for k, v := range attributes {
+		attrs[k] = v
+	}
+	entry := ldap.NewEntry(dn, attrs)
+	filter := "(uid=" + ldap.EscapeFilter(username) + ")"
+	d.entries[filter] = append(d.entries[filter], entry)
+	d.passwords[dn] = pwd
+	return entry
+}
+
+func (d *stubDirectory) dial(LDAPConfig) (LDAPConn, error) {
+	d.dials++
+	return &stubLDAPConn{dir: d}, nil
+}
+
+type stubLDAPConn struct {
+	dir *stubDirectory
+}
+
+func (c *stubLDAPConn) Bind(username, password string) error {
+	c.dir.binds = append(c.dir.binds, username)
+	if expected, ok := c.dir.passwords[username]; ok && password != "" && password == expected {
+		return nil
+	}
+	return ldap.NewError(ldap.LDAPResultInvalidCredentials, errors.New("invalid credentials"))
+}
+
+func (c *stubLDAPConn) Search(request *ldap.SearchRequest) (*ldap.SearchResult, error) {
+	c.dir.filters = append(c.dir.filters, request.Filter)
+	return &ldap.SearchResult{Entries: c.dir.entries[request.Filter]}, nil
+}
+
+func (c *stubLDAPConn) Close() error {
+	return nil
+}
+
+// testLDAPConfig 返回使用服务账号查找 (uid={username}) 的配置
+func testLDAPConfig(mappings ...GroupMapping) LDAPConfig {
+	return LDAPConfig{
+		URL:           "ldap://directory.test",
+		BindDN:        testBindDN,
+		BindPassword:  testBindPassword,
+		BaseDN:        "ou=people,dc=example,dc=com",
+		UserFilter:    "(uid={username})",
+		AttrUsername:  "uid",
+		AttrName:      "cn",
+		AttrEmail:     "mail",
+		AttrPhone:     "telephoneNumber",
+		AttrGroups:    "memberOf",
+		GroupMappings: mappings,
+		Timeout:       5 * time.Second,
+	}
+}
+
+func TestLDAPAuthenticateSuccess(t *testing.T) {
+	dir := newStubDirectory()
+	entry := dir.addUser("ldap_alice", "alice-pass", map[string][]string{
+		"cn":   {"Alice"},
+		"mail": {"alice@example.com"},
+	})
+
+	user, err := NewLDAPAuthenticator(testLDAPConfig(), dir.dial).Authenticate("ldap_alice", "alice-pass")
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if user.ID == 0 || user.Username != "ldap_alice" || user.Name != "Alice" || user.Email != "alice@example.com" {
+		t.Errorf("目录用户资料不正确: %+v", user)
+	}
+	if user.AuthSource != AuthSourceLDAP || user.Role != RoleUser || user.Status != 1 {
+		t.Errorf("auth_source=%q role=%q status=%d", user.AuthSource, user.Role, user.Status)
+	}
+	// 先以服务账号查找，再以用户 DN 校验密码
+	if len(dir.binds) != 2 || dir.binds[0] != testBindDN || dir.binds[1] != entry.DN {
+		t.Errorf("绑定顺序不正确: %v", dir.binds)
+	}
+
+	// 再次登录复用同一个本地用户，并同步目录中的资料
+	entry.Attributes = ldap.NewEntry(entry.DN, map[string][]string{"uid": {"ldap_alice"}, "cn": {"Alice Liu"}}).Attributes
+	again, err := NewLDAPAuthenticator(testLDAPConfig(), dir.dial).Authenticate("ldap_alice", "alice-pass")
+	if err != nil {
+		t.Fatalf("再次登录: %v", err)
+	}
+	if again.ID != user.ID || again.Name != "Alice Liu" {
+		t.Errorf("再次登录 id=%d name=%q，期望 id=%d name=Alice Liu", again.ID, again.Name, user.ID)
+	}
+}
+
+func TestLDAPAuthenticateWrongPassword(t *testing.T) {
+	dir := newStubDirectory()
+	dir.addUser("ldap_bob", "bob-pass", nil)
+	auth := NewLDAPAuthenticator(testLDAPConfig(), dir.dial)
+
+	if _, err := auth.Authenticate("ldap_bob", "wrong"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("错误密码: 期望 ErrInvalidCredentials，实际 %v", err)
+	}
+	if _, err := auth.Authenticate("ldap_nobody", "whatever"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("不存在的用户: 期望 ErrInvalidCredentials，实际 %v", err)
+	}
+	if repository.NewUserRepository().ExistsByUsername("ldap_bob") {
+		t.Error("认证失败不应创建本地用户")
+	}
+}
+
+func TestLDAPAuthenticateRejectsEmptyPassword(t *testing.T) {
+	dir := newStubDirectory()
+	dir.addUser("ldap_carol", "carol-pass", nil)
+	// 即使目录服务允许匿名绑定，空密码也不能登录
+	dir.passwords["uid=ldap_carol,ou=people,dc=example,dc=com"] = ""
+
+	if _, err := NewLDAPAuthenticator(testLDAPConfig(), dir.dial).Authenticate("ldap_carol", ""); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("期望 ErrInvalidCredentials，实际 %v", err)
+	}
+	if dir.dials != 0 {
+		t.Errorf("空密码不应连接目录服务，实际连接 %d 次", dir.dials)
+	}
+}
+
+func TestLDAPAuthenticateEscapesFilter(t *testing.T) {
+	dir := newStubDirectory()
+	// 通配符过滤器会匹配任意用户，必须转义后原样查找
+	dir.entries["(uid=*)"] = []*ldap.Entry{ldap.NewEntry("uid=victim,ou=people,dc=example,dc=com", map[string][]string{"uid": {"victim"}})}
+	dir.passwords["uid=victim,ou=people,dc=example,dc=com"] = "pwd"
+
+	credential := `a*)(uid=*)\`
+	if _, err := NewLDAPAuthenticator(testLDAPConfig(), dir.dial).Authenticate(credential, "pwd"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("期望 ErrInvalidCredentials，实际 %v", err)
+	}
+	if len(dir.filters) != 1 {
+		t.Fatalf("期望查找 1 次，实际 %v", dir.filters)
+	}
+	if want := `(uid=a\2a\29\28uid=\2a\29\5c)`; dir.filters[0] != want {
+		t.Errorf("过滤器 = %s，期望 %s", dir.filters[0], want)
+	}
+}
+
+func TestLDAPGroupMapping(t *testing.T) {
+	mappings := []GroupMapping{
+		{Group: "finance", Department: "财务部", Position: "会计"},
+		{Group: "cn=orange-admins,ou=groups,dc=example,dc=com", Role: RoleAdmin},
+	}
+	dir := newStubDirectory()
+	dir.addUser("ldap_dave", "dave-pass", map[string][]string{
+		"memberOf": {"cn=Finance,ou=groups,dc=example,dc=com", "CN=Orange-Admins,OU=Groups,DC=example,DC=com"},
+	})
+	dir.addUser("ldap_erin", "erin-pass", map[string][]string{
+		"memberOf": {"cn=finance,ou=groups,dc=example,dc=com"},
+	})
+	auth := NewLDAPAuthenticator(testLDAPConfig(mappings...), dir.dial)
+
+	dave, err := auth.Authenticate("ldap_dave", "dave-pass")
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if dave.Role != RoleAdmin || dave.Department != "财务部" || dave.Position != "会计" || dave.DepartmentID == 0 || dave.PositionID == 0 {
+		t.Errorf("组映射结果不正确: role=%q department=%q(%d) position=%q(%d)",
+			dave.Role, dave.Department, dave.DepartmentID, dave.Position, dave.PositionID)
+	}
+
+	erin, err := auth.Authenticate("ldap_erin", "erin-pass")
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if erin.Role != RoleUser || erin.DepartmentID != dave.DepartmentID {
+		t.Errorf("未匹配角色的用户应为普通用户: role=%q department_id=%d", erin.Role, erin.DepartmentID)
+	}
+
+	// 移出管理员组后再次登录降为普通用户，已签发的令牌失效
+	dir.entries["(uid=ldap_dave)"][0] = ldap.NewEntry(dir.entries["(uid=ldap_dave)"][0].DN, map[string][]string{"uid": {"ldap_dave"}})
+	demoted, err := auth.Authenticate("ldap_dave", "dave-pass")
+	if err != nil {
+		t.Fatalf("再次登录: %v", err)
+	}
+	if demoted.Role != RoleUser || demoted.TokenVersion != dave.TokenVersion+1 {
+		t.Errorf("role=%q token_version=%d，期望 user 且令牌版本递增", demoted.Role, demoted.TokenVersion)
+	}
+}
+
+func TestLDAPGroupMappingUnknownRole(t *testing.T) {
+	mappings := []GroupMapping{{Group: "auditors", Role: "auditor_not_created"}}
+	if err := checkGroupMappings(NewRoleService(), mappings); err == nil {
+		t.Error("checkGroupMappings: 不存在的角色应返回错误")
+	}
+	if err := checkGroupMappings(NewRoleService(), []GroupMapping{{Group: "admins", Role: RoleAdmin}, {Group: "finance"}}); err != nil {
+		t.Errorf("checkGroupMappings: %v", err)
+	}
+
+	// 加载配置时发现不存在的角色即报错，不启用 LDAP 认证
+	saved := *config.AppConfig
+	defer func() { *config.AppConfig = saved }()
+	config.AppConfig.LDAPURL = "ldap://directory.test"
+	config.AppConfig.LDAPBaseDN = "ou=people,dc=example,dc=com"
+	config.AppConfig.LDAPUserFilter = "(uid={username})"
+	config.AppConfig.LDAPGroupMapping = `[{"group":"auditors","role":"auditor_not_created"}]`
+	if _, err := LDAPConfigFromEnv(); err == nil {
+		t.Error("LDAPConfigFromEnv: 不存在的角色应返回错误")
+	}
+
+	dir := newStubDirectory()
+	dir.addUser("ldap_frank", "frank-pass", map[string][]string{"memberOf": {"cn=auditors,ou=groups,dc=example,dc=com"}})
+	_, err := NewLDAPAuthenticator(testLDAPConfig(mappings...), dir.dial).Authenticate("ldap_frank", "frank-pass")
+	if err == nil || errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("映射到不存在的角色应返回配置错误，实际 %v", err)
+	}
+	if repository.NewUserRepository().ExistsByUsername("ldap_frank") {
+		t.Error("不应以不存在的角色创建用户")
+	}
+}
+
+// ldapTestServer 进程内的 LDAP 服务 (仅实现简单绑定与查找)
+// 认证器通过真实的客户端 (dialLDAP) 连接，绑定、过滤器与查找结果都经过协议编码。
+// 等值过滤器按值精确匹配，存在与子串过滤器 (通配符) 匹配所有含该属性的条目。
+type ldapTestServer struct {
+	listener  net.Listener
+	entries   []*ldap.Entry
+	passwords map[string]string // DN -> 密码
+
+	mu      sync.Mutex
+	filters []string // 收到的查找过滤器 (由 BER 还原的字符串形式)
+	binds   []string // 绑定成功的 DN
+}
+
+func newLDAPTestServer(t *testing.T) *ldapTestServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &ldapTestServer{listener: listener, passwords: map[string]string{testBindDN: testBindPassword}}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+// addUser 添加用户条目
+func (s *ldapTestServer) addUser(username, pwd string, attributes map[string][]string) {
+	dn := "uid=" + username + ",ou=people,dc=example,dc=com"
+	attrs := map[string][]string{"uid": {username}}
+	for k, v := range attributes {
+		attrs[k] = v
+	}
+	s.entries = append(s.entries, ldap.NewEntry(dn, attrs))
+	s.passwords[dn] = pwd
+}
+
+// config 返回连接到该服务的配置 (使用真实的客户端)
+func (s *ldapTestServer) config(mappings ...GroupMapping) LDAPConfig {
+	cfg := testLDAPConfig(mappings...)
+	cfg.URL = "ldap://" + s.listener.Addr().String()
+	return cfg
+}
+
+func (s *ldapTestServer) serve(conn net.Conn) {
+	defer conn.Close()
+	for {
+		packet, err := ber.ReadPacket(conn)
+		if err != nil || len(packet.Children) < 2 {
+			return
+		}
+		id := packet.Children[0].Value
+		op := packet.Children[1]
+		var responses []*ber.Packet
+		switch op.Tag {
+		case ldap.ApplicationBindRequest:
+			responses = append(responses, ldapResult(ldap.ApplicationBindResponse, s.bind(op)))
+		case ldap.ApplicationSearchRequest:
+			entries, code := s.search(op)
+			for _, entry := range entries {
+				responses = append(responses, ldapEntry(entry))
+			}
+			responses = append(responses, ldapResult(ldap.ApplicationSearchResultDone, code))
+		default: // 解除绑定等请求: 关闭连接
+			return
+		}
+		for _, response := range responses {
+			message := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+			message.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, id, "MessageID"))
+			message.AppendChild(response)
+			if _, err := conn.Write(message.Bytes()); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// bind 简单绑定: 按 DN 校验密码 (空密码一律拒绝)
+func (s *ldapTestServer) bind(op *ber.Packet) uint16 {
+	dn := op.Children[1].Data.String()
+	pwd := op.Children[2].Data.String()
+	if expected, ok := s.passwords[dn]; !ok || pwd == "" || pwd != expected {
+		return ldap.LDAPResultInvalidCredentials
+	}
+	s.mu.Lock()
+	s.binds = append(s.binds, dn)
+	s.mu.Unlock()
+	return ldap.LDAPResultSuccess
+}
+
+func (s *ldapTestServer) search(op *ber.Packet) ([]*ldap.Entry, uint16) {
+	filter := op.Children[6]
+	decompiled, err := ldap.DecompileFilter(filter)
+	if err != nil {
+		return nil, ldap.LDAPResultProtocolError
+	}
+	s.mu.Lock()
+	s.filters = append(s.filters, decompiled)
+	s.mu.Unlock()
+
+	var matched []*ldap.Entry
+	for _, entry := range s.entries {
+		if matchLDAPFilter(filter, entry) {
+			matched = append(matched, entry)
+		}
+	}
+	return matched, ldap.LDAPResultSuccess
+}
+
+// matchLDAPFilter 判断条目是否匹配过滤器
+func matchLDAPFilter(filter *ber.Packet, entry *ldap.Entry) bool {
+	switch filter.Tag {
+	case ldap.FilterAnd:
+		for _, child := range filter.Children {
+			if !matchLDAPFilter(child, entry) {
+				return false
+			}
+		}
+		return true
+	case ldap.FilterOr:
+		for _, child := range filter.Children {
+			if matchLDAPFilter(child, entry) {
+				return true
+			}
+		}
+		return false
+	case ldap.FilterEqualityMatch:
+		value := filter.Children[1].Data.String()
+		for _, v := range entry.GetAttributeValues(filter.Children[0].Data.String()) {
+			if v == value {
+				return true
+			}
+		}
+		return false
+	case ldap.FilterPresent:
+		return len(entry.GetAttributeValues(filter.Data.String())) > 0
+	case ldap.FilterSubstrings:
+		return len(entry.GetAttributeValues(filter.Children[0].Data.String())) > 0
+	}
+	return false
+}
+
+// ldapResult 编码 LDAPResult 类型的响应 (绑定响应、查找结束)
+func ldapResult(tag ber.Tag, code uint16) *ber.Packet {
+	op := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Response")
+	op.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code), "resultCode"))
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "matchedDN"))
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "diagnosticMessage"))
+	return op
+}
+
+// ldapEntry 编码查找结果条目
+func ldapEntry(entry *ldap.Entry) *ber.Packet {
+	op := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, entry.DN, "objectName"))
+	attributes := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "attributes")
+	for _, attr := range entry.Attributes {
+		item := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "attribute")
+		item.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, attr.Name, "type"))
+		values := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "vals")
+		for _, v := range attr.Values {
+			values.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, v, "value"))
+		}
+		item.AppendChild(values)
+		attributes.AppendChild(item)
+	}
+	op.AppendChild(attributes)
+	return op
+}
+
+func TestLDAPClientEscapesFilter(t *testing.T) {
+	server := newLDAPTestServer(t)
+	server.addUser("ldap_wire_victim", "victim-pass", nil)
+	victimDN := "uid=ldap_wire_victim,ou=people,dc=example,dc=com"
+
+	// 未转义的通配符过滤器会匹配到其他用户
+	conn, err := dialLDAP(server.config())
+	if err != nil {
+		t.Fatalf("dialLDAP: %v", err)
+	}
+	result, err := conn.Search(ldap.NewSearchRequest("dc=example,dc=com", ldap.ScopeWholeSubtree, ldap.NeverDerefAliases,
+		0, 0, false, "(uid=*)", nil, nil))
+	conn.Close()
+	if err != nil || len(result.Entries) != 1 || result.Entries[0].DN != victimDN {
+		t.Fatalf("通配符查找应返回目标用户: %v %v", result, err)
+	}
+
+	// 登录名中的过滤器元字符被转义，按原值精确查找，不会匹配并绑定到其他用户
+	auth := NewLDAPAuthenticator(server.config(), nil)
+	if _, err := auth.Authenticate("*)(uid=*", "victim-pass"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("期望 ErrInvalidCredentials，实际 %v", err)
+	}
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if want := `(uid=\2a\29\28uid=\2a)`; len(server.filters) != 2 || server.filters[1] != want {
+		t.Errorf("过滤器 = %v，期望 %s", server.filters, want)
+	}
+	for _, dn := range server.binds {
+		if dn == victimDN {
+			t.Errorf("不应以其他用户的 DN 绑定: %v", server.binds)
+		}
+	}
+}
+
+func TestLDAPClientMapsGroupDNsToRoles(t *testing.T) {
+	server := newLDAPTestServer(t)
+	server.addUser("ldap_wire_grace", "grace-pass", map[string][]string{
+		"cn": {"Grace"},
+		// 组 DN 的 RDN 值包含转义字符，按解析后的组名匹配
+		"memberOf": {`CN=Orange\2C Admins,OU=Groups,DC=example,DC=com`, "cn=finance,ou=groups,dc=example,dc=com"},
+	})
+	mappings := []GroupMapping{
+		{Group: "Orange, Admins", Role: RoleAdmin},
+		{Group: "finance", Department: "财务部"},
+	}
+	auth := NewLDAPAuthenticator(server.config(mappings...), nil)
+
+	if _, err := auth.Authenticate("ldap_wire_grace", "wrong-pass"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("错误密码: 期望 ErrInvalidCredentials，实际 %v", err)
+	}
+	user, err := auth.Authenticate("ldap_wire_grace", "grace-pass")
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if user.Name != "Grace" || user.Role != RoleAdmin || user.Department != "财务部" || user.DepartmentID == 0 {
+		t.Errorf("组映射结果不正确: name=%q role=%q department=%q(%d)", user.Name, user.Role, user.Department, user.DepartmentID)
+	}
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if !strings.HasPrefix(strings.Join(server.binds, ";"), testBindDN) {
+		t.Errorf("应先以服务账号绑定: %v", server.binds)
+	}
+}
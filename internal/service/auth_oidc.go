@@ -0,0 +1,379 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/config"
+	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/pkg/oidc"
+	"gorm.io/gorm"
+)
+
+// OIDC 单点登录参数
+const (
+	oidcLoginTTL  = 10 * time.Minute // 发起登录到回调的有效期
+	oidcTicketTTL = time.Minute      // 回调签发的登录票据有效期
+)
+
+var (
+	// ErrOIDCDisabled 未启用 OIDC 单点登录
+	ErrOIDCDisabled = errors.New("未启用单点登录")
+	// ErrOIDCLoginInvalid 单点登录状态无效、过期或已使用
+	ErrOIDCLoginInvalid = errors.New("单点登录已过期，请重新登录")
+	// ErrOIDCIdentityInvalid ID Token 校验失败
+	ErrOIDCIdentityInvalid = errors.New("单点登录身份校验失败，请重新登录")
+	// ErrOIDCAccountConflict 单点登录用户名已被其他账户使用
+	ErrOIDCAccountConflict = errors.New("用户名已被其他账户使用，请联系管理员关联账户")
+)
+
+// OIDCConfig OIDC 单点登录配置
+type OIDCConfig struct {
+	oidc.Config
+	UsernameClaim string         // 作为用户名的声明 (缺失时依次使用 email、sub)
+	GroupsClaim   string         // 所属组声明
+	GroupMappings []GroupMapping // 按顺序匹配，为空时不根据组修改角色
+	LinkByEmail   bool           // 是否按已验证的邮箱关联已有账户
+	DisplayName   string         // 登录页显示的名称
+}
+
+// OIDCConfigFromEnv 从全局配置读取 OIDC 单点登录配置，未配置 OIDC_ISSUER 时返回 ErrOIDCDisabled
+func OIDCConfigFromEnv() (OIDCConfig, error) {
+	cfg := config.AppConfig
+	oidcCfg := OIDCConfig{
+		Config: oidc.Config{
+			Issuer:       cfg.OIDCIssuer,
+			ClientID:     cfg.OIDCClientID,
+			ClientSecret: cfg.OIDCClientSecret,
+			RedirectURL:  cfg.OIDCRedirectURL,
+			Scopes:       strings.Fields(cfg.OIDCScopes),
+		},
+		UsernameClaim: cfg.OIDCUsernameClaim,
+		GroupsClaim:   cfg.OIDCGroupsClaim,
+		LinkByEmail:   cfg.OIDCLinkByEmail,
+		DisplayName:   cfg.OIDCDisplayName,
+	}
+	if oidcCfg.Issuer == "" {
+		return oidcCfg, ErrOIDCDisabled
+	}
+	if oidcCfg.ClientID == "" || oidcCfg.RedirectURL == "" {
+		return oidcCfg, errors.New("未配置 OIDC_CLIENT_ID 或 OIDC_REDIRECT_URL")
+	}
+	hasOpenID := false
+	for _, scope := range oidcCfg.Scopes {
+		hasOpenID = hasOpenID || scope == "openid"
+	}
+	if !hasOpenID {
+		oidcCfg.Scopes = append([]string{"openid"}, oidcCfg.Scopes...)
+	}
+	if cfg.OIDCGroupMapping != "" {
+		if err := json.Unmarshal([]byte(cfg.OIDCGroupMapping), &oidcCfg.GroupMappings); err != nil {
+			return oidcCfg, fmt.Errorf("OIDC_GROUP_MAPPING 格式错误: %w", err)
+		}
+		if err := checkGroupMappings(NewRoleService(), oidcCfg.GroupMappings); err != nil {
+			return oidcCfg, fmt.Errorf("OIDC_GROUP_MAPPING 无效: %w", err)
+		}
+	}
+	return oidcCfg, nil
+}
+
+// oidcSSO 已启用的 OIDC 身份提供方
+type oidcSSO struct {
+	cfg      OIDCConfig
+	provider *oidc.Provider
+}
+
+// newOIDCSSO 按 OIDC_* 配置创建单点登录，未配置或配置无效时返回 nil
+func newOIDCSSO() *oidcSSO {
+	cfg, err := OIDCConfigFromEnv()
+	if errors.Is(err, ErrOIDCDisabled) {
+		return nil
+	}
+	if err != nil {
+		slog.Error("OIDC single sign-on disabled", "error", err)
+		return nil
+	}
+	return &oidcSSO{cfg: cfg, provider: oidc.NewProvider(cfg.Config, nil)}
+}
+
+// UseOIDC 替换 OIDC 单点登录配置，client 为 nil 时使用默认 HTTP 客户端 (用于在测试中接入本地模拟的身份提供方)
+func (s *AuthService) UseOIDC(cfg OIDCConfig, client *http.Client) {
+	s.sso = &oidcSSO{cfg: cfg, provider: oidc.NewProvider(cfg.Config, client)}
+}
+
+// OIDCInfo 获取单点登录配置 (供登录页展示)
+func (s *AuthService) OIDCInfo() dto.OIDCInfo {
+	if s.sso == nil {
+		return dto.OIDCInfo{}
+	}
+	return dto.OIDCInfo{Enabled: true, DisplayName: s.sso.cfg.DisplayName}
+}
+
+// StartOIDCLogin 发起单点登录
+// 生成 state、nonce 与 PKCE code_verifier 并保存，返回身份提供方的授权地址。
+func (s *AuthService) StartOIDCLogin(ctx context.Context) (string, error) {
+	if s.sso == nil {
+		return "", ErrOIDCDisabled
+	}
+
+	var state, nonce, verifier string
+	for _, v := range []*string{&state, &nonce, &verifier} {
+		raw, err := oidc.RandomString()
+		if err != nil {
+			return "", errors.New("生成登录状态失败")
+		}
+		*v = raw
+	}
+	if err := s.oidcLoginRepo.Create(&models.OIDCLogin{
+		StateHash:    hashToken(state),
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpireTime:   time.Now().Add(oidcLoginTTL),
+	}); err != nil {
+		return "", err
+	}
+
+	// 顺带清理过期的登录状态，失败不影响登录
+	_ = s.oidcLoginRepo.DeleteExpired()
+
+	authURL, err := s.sso.provider.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		slog.Warn("OIDC discovery failed", "issuer", s.sso.cfg.Issuer, "error", err)
+		return "", ErrAuthUnavailable
+	}
+	return authURL, nil
+}
+
+// CompleteOIDCLogin 处理身份提供方的回调
+// 校验 state，用授权码与 code_verifier 换取 ID Token，按 JWKS 校验签名、签发方、受众、有效期与 nonce，
+// 再关联或创建本地用户。成功时返回一次性登录票据，前端凭票据调用 ExchangeOIDCTicket 换取令牌
+// (令牌不经过浏览器地址栏)。
+func (s *AuthService) CompleteOIDCLogin(ctx context.Context, state, code string, client dto.ClientInfo) (string, error) {
+	if s.sso == nil {
+		return "", ErrOIDCDisabled
+	}
+	login, err := s.oidcLoginRepo.FindByStateHash(hashToken(state))
+	if err != nil || login.UserID != 0 {
+		return "", ErrOIDCLoginInvalid
+	}
+	if time.Now().After(login.ExpireTime) {
+		_, _ = s.oidcLoginRepo.Delete(login.ID)
+		return "", ErrOIDCLoginInvalid
+	}
+
+	rawIDToken, err := s.sso.provider.Exchange(ctx, code, login.CodeVerifier)
+	if err != nil {
+		slog.Warn("OIDC code exchange failed", "issuer", s.sso.cfg.Issuer, "error", err)
+		_, _ = s.oidcLoginRepo.Delete(login.ID)
+		return "", ErrAuthUnavailable
+	}
+	claims, err := s.sso.provider.VerifyIDToken(ctx, rawIDToken, login.Nonce)
+	if err != nil {
+		_, _ = s.oidcLoginRepo.Delete(login.ID)
+		if errors.Is(err, oidc.ErrInvalidIDToken) {
+			s.auditService.Record(AuditOIDCFailed, 0, "", client, err.Error())
+			return "", ErrOIDCIdentityInvalid
+		}
+		slog.Warn("OIDC ID token verification failed", "issuer", s.sso.cfg.Issuer, "error", err)
+		return "", ErrAuthUnavailable
+	}
+
+	user, err := s.oidcUser(claims)
+	if err != nil {
+		_, _ = s.oidcLoginRepo.Delete(login.ID)
+		if errors.Is(err, ErrOIDCAccountConflict) {
+			s.auditService.Record(AuditOIDCFailed, 0, claims.Subject, client, err.Error())
+		}
+		return "", err
+	}
+
+	ticket, err := newOpaqueToken()
+	if err != nil {
+		return "", errors.New("生成Token失败")
+	}
+	// state 只能使用一次
+	issued, err := s.oidcLoginRepo.IssueTicket(login.ID, user.ID, hashToken(ticket), time.Now().Add(oidcTicketTTL))
+	if err != nil {
+		return "", err
+	}
+	if !issued {
+		return "", ErrOIDCLoginInvalid
+	}
+	return ticket, nil
+}
+
+// ExchangeOIDCTicket 凭一次性登录票据完成单点登录
+// 与密码登录相同: 检查账户状态，已启用两步验证时返回挑战令牌，否则签发令牌。
+func (s *AuthService) ExchangeOIDCTicket(ticket string, client dto.ClientInfo) (*dto.LoginResult, error) {
+	login, err := s.oidcLoginRepo.FindByTicketHash(hashToken(ticket))
+	if err != nil || login.UserID == 0 {
+		return nil, ErrOIDCLoginInvalid
+	}
+	// 票据只能使用一次
+	deleted, err := s.oidcLoginRepo.Delete(login.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !deleted || time.Now().After(login.ExpireTime) {
+		return nil, ErrOIDCLoginInvalid
+	}
+
+	user, err := s.userRepo.FindByID(login.UserID)
+	if err != nil {
+		return nil, ErrOIDCLoginInvalid
+	}
+	return s.finishLogin(user, client)
+}
+
+// oidcUser 按 ID Token 查找、关联或创建本地用户
+// 1. 已绑定 (签发方 + sub) 的直接使用，单点登录账户同步姓名、邮箱与组映射；
+// 2. 开启 OIDC_LINK_BY_EMAIL 时按已验证的邮箱关联已有账户 (账户的认证来源不变)；
+// 3. 否则创建单点登录账户 (AuthSource=oidc)，用户名已被占用时拒绝登录，不自动接管。
+func (s *AuthService) oidcUser(claims *oidc.Claims) (*models.User, error) {
+	issuer := s.sso.provider.Issuer()
+
+	identity, err := s.identityRepo.Find(issuer, claims.Subject)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if err == nil {
+		user, err := s.userRepo.FindByID(identity.UserID)
+		if err == nil {
+			if claims.Email != "" && claims.Email != identity.Email {
+				_ = s.identityRepo.UpdateEmail(identity.ID, truncate(claims.Email, 100))
+			}
+			if user.AuthSource != AuthSourceOIDC {
+				return user, nil
+			}
+			return s.syncOIDCUser(user, claims)
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		// 绑定的用户已被删除，清理后按新用户处理
+		if err := s.identityRepo.DeleteByUser(identity.UserID); err != nil {
+			return nil, err
+		}
+	}
+
+	var user *models.User
+	if s.sso.cfg.LinkByEmail && claims.Email != "" && claims.EmailVerified {
+		if linked, err := s.userRepo.FindByEmail(claims.Email); err == nil {
+			user = linked
+		}
+	}
+	if user == nil {
+		if user, err = s.provisionOIDCUser(claims); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.identityRepo.Create(&models.UserIdentity{
+		UserID:   user.ID,
+		Provider: issuer,
+		Subject:  claims.Subject,
+		Email:    truncate(claims.Email, 100),
+	}); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// provisionOIDCUser 创建单点登录账户
+func (s *AuthService) provisionOIDCUser(claims *oidc.Claims) (*models.User, error) {
+	username := claims.String(s.sso.cfg.UsernameClaim)
+	if username == "" {
+		username = claims.Email
+	}
+	if username == "" {
+		username = claims.Subject
+	}
+	if len(username) > 50 || s.userRepo.ExistsByUsername(username) {
+		return nil, ErrOIDCAccountConflict
+	}
+	name := claims.Name
+	if name == "" {
+		name = username
+	}
+	role, department, position, err := mapGroups(s.roleService, s.sso.cfg.GroupMappings, claims.Strings(s.sso.cfg.GroupsClaim))
+	if err != nil {
+		return nil, err
+	}
+	department, position = truncate(department, 50), truncate(position, 50)
+	departmentID, err := s.departmentRepo.EnsureByName(department)
+	if err != nil {
+		return nil, err
+	}
+	positionID, err := s.positionRepo.EnsureByName(position)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	user := &models.User{
+		Username:           username,
+		Name:               truncate(name, 50),
+		Email:              truncate(claims.Email, 100),
+		Role:               role,
+		Department:         department,
+		DepartmentID:       departmentID,
+		Position:           position,
+		PositionID:         positionID,
+		Status:             1,
+		AuthSource:         AuthSourceOIDC,
+		PasswordChangeTime: &now,
+	}
+	if err := s.userRepo.Create(user); err != nil {
+		return nil, fmt.Errorf("创建单点登录用户失败: %w", err)
+	}
+	return user, nil
+}
+
+// syncOIDCUser 按 ID Token 更新单点登录账户的资料，配置了组映射时同步角色、部门与职位
+func (s *AuthService) syncOIDCUser(user *models.User, claims *oidc.Claims) (*models.User, error) {
+	updates := map[string]interface{}{}
+	if claims.Name != "" {
+		updates["name"] = truncate(claims.Name, 50)
+	}
+	if claims.Email != "" {
+		updates["email"] = truncate(claims.Email, 100)
+	}
+	role := user.Role
+	if len(s.sso.cfg.GroupMappings) > 0 {
+		var department, position string
+		var err error
+		if role, department, position, err = mapGroups(s.roleService, s.sso.cfg.GroupMappings, claims.Strings(s.sso.cfg.GroupsClaim)); err != nil {
+			return nil, err
+		}
+		updates["role"] = role
+		if department != "" {
+			if err := departmentUpdates(s.departmentRepo, updates, truncate(department, 50)); err != nil {
+				return nil, err
+			}
+		}
+		if position != "" {
+			if err := positionUpdates(s.positionRepo, updates, truncate(position, 50)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(updates) > 0 {
+		if err := s.userRepo.UpdateFields(user.ID, updates); err != nil {
+			return nil, err
+		}
+	}
+	// 身份提供方中的角色变更后，已签发的访问令牌随之失效
+	if user.Role != role {
+		if err := s.userRepo.IncrementTokenVersion(user.ID); err != nil {
+			return nil, err
+		}
+	}
+	return s.userRepo.FindByID(user.ID)
+}
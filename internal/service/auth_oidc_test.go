@@ -0,0 +1,301 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/pkg/jwk"
+	"github.com/FruitsAI/Orange/internal/pkg/oidc"
+	"github.com/FruitsAI/Orange/internal/repository"
+	gojwt "github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testOIDCClientID = "orange-web"
+	testOIDCKeyID    = "idp-key-1"
+)
+
+// oidcGrant 身份提供方签发授权码时记录的授权请求
+type oidcGrant struct {
+	challenge string
+	nonce     string
+	claims    gojwt.MapClaims
+}
+
+// mockIdP 本地模拟的 OIDC 身份提供方 (服务发现、JWKS 与令牌端点)
+// 授权端点不经过 HTTP: 测试调用 authorize 模拟用户在身份提供方完成登录并获得授权码。
+type mockIdP struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu        sync.Mutex
+	grants    map[string]oidcGrant
+	verifiers []string // 令牌端点收到的 code_verifier
+}
+
+func newMockIdP(t *testing.T) *mockIdP {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idp := &mockIdP{key: key, grants: map[string]oidcGrant{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, oidc.Discovery{
+			Issuer:                idp.server.URL,
+			AuthorizationEndpoint: idp.server.URL + "/authorize",
+			TokenEndpoint:         idp.server.URL + "/token",
+			JWKSURI:               idp.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		k, err := jwk.FromPublicKey(&key.PublicKey, testOIDCKeyID, "RS256")
+		if err != nil {
+			t.Error(err)
+		}
+		writeJSON(w, http.StatusOK, jwk.Set{Keys: []jwk.Key{k}})
+	})
+	mux.HandleFunc("/token", idp.token)
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+	return idp
+}
+
+// token 令牌端点: 校验 PKCE 后签发 ID Token
+func (idp *mockIdP) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+	verifier := r.PostForm.Get("code_verifier")
+
+	idp.mu.Lock()
+	grant, ok := idp.grants[r.PostForm.Get("code")]
+	delete(idp.grants, r.PostForm.Get("code"))
+	idp.verifiers = append(idp.verifiers, verifier)
+	idp.mu.Unlock()
+
+	if !ok || r.PostForm.Get("client_id") != testOIDCClientID || oidc.CodeChallenge(verifier) != grant.challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	claims := gojwt.MapClaims{
+		"iss":   idp.server.URL,
+		"aud":   testOIDCClientID,
+		"nonce": grant.nonce,
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(5 * time.Minute).Unix(),
+	}
+	for k, v := range grant.claims {
+		claims[k] = v
+	}
+	token := gojwt.NewWithClaims(gojwt.SigningMethodRS256, claims)
+	token.Header["kid"] = testOIDCKeyID
+	signed, err := token.SignedString(idp.key)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"id_token": signed, "token_type": "Bearer"})
+}
+
+// authorize 模拟用户在身份提供方登录，按授权地址中的 code_challenge 与 nonce 签发授权码
+// claims 会覆盖默认的 ID Token 声明 (可用于构造无效的 ID Token)。
+func (idp *mockIdP) authorize(t *testing.T, authURL string, claims gojwt.MapClaims) (state, code string) {
+	t.Helper()
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	if q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" {
+		t.Fatalf("授权地址缺少 PKCE 参数: %s", authURL)
+	}
+	if q.Get("client_id") != testOIDCClientID || q.Get("response_type") != "code" {
+		t.Fatalf("授权地址参数不正确: %s", authURL)
+	}
+
+	code, err = oidc.RandomString()
+	if err != nil {
+		t.Fatal(err)
+	}
+	idp.mu.Lock()
+	idp.grants[code] = oidcGrant{challenge: q.Get("code_challenge"), nonce: q.Get("nonce"), claims: claims}
+	idp.mu.Unlock()
+	return q.Get("state"), code
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// newOIDCAuthService 返回接入模拟身份提供方的认证服务
+func newOIDCAuthService(idp *mockIdP, mappings ...GroupMapping) *AuthService {
+	auth := NewAuthService()
+	auth.UseOIDC(OIDCConfig{
+		Config: oidc.Config{
+			Issuer:      idp.server.URL,
+			ClientID:    testOIDCClientID,
+			RedirectURL: "http://localhost:5173/oidc/callback",
+			Scopes:      []string{"openid", "profile", "email"},
+		},
+		UsernameClaim: "preferred_username",
+		GroupsClaim:   "groups",
+		GroupMappings: mappings,
+	}, idp.server.Client())
+	return auth
+}
+
+func TestOIDCLoginWithPKCE(t *testing.T) {
+	idp := newMockIdP(t)
+	auth := newOIDCAuthService(idp, GroupMapping{Group: "orange-admins", Role: RoleAdmin})
+	ctx := context.Background()
+	client := dto.ClientInfo{DeviceName: "test"}
+
+	authURL, err := auth.StartOIDCLogin(ctx)
+	if err != nil {
+		t.Fatalf("StartOIDCLogin: %v", err)
+	}
+	state, code := idp.authorize(t, authURL, gojwt.MapClaims{
+		"sub":                "oidc-sub-grace",
+		"preferred_username": "oidc_grace",
+		"name":               "Grace",
+		"email":              "grace@example.com",
+		"groups":             []string{"orange-admins"},
+	})
+
+	ticket, err := auth.CompleteOIDCLogin(ctx, state, code, client)
+	if err != nil {
+		t.Fatalf("CompleteOIDCLogin: %v", err)
+	}
+	// 令牌端点收到的 code_verifier 与授权请求中的 code_challenge 对应 (否则令牌端点拒绝)
+	if len(idp.verifiers) != 1 || idp.verifiers[0] == "" {
+		t.Fatalf("code_verifier = %v", idp.verifiers)
+	}
+
+	result, err := auth.ExchangeOIDCTicket(ticket, client)
+	if err != nil {
+		t.Fatalf("ExchangeOIDCTicket: %v", err)
+	}
+	if result.Token == "" || result.User == nil {
+		t.Fatalf("未签发令牌: %+v", result)
+	}
+	user := result.User
+	if user.Username != "oidc_grace" || user.Name != "Grace" || user.AuthSource != AuthSourceOIDC || user.Role != RoleAdmin {
+		t.Errorf("单点登录用户不正确: username=%q name=%q auth_source=%q role=%q", user.Username, user.Name, user.AuthSource, user.Role)
+	}
+
+	// state 与登录票据都只能使用一次
+	if _, err := auth.CompleteOIDCLogin(ctx, state, code, client); !errors.Is(err, ErrOIDCLoginInvalid) {
+		t.Errorf("重复回调: 期望 ErrOIDCLoginInvalid，实际 %v", err)
+	}
+	if _, err := auth.ExchangeOIDCTicket(ticket, client); !errors.Is(err, ErrOIDCLoginInvalid) {
+		t.Errorf("重复使用票据: 期望 ErrOIDCLoginInvalid，实际 %v", err)
+	}
+}
+
+func TestOIDCLoginRejectsWrongVerifier(t *testing.T) {
+	idp := newMockIdP(t)
+	auth := newOIDCAuthService(idp)
+	ctx := context.Background()
+
+	authURL, err := auth.StartOIDCLogin(ctx)
+	if err != nil {
+		t.Fatalf("StartOIDCLogin: %v", err)
+	}
+	state, code := idp.authorize(t, authURL, gojwt.MapClaims{"sub": "oidc-sub-heidi", "preferred_username": "oidc_heidi"})
+
+	// 授权码被绑定到其他 code_challenge (如被截获后用于另一次登录)，令牌端点校验 PKCE 失败
+	idp.mu.Lock()
+	grant := idp.grants[code]
+	grant.challenge = oidc.CodeChallenge("attacker-verifier")
+	idp.grants[code] = grant
+	idp.mu.Unlock()
+
+	if _, err := auth.CompleteOIDCLogin(ctx, state, code, dto.ClientInfo{}); !errors.Is(err, ErrAuthUnavailable) {
+		t.Fatalf("期望 ErrAuthUnavailable，实际 %v", err)
+	}
+	if repository.NewUserRepository().ExistsByUsername("oidc_heidi") {
+		t.Error("换取令牌失败不应创建用户")
+	}
+}
+
+func TestOIDCLoginStateMismatch(t *testing.T) {
+	idp := newMockIdP(t)
+	auth := newOIDCAuthService(idp)
+	ctx := context.Background()
+
+	authURL, err := auth.StartOIDCLogin(ctx)
+	if err != nil {
+		t.Fatalf("StartOIDCLogin: %v", err)
+	}
+	_, code := idp.authorize(t, authURL, gojwt.MapClaims{"sub": "oidc-sub-ivan", "preferred_username": "oidc_ivan"})
+
+	forged, err := oidc.RandomString()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := auth.CompleteOIDCLogin(ctx, forged, code, dto.ClientInfo{}); !errors.Is(err, ErrOIDCLoginInvalid) {
+		t.Fatalf("期望 ErrOIDCLoginInvalid，实际 %v", err)
+	}
+	if len(idp.verifiers) != 0 {
+		t.Error("state 无效时不应使用授权码换取令牌")
+	}
+}
+
+func TestOIDCLoginRejectsInvalidIDToken(t *testing.T) {
+	idp := newMockIdP(t)
+	auth := newOIDCAuthService(idp)
+	ctx := context.Background()
+	now := time.Now()
+
+	cases := []struct {
+		name   string
+		claims gojwt.MapClaims
+	}{
+		{"nonce", gojwt.MapClaims{"nonce": "replayed-nonce"}},
+		{"aud", gojwt.MapClaims{"aud": "another-client"}},
+		{"iss", gojwt.MapClaims{"iss": "https://evil.example.com"}},
+		{"exp", gojwt.MapClaims{"iat": now.Add(-2 * time.Hour).Unix(), "exp": now.Add(-time.Hour).Unix()}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			username := "oidc_bad_" + tc.name
+			claims := gojwt.MapClaims{"sub": "oidc-sub-" + username, "preferred_username": username}
+			for k, v := range tc.claims {
+				claims[k] = v
+			}
+
+			authURL, err := auth.StartOIDCLogin(ctx)
+			if err != nil {
+				t.Fatalf("StartOIDCLogin: %v", err)
+			}
+			state, code := idp.authorize(t, authURL, claims)
+			if _, err := auth.CompleteOIDCLogin(ctx, state, code, dto.ClientInfo{}); !errors.Is(err, ErrOIDCIdentityInvalid) {
+				t.Fatalf("期望 ErrOIDCIdentityInvalid，实际 %v", err)
+			}
+			if repository.NewUserRepository().ExistsByUsername(username) {
+				t.Error("ID Token 无效时不应创建用户")
+			}
+			// 校验失败后 state 作废
+			if _, err := auth.CompleteOIDCLogin(ctx, state, code, dto.ClientInfo{}); !errors.Is(err, ErrOIDCLoginInvalid) {
+				t.Errorf("重复回调: 期望 ErrOIDCLoginInvalid，实际 %v", err)
+			}
+		})
+	}
+}
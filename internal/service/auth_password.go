@@ -0,0 +1,94 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/pkg/password"
+)
+
+// ErrPasswordChangeRequired 密码已超过有效期，需先修改密码
+var ErrPasswordChangeRequired = errors.New("密码已过期，请先修改密码")
+
+// PasswordPolicy 获取当前密码策略 (供注册、修改密码页面展示规则)
+func (s *AuthService) PasswordPolicy() password.Policy {
+	return s.settingService.GetPasswordPolicy()
+}
+
+// hashNewPassword 按密码策略校验新密码并返回其哈希
+// user 为 nil 表示新建用户 (无需比对历史密码)；不符合策略时返回 *password.PolicyError。
+func (s *AuthService) hashNewPassword(user *models.User, pwd string) (string, error) {
+	policy := s.settingService.GetPasswordPolicy()
+
+	var history []string
+	if user != nil && policy.HistoryCount > 0 {
+		recent, err := s.historyRepo.ListRecent(user.ID, policy.HistoryCount)
+		if err != nil {
+			return "", err
+		}
+		// 当前密码已由 savePassword 记入历史；启用密码历史前设置的密码没有历史记录，
+		// 需补充当前密码，使其同样不能重复使用
+		history = recent
+		if len(recent) == 0 || recent[0] != user.Password {
+			history = append([]string{user.Password}, recent...)
+		}
+	}
+	if err := policy.Validate(pwd, history); err != nil {
+		return "", err
+	}
+
+	hashed, err := password.HashPassword(pwd)
+	if err != nil {
+		return "", errors.New("密码加密失败")
+	}
+	return hashed, nil
+}
+
+// savePassword 保存新密码并记录密码历史
+func (s *AuthService) savePassword(userID int64, hashed string) error {
+	if err := s.userRepo.UpdateFields(userID, map[string]interface{}{
+		"password":             hashed,
+		"password_change_time": time.Now(),
+	}); err != nil {
+		return err
+	}
+	return s.historyRepo.Add(userID, hashed, password.MaxHistoryCount)
+}
+
+// createUser 按密码策略校验密码后创建用户，并记录密码历史
+func (s *AuthService) createUser(user *models.User, pwd string) error {
+	hashed, err := s.hashNewPassword(nil, pwd)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	user.Password = hashed
+	user.PasswordChangeTime = &now
+	user.AuthSource = AuthSourceLocal
+	if err := s.userRepo.Create(user); err != nil {
+		return err
+	}
+	return s.historyRepo.Add(user.ID, hashed, password.MaxHistoryCount)
+}
+
+// verifyPassword 校验用户的当前密码 (目录账户通过对应的认证方式校验)
+func (s *AuthService) verifyPassword(user *models.User, pwd string) bool {
+	if isLocalUser(user) {
+		return password.CheckPassword(pwd, user.Password)
+	}
+	authenticated, err := s.authenticate(user.Username, pwd)
+	return err == nil && authenticated.ID == user.ID
+}
+
+// passwordExpired 用户密码是否已超过有效期 (目录账户的密码有效期由目录服务管理)
+func (s *AuthService) passwordExpired(user *models.User) bool {
+	if !isLocalUser(user) {
+		return false
+	}
+	changedAt := user.CreateTime
+	if user.PasswordChangeTime != nil {
+		changedAt = *user.PasswordChangeTime
+	}
+	return s.settingService.GetPasswordPolicy().Expired(changedAt)
+}
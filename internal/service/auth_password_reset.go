@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/config"
+	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/pkg/mailer"
+)
+
+// passwordResetWindow 重置邮件发送频率的统计窗口
+const passwordResetWindow = time.Hour
+
+// ErrPasswordResetInvalid 重置令牌无效、已使用或已过期
+var ErrPasswordResetInvalid = errors.New("重置链接无效或已过期，请重新申请")
+
+// UseMailer 替换邮件发送方式 (用于测试或自定义实现)
+func (s *AuthService) UseMailer(m mailer.Mailer) {
+	s.mailer = m
+}
+
+// RequestPasswordReset 申请找回密码
+// 邮箱对应启用中的本地账户时生成一次性重置令牌并发送邮件；同一邮箱每小时最多发送 PASSWORD_RESET_LIMIT 封。
+// 为避免泄露邮箱是否已注册，邮箱不存在、账户不可重置、超出频率限制或发送失败时同样返回成功，仅记录日志与审计。
+func (s *AuthService) RequestPasswordReset(ctx context.Context, email string, client dto.ClientInfo) error {
+	email = strings.TrimSpace(email)
+	user, err := s.userRepo.FindByEmail(email)
+	if err != nil || user.Status != 1 || !isLocalUser(user) {
+		return nil
+	}
+	email = strings.ToLower(email)
+
+	now := time.Now()
+	_ = s.resetRepo.DeleteBefore(now.Add(-24 * time.Hour))
+	sent, err := s.resetRepo.CountSince(email, now.Add(-passwordResetWindow))
+	if err != nil {
+		return err
+	}
+	if limit := config.AppConfig.PasswordResetLimit; limit > 0 && sent >= int64(limit) {
+		s.auditService.Record(AuditPasswordResetSent, user.ID, user.Username, client, "rate limited: "+email)
+		return nil
+	}
+
+	raw, err := newOpaqueToken()
+	if err != nil {
+		return errors.New("生成Token失败")
+	}
+	expiry := time.Duration(config.AppConfig.PasswordResetExpiry) * time.Minute
+	if err := s.resetRepo.Create(&models.PasswordResetToken{
+		UserID:     user.ID,
+		Email:      email,
+		TokenHash:  hashToken(raw),
+		RequestIP:  truncate(client.ClientIP, 64),
+		ExpireTime: now.Add(expiry),
+	}); err != nil {
+		return err
+	}
+
+	// 在后台发送，响应时间不因邮箱是否存在而不同
+	msg := passwordResetMail(user, email, raw, expiry)
+	go func() {
+		if err := s.mailer.Send(context.WithoutCancel(ctx), msg); err != nil {
+			slog.Error("Failed to send password reset mail", "user_id", user.ID, "error", err)
+			s.auditService.Record(AuditPasswordResetSent, user.ID, user.Username, client, "send failed: "+err.Error())
+			return
+		}
+		s.auditService.Record(AuditPasswordResetSent, user.ID, user.Username, client, email)
+	}()
+	return nil
+}
+
+// ResetPasswordByToken 凭邮件中的重置令牌设置新密码
+// 新密码需符合密码策略；成功后该用户的其余重置令牌失效、全部登录会话下线，并解除登录锁定。
+func (s *AuthService) ResetPasswordByToken(raw, newPassword string, client dto.ClientInfo) error {
+	token, err := s.resetRepo.FindByHash(hashToken(raw))
+	if err != nil || token.UsedTime != nil || time.Now().After(token.ExpireTime) {
+		return ErrPasswordResetInvalid
+	}
+	user, err := s.userRepo.FindByID(token.UserID)
+	if err != nil || user.Status != 1 || !isLocalUser(user) {
+		return ErrPasswordResetInvalid
+	}
+
+	hashed, err := s.hashNewPassword(user, newPassword)
+	if err != nil {
+		return err
+	}
+	if ok, err := s.resetRepo.MarkUsed(token.ID); err != nil {
+		return err
+	} else if !ok {
+		return ErrPasswordResetInvalid
+	}
+	if err := s.savePassword(user.ID, hashed); err != nil {
+		return err
+	}
+	if err := s.resetRepo.InvalidateByUser(user.ID); err != nil {
+		return err
+	}
+	if err := s.RevokeAll(user.ID); err != nil {
+		return err
+	}
+	_ = s.throttleRepo.Delete(throttleUser, strconv.FormatInt(user.ID, 10))
+
+	s.auditService.Record(AuditPasswordReset, user.ID, user.Username, client, "")
+	return nil
+}
+
+// passwordResetMail 组装找回密码邮件
+// 配置了 APP_URL 时附带重置链接；离线环境下用户可将重置码粘贴到登录页的找回密码表单中。
+func passwordResetMail(user *models.User, email, raw string, expiry time.Duration) mailer.Message {
+	var body strings.Builder
+	fmt.Fprintf(&body, "%s，您好：\n\n", user.Name)
+	fmt.Fprintf(&body, "我们收到了重置 Orange 账户 %s 密码的申请。", user.Username)
+	if base := strings.TrimRight(config.AppConfig.AppURL, "/"); base != "" {
+		fmt.Fprintf(&body, "请点击以下链接设置新密码：\n\n%s/login?reset_token=%s\n\n", base, url.QueryEscape(raw))
+		body.WriteString("也可以在登录页的「忘记密码」中输入以下重置码：\n\n")
+	} else {
+		body.WriteString("请在登录页的「忘记密码」中输入以下重置码设置新密码：\n\n")
+	}
+	fmt.Fprintf(&body, "%s\n\n", raw)
+	fmt.Fprintf(&body, "重置码 %d 分钟内有效，只能使用一次。如果这不是您本人的操作，请忽略本邮件，您的密码不会改变。\n", int(expiry/time.Minute))
+	return mailer.Message{To: email, Subject: "重置 Orange 账户密码", Body: body.String()}
+}
+
+// newMailer 按 MAIL_DRIVER 配置创建邮件发送方式，配置无效时写入本地文件
+func newMailer() mailer.Mailer {
+	cfg := config.AppConfig
+	switch cfg.MailDriver {
+	case "smtp":
+		if cfg.SMTPHost != "" {
+			return mailer.NewSMTPMailer(mailer.SMTPConfig{
+				Host:     cfg.SMTPHost,
+				Port:     cfg.SMTPPort,
+				Username: cfg.SMTPUsername,
+				Password: cfg.SMTPPassword,
+				From:     cfg.MailFrom,
+				Security: cfg.SMTPSecurity,
+			})
+		}
+		slog.Error("SMTP_HOST is not configured, mail will be saved to files")
+	case "", "file":
+	default:
+		slog.Warn("Unknown mail driver, mail will be saved to files", "driver", cfg.MailDriver)
+	}
+	dir := cfg.MailDir
+	if dir == "" {
+		dir = filepath.Join(cfg.DataDir, "mail")
+	}
+	return mailer.NewFileMailer(dir, cfg.MailFrom)
+}
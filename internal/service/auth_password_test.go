@@ -0,0 +1,83 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/pkg/password"
+)
+
+// isReused 错误是否为重复使用历史密码
+func isReused(err error) bool {
+	var policyErr *password.PolicyError
+	if !errors.As(err, &policyErr) {
+		return false
+	}
+	for _, v := range policyErr.Violations {
+		if v.Rule == password.RuleReused {
+			return true
+		}
+	}
+	return false
+}
+
+func TestPasswordHistoryCountsCurrentPasswordOnce(t *testing.T) {
+	settings := NewSettingService()
+	saved, err := settings.GetSecurity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer settings.UpdateSecurity(saved)
+	policy := password.DefaultPolicy()
+	policy.HistoryCount = 2
+	if err := settings.UpdateSecurity(dto.SecuritySettings{PasswordPolicy: policy}); err != nil {
+		t.Fatal(err)
+	}
+
+	user := createTestUser(t, "history_user", RoleUser)
+	auth := NewAuthService()
+	for _, pwd := range []string{"Orange-first-1", "Orange-second-2"} {
+		if err := auth.ResetPassword(user.ID, pwd); err != nil {
+			t.Fatalf("设置密码 %s 失败: %v", pwd, err)
+		}
+	}
+
+	// 最近 2 次为当前密码与上一次密码，二者都不能再次使用
+	for _, pwd := range []string{"Orange-second-2", "Orange-first-1"} {
+		if err := auth.ResetPassword(user.ID, pwd); !isReused(err) {
+			t.Errorf("%s: 期望重复使用错误，实际 %v", pwd, err)
+		}
+	}
+	if err := auth.ResetPassword(user.ID, "Orange-third-3"); err != nil {
+		t.Errorf("新密码应可使用: %v", err)
+	}
+}
+
+func TestPasswordHistoryIncludesPasswordSetBeforeHistory(t *testing.T) {
+	settings := NewSettingService()
+	saved, err := settings.GetSecurity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer settings.UpdateSecurity(saved)
+	policy := password.DefaultPolicy()
+	policy.HistoryCount = 1
+	if err := settings.UpdateSecurity(dto.SecuritySettings{PasswordPolicy: policy}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 直接写入的密码没有历史记录，仍不能重复使用
+	user := createTestUser(t, "history_legacy", RoleUser)
+	hashed, err := password.HashPassword("Orange-legacy-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := database.GetDB().Model(user).Update("password", hashed).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := NewAuthService().ResetPassword(user.ID, "Orange-legacy-1"); !isReused(err) {
+		t.Errorf("期望重复使用错误，实际 %v", err)
+	}
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/models"
+)
+
+// 个人访问令牌参数
+const (
+	PersonalTokenPrefix     = "orp_" // 令牌明文前缀 (用于区分登录令牌与个人访问令牌，也便于密钥扫描工具识别)
+	personalTokenMaxPerUser = 50     // 每个用户最多可创建的令牌数
+)
+
+var (
+	// ErrPersonalTokenNotFound 个人访问令牌不存在 (或不属于该用户)
+	ErrPersonalTokenNotFound = errors.New("访问令牌不存在")
+	// ErrPersonalTokenInvalid 个人访问令牌无效、已过期或已吊销
+	ErrPersonalTokenInvalid = errors.New("访问令牌无效或已过期")
+)
+
+// personalTokenScopes 可申请的授权范围
+// 资源:write 同时包含资源:read。
+var personalTokenScopes = []dto.TokenScope{
+	{Scope: "profile:read", Description: "查看个人信息"},
+	{Scope: "projects:read", Description: "查看项目"},
+	{Scope: "projects:write", Description: "创建、修改与删除项目"},
+	{Scope: "payments:read", Description: "查看收款"},
+	{Scope: "payments:write", Description: "创建、修改、确认与删除收款"},
+	{Scope: "dashboard:read", Description: "查看仪表盘与统计分析"},
+	{Scope: "dictionaries:read", Description: "查看数据字典"},
+	{Scope: "dictionaries:write", Description: "维护数据字典"},
+	{Scope: "notifications:read", Description: "查看通知"},
+	{Scope: "notifications:write", Description: "标记已读与管理通知"},
+}
+
+// PersonalTokenScopes 获取可申请的授权范围
+func (s *AuthService) PersonalTokenScopes() []dto.TokenScope {
+	return personalTokenScopes
+}
+
+// ListPersonalTokens 获取用户的个人访问令牌
+func (s *AuthService) ListPersonalTokens(userID int64) ([]dto.PersonalTokenView, error) {
+	tokens, err := s.patRepo.ListByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	views := make([]dto.PersonalTokenView, 0, len(tokens))
+	for _, t := range tokens {
+		views = append(views, toPersonalTokenView(t))
+	}
+	return views, nil
+}
+
+// CreatePersonalToken 创建个人访问令牌
+// 只保存令牌哈希，明文仅在返回值中出现一次。
+func (s *AuthService) CreatePersonalToken(userID int64, input dto.CreatePersonalTokenRequest) (*dto.PersonalTokenCreated, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, errors.New("令牌名称不能为空")
+	}
+	scopes, err := normalizeScopes(input.Scopes)
+	if err != nil {
+		return nil, err
+	}
+	count, err := s.patRepo.CountByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= personalTokenMaxPerUser {
+		return nil, fmt.Errorf("每个用户最多创建 %d 个访问令牌，请先删除不再使用的令牌", personalTokenMaxPerUser)
+	}
+
+	opaque, err := newOpaqueToken()
+	if err != nil {
+		return nil, errors.New("生成Token失败")
+	}
+	raw := PersonalTokenPrefix + opaque
+	token := &models.PersonalAccessToken{
+		UserID:      userID,
+		Name:        truncate(name, 100),
+		TokenHash:   hashToken(raw),
+		TokenPrefix: raw[:len(PersonalTokenPrefix)+4],
+		Scopes:      strings.Join(scopes, ","),
+	}
+	if input.ExpiresInDays > 0 {
+		expire := time.Now().AddDate(0, 0, input.ExpiresInDays)
+		token.ExpireTime = &expire
+	}
+	if err := s.patRepo.Create(token); err != nil {
+		return nil, err
+	}
+	return &dto.PersonalTokenCreated{PersonalTokenView: toPersonalTokenView(*token), Token: raw}, nil
+}
+
+// RevokePersonalToken 吊销 (删除) 用户的个人访问令牌，立即失效
+func (s *AuthService) RevokePersonalToken(userID, tokenID int64) error {
+	token, err := s.patRepo.FindByID(tokenID)
+	if err != nil || token.UserID != userID {
+		return ErrPersonalTokenNotFound
+	}
+	return s.patRepo.Delete(token.ID)
+}
+
+// CheckPersonalToken 校验个人访问令牌，返回令牌所属用户与授权范围
+// 令牌不存在、已过期或用户已禁用时返回 ErrPersonalTokenInvalid。最近使用时间在此更新 (同一令牌每分钟最多写一次)。
+// 令牌沿用用户当前的角色；用户密码已过期或被要求启用两步验证但尚未启用时，与登录令牌一样返回对应错误。
+func (s *AuthService) CheckPersonalToken(raw, clientIP string) (*models.User, []string, error) {
+	token, err := s.patRepo.FindByHash(hashToken(raw))
+	if err != nil {
+		return nil, nil, ErrPersonalTokenInvalid
+	}
+	now := time.Now()
+	if token.ExpireTime != nil && now.After(*token.ExpireTime) {
+		return nil, nil, ErrPersonalTokenInvalid
+	}
+	user, err := s.userRepo.FindByID(token.UserID)
+	if err != nil || user.Status != 1 {
+		return nil, nil, ErrPersonalTokenInvalid
+	}
+
+	if token.LastUsedTime == nil || now.Sub(*token.LastUsedTime) >= sessionTouchInterval || token.LastUsedIP != clientIP {
+		_ = s.patRepo.UpdateFields(token.ID, map[string]interface{}{
+			"last_used_time": now,
+			"last_used_ip":   truncate(clientIP, 64),
+		})
+	}
+	if s.passwordExpired(user) {
+		return nil, nil, ErrPasswordChangeRequired
+	}
+	if !user.TwoFactorEnabled && s.twoFactorRequired(user.Role) {
+		return nil, nil, ErrTwoFactorSetupRequired
+	}
+	return user, splitScopes(token.Scopes), nil
+}
+
+// ScopeAllows 授权范围是否包含 required (资源:write 包含资源:read)
+func ScopeAllows(scopes []string, required string) bool {
+	resource, action, _ := strings.Cut(required, ":")
+	for _, scope := range scopes {
+		if scope == required || (action == "read" && scope == resource+":write") {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeScopes 校验授权范围并去重排序
+func normalizeScopes(scopes []string) ([]string, error) {
+	valid := make(map[string]bool, len(personalTokenScopes))
+	for _, s := range personalTokenScopes {
+		valid[s.Scope] = true
+	}
+	seen := make(map[string]bool, len(scopes))
+	normalized := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if !valid[scope] {
+			return nil, fmt.Errorf("无效的授权范围: %s", scope)
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			normalized = append(normalized, scope)
+		}
+	}
+	if len(normalized) == 0 {
+		return nil, errors.New("请至少选择一个授权范围")
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+// splitScopes 解析逗号分隔的授权范围
+func splitScopes(scopes string) []string {
+	if scopes == "" {
+		return []string{}
+	}
+	return strings.Split(scopes, ",")
+}
+
+// toPersonalTokenView 转换为对外展示结构
+func toPersonalTokenView(t models.PersonalAccessToken) dto.PersonalTokenView {
+	return dto.PersonalTokenView{
+		PersonalAccessToken: t,
+		Scopes:              splitScopes(t.Scopes),
+		Expired:             t.ExpireTime != nil && time.Now().After(*t.ExpireTime),
+	}
+}
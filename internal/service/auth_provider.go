@@ -0,0 +1,182 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/FruitsAI/Orange/internal/config"
+	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/pkg/password"
+	"github.com/FruitsAI/Orange/internal/repository"
+	"github.com/go-ldap/ldap/v3"
+)
+
+// 认证来源 (User.AuthSource)
+const (
+	AuthSourceLocal = "local" // 本地密码
+	AuthSourceLDAP  = "ldap"  // LDAP / Active Directory 目录账户
+	AuthSourceOIDC  = "oidc"  // OIDC 单点登录账户
+)
+
+var (
+	// ErrInvalidCredentials 用户名或密码错误 (或账户不属于该认证方式)
+	ErrInvalidCredentials = errors.New("用户名或密码错误")
+	// ErrAuthUnavailable 认证服务暂不可用 (如目录服务连接失败)
+	ErrAuthUnavailable = errors.New("认证服务暂不可用，请稍后再试")
+	// ErrExternalAccount 目录/单点登录账户的密码由外部身份提供方管理
+	ErrExternalAccount = errors.New("该账户由公司目录或单点登录管理，请在对应的身份服务中修改密码")
+)
+
+// GroupMapping 外部身份提供方的组到 Orange 用户属性的映射 (LDAP_GROUP_MAPPING、OIDC_GROUP_MAPPING)
+// Group 可以是组的完整 DN，也可以只是组名 (DN 第一段的值，如 cn=finance,... 中的 finance)，不区分大小写。
+type GroupMapping struct {
+	Group      string `json:"group"`      // 组
+	Role       string `json:"role"`       // 角色编码 (须已存在)，为空表示不指定
+	Department string `json:"department"` // 部门，为空表示不指定
+	Position   string `json:"position"`   // 职位，为空表示不指定
+}
+
+// Authenticator 认证方式
+// 校验登录名与密码，成功时返回对应的本地用户 (目录账户首次登录时自动创建)。
+// 登录名不属于该认证方式或密码错误时返回 ErrInvalidCredentials，其他错误视为认证服务不可用。
+type Authenticator interface {
+	// Name 认证方式名称 (与 AUTH_PROVIDERS 中的取值一致)
+	Name() string
+	// Authenticate 校验登录名 (用户名/邮箱/手机号) 与密码
+	Authenticate(credential, pwd string) (*models.User, error)
+}
+
+// LocalAuthenticator 本地密码认证 (bcrypt 哈希)
+// 只认证 AuthSource 为 local 的用户。
+type LocalAuthenticator struct {
+	userRepo *repository.UserRepository
+}
+
+// NewLocalAuthenticator 创建本地密码认证
+func NewLocalAuthenticator() *LocalAuthenticator {
+	return &LocalAuthenticator{userRepo: repository.NewUserRepository()}
+}
+
+// Name 认证方式名称
+func (a *LocalAuthenticator) Name() string {
+	return AuthSourceLocal
+}
+
+// Authenticate 校验本地密码
+func (a *LocalAuthenticator) Authenticate(credential, pwd string) (*models.User, error) {
+	user, err := a.userRepo.FindByCredential(credential)
+	if err != nil || !isLocalUser(user) || !password.CheckPassword(pwd, user.Password) {
+		return nil, ErrInvalidCredentials
+	}
+	return user, nil
+}
+
+// UseAuthenticators 替换认证方式 (按顺序尝试)，用于接入自定义认证或在测试中注入目录服务桩
+func (s *AuthService) UseAuthenticators(authenticators ...Authenticator) {
+	s.authenticators = authenticators
+}
+
+// authenticate 依次尝试各认证方式，任一成功即返回
+// 全部失败时，若有认证方式不可用则返回 ErrAuthUnavailable (不计入登录失败次数)，否则返回 ErrInvalidCredentials。
+func (s *AuthService) authenticate(credential, pwd string) (*models.User, error) {
+	var unavailable bool
+	for _, a := range s.authenticators {
+		user, err := a.Authenticate(credential, pwd)
+		if err == nil {
+			return user, nil
+		}
+		if !errors.Is(err, ErrInvalidCredentials) {
+			slog.Warn("Authentication provider failed", "provider", a.Name(), "error", err)
+			unavailable = true
+		}
+	}
+	if unavailable {
+		return nil, ErrAuthUnavailable
+	}
+	return nil, ErrInvalidCredentials
+}
+
+// newAuthenticators 按 AUTH_PROVIDERS 配置创建认证方式，配置无效时仅使用本地密码认证
+func newAuthenticators() []Authenticator {
+	var authenticators []Authenticator
+	for _, name := range strings.Split(config.AppConfig.AuthProviders, ",") {
+		switch name = strings.TrimSpace(name); name {
+		case "":
+		case AuthSourceLocal:
+			authenticators = append(authenticators, NewLocalAuthenticator())
+		case AuthSourceLDAP:
+			cfg, err := LDAPConfigFromEnv()
+			if err != nil {
+				slog.Error("LDAP authentication disabled", "error", err)
+				continue
+			}
+			authenticators = append(authenticators, NewLDAPAuthenticator(cfg, nil))
+		default:
+			slog.Warn("Unknown authentication provider", "name", name)
+		}
+	}
+	if len(authenticators) == 0 {
+		authenticators = append(authenticators, NewLocalAuthenticator())
+	}
+	return authenticators
+}
+
+// isLocalUser 是否为本地密码账户
+func isLocalUser(user *models.User) bool {
+	return user.AuthSource == "" || user.AuthSource == AuthSourceLocal
+}
+
+// checkGroupMappings 校验组映射指定的角色均已存在 (加载 LDAP_GROUP_MAPPING、OIDC_GROUP_MAPPING 时调用)
+func checkGroupMappings(roles *RoleService, mappings []GroupMapping) error {
+	for _, mapping := range mappings {
+		if mapping.Role != "" && !roles.Exists(mapping.Role) {
+			return fmt.Errorf("组 %s 映射的角色 %s 不存在", mapping.Group, mapping.Role)
+		}
+	}
+	return nil
+}
+
+// mapGroups 按映射规则计算角色、部门与职位
+// 任一匹配的组映射为 admin 即为管理员，否则取第一个指定了角色的匹配项；
+// 部门与职位取第一个指定了该字段的匹配项。
+// 映射得到的角色已被删除时返回错误，不以不存在的角色创建或更新用户。
+func mapGroups(roles *RoleService, mappings []GroupMapping, groups []string) (role, department, position string, err error) {
+	for _, mapping := range mappings {
+		if !memberOf(groups, mapping.Group) {
+			continue
+		}
+		if mapping.Role == RoleAdmin || (role == "" && mapping.Role != "") {
+			role = mapping.Role
+		}
+		if department == "" {
+			department = mapping.Department
+		}
+		if position == "" {
+			position = mapping.Position
+		}
+	}
+	if role == "" {
+		role = RoleUser
+	}
+	if !roles.Exists(role) {
+		slog.Error("Group mapping role not found", "role", role)
+		return "", "", "", fmt.Errorf("组映射的角色 %s 不存在", role)
+	}
+	return role, department, position, nil
+}
+
+// memberOf 判断 groups (组名或组 DN 列表) 是否包含 group (DN 或组名)
+func memberOf(groups []string, group string) bool {
+	for _, dn := range groups {
+		if strings.EqualFold(dn, group) {
+			return true
+		}
+		if parsed, err := ldap.ParseDN(dn); err == nil && len(parsed.RDNs) > 0 && len(parsed.RDNs[0].Attributes) > 0 &&
+			strings.EqualFold(parsed.RDNs[0].Attributes[0].Value, group) {
+			return true
+		}
+	}
+	return false
+}
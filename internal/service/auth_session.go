@@ -0,0 +1,122 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/google/uuid"
+)
+
+// sessionTouchInterval 会话最近活跃时间的最小更新间隔
+const sessionTouchInterval = time.Minute
+
+// ErrSessionNotFound 登录会话不存在 (或不属于该用户)
+var ErrSessionNotFound = errors.New("登录会话不存在")
+
+// ListSessions 获取用户的有效登录会话，currentID 为发起请求的会话ID (用于标记当前会话)
+func (s *AuthService) ListSessions(userID, currentID int64) ([]dto.SessionView, error) {
+	sessions, err := s.sessionRepo.ListActiveByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	views := make([]dto.SessionView, 0, len(sessions))
+	for _, session := range sessions {
+		views = append(views, dto.SessionView{UserSession: session, Current: session.ID == currentID})
+	}
+	return views, nil
+}
+
+// RevokeSession 吊销用户的指定登录会话
+// 会话的访问令牌与刷新令牌立即失效，该设备需重新登录。
+func (s *AuthService) RevokeSession(userID, sessionID int64) error {
+	session, err := s.sessionRepo.FindByID(sessionID)
+	if err != nil || session.UserID != userID || session.RevokeTime != nil {
+		return ErrSessionNotFound
+	}
+	return s.revokeSession(session.ID)
+}
+
+// revokeSession 吊销会话及其下的刷新令牌
+func (s *AuthService) revokeSession(sessionID int64) error {
+	if err := s.tokenRepo.RevokeBySession(sessionID); err != nil {
+		return err
+	}
+	return s.sessionRepo.Revoke(sessionID)
+}
+
+// newSession 为一次登录创建会话记录
+// TokenID 先以占位值写入，签发令牌时替换为访问令牌的 jti。
+func (s *AuthService) newSession(userID int64, client dto.ClientInfo) (*models.UserSession, error) {
+	deviceName := strings.TrimSpace(client.DeviceName)
+	if deviceName == "" {
+		deviceName = deviceNameFromUserAgent(client.UserAgent)
+	}
+	session := &models.UserSession{
+		UserID:       userID,
+		TokenID:      uuid.NewString(),
+		DeviceName:   truncate(deviceName, 100),
+		ClientIP:     truncate(client.ClientIP, 64),
+		UserAgent:    truncate(client.UserAgent, 255),
+		LastSeenTime: time.Now(),
+	}
+	if err := s.sessionRepo.Create(session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// deviceNameFromUserAgent 根据 User-Agent 粗略推断设备名称 (如 "Chrome / Windows")
+func deviceNameFromUserAgent(ua string) string {
+	if ua == "" {
+		return "未知设备"
+	}
+
+	var browser string
+	switch {
+	case strings.Contains(ua, "Edg/"):
+		browser = "Edge"
+	case strings.Contains(ua, "Chrome/"):
+		browser = "Chrome"
+	case strings.Contains(ua, "Firefox/"):
+		browser = "Firefox"
+	case strings.Contains(ua, "Safari/"):
+		browser = "Safari"
+	}
+
+	var platform string
+	switch {
+	case strings.Contains(ua, "Windows"):
+		platform = "Windows"
+	case strings.Contains(ua, "iPhone"), strings.Contains(ua, "iPad"):
+		platform = "iOS"
+	case strings.Contains(ua, "Mac OS X"):
+		platform = "macOS"
+	case strings.Contains(ua, "Android"):
+		platform = "Android"
+	case strings.Contains(ua, "Linux"):
+		platform = "Linux"
+	}
+
+	switch {
+	case browser != "" && platform != "":
+		return browser + " / " + platform
+	case browser != "":
+		return browser
+	case platform != "":
+		return platform
+	default:
+		return truncate(ua, 100)
+	}
+}
+
+// truncate 按字符数截断字符串，避免超出列长度
+func truncate(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	return string([]rune(s)[:max])
+}
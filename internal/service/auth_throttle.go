@@ -0,0 +1,191 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/config"
+	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/models"
+	"gorm.io/gorm"
+)
+
+// 登录失败计数类型
+const (
+	throttleUser = "user" // 按账户 (用户ID，账户不存在时为提交的登录名)
+	throttleIP   = "ip"   // 按客户端 IP
+)
+
+// LoginThrottledError 登录尝试被拒绝 (锁定中或失败后等待期内)
+type LoginThrottledError struct {
+	Locked     bool          // 是否为锁定 (否则为失败后的等待期)
+	RetryAfter time.Duration // 剩余等待时间
+}
+
+func (e *LoginThrottledError) Error() string {
+	if e.Locked {
+		minutes := int(e.RetryAfter.Round(time.Minute) / time.Minute)
+		if minutes < 1 {
+			minutes = 1
+		}
+		return fmt.Sprintf("登录失败次数过多，已临时锁定，请 %d 分钟后再试", minutes)
+	}
+	seconds := int((e.RetryAfter + time.Second - 1) / time.Second)
+	return fmt.Sprintf("尝试过于频繁，请 %d 秒后再试", seconds)
+}
+
+// UnlockUser 解除账户的登录锁定并清空失败计数 (管理员)
+func (s *AuthService) UnlockUser(id, operatorID int64) error {
+	if _, err := s.userRepo.FindByID(id); err != nil {
+		return errors.New("用户不存在")
+	}
+	if err := s.throttleRepo.Delete(throttleUser, strconv.FormatInt(id, 10)); err != nil {
+		return err
+	}
+	s.auditService.RecordOperation(AuditAccountUnlocked, operatorID, id, "")
+	return nil
+}
+
+// checkLoginThrottles 检查来源 IP 与账户是否处于锁定或等待期，被拒绝的尝试写入审计日志
+func (s *AuthService) checkLoginThrottles(user *models.User, credential string, client dto.ClientInfo) error {
+	var userID int64
+	if user != nil {
+		userID = user.ID
+	}
+	for _, throttle := range []struct{ kind, key string }{
+		{throttleIP, client.ClientIP},
+		{throttleUser, loginAccountKey(user, credential)},
+	} {
+		if err := s.checkLoginThrottle(throttle.kind, throttle.key); err != nil {
+			s.auditService.Record(AuditLoginBlocked, userID, credential, client, throttle.kind+": "+err.Error())
+			return err
+		}
+	}
+	return nil
+}
+
+// checkLoginThrottle 检查账户或 IP 是否处于锁定或失败后的等待期
+func (s *AuthService) checkLoginThrottle(kind, key string) error {
+	if key == "" {
+		return nil
+	}
+	throttle, err := s.throttleRepo.Find(kind, key)
+	if err != nil {
+		return nil
+	}
+
+	now := time.Now()
+	if throttle.LockedUntil != nil && now.Before(*throttle.LockedUntil) {
+		return &LoginThrottledError{Locked: true, RetryAfter: throttle.LockedUntil.Sub(now)}
+	}
+	if throttle.LockedUntil == nil {
+		if next := throttle.LastFailureTime.Add(loginDelay(throttle.Failures)); now.Before(next) {
+			return &LoginThrottledError{RetryAfter: next.Sub(now)}
+		}
+	}
+	return nil
+}
+
+// recordLoginFailure 失败次数加一，达到上限时锁定，返回本次是否触发锁定
+// 距上次失败超过统计窗口 (锁定时长) 或锁定已到期时重新计数。
+func (s *AuthService) recordLoginFailure(kind, key string, max int) (bool, error) {
+	if key == "" {
+		return false, nil
+	}
+	throttle, err := s.throttleRepo.Find(kind, key)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		throttle = &models.LoginThrottle{Kind: kind, Key: key}
+	} else if err != nil {
+		return false, err
+	}
+
+	now := time.Now()
+	window := loginLockout()
+	if throttle.LockedUntil != nil || now.Sub(throttle.LastFailureTime) > window {
+		throttle.Failures = 0
+		throttle.LockedUntil = nil
+	}
+	throttle.Failures++
+	throttle.LastFailureTime = now
+
+	locked := max > 0 && throttle.Failures >= max
+	if locked {
+		until := now.Add(window)
+		throttle.LockedUntil = &until
+	}
+	return locked, s.throttleRepo.Save(throttle)
+}
+
+// loginFailed 记录一次密码错误 (账户与 IP 各计一次) 并写入审计日志
+// 本次失败触发锁定时返回锁定错误，否则返回统一的 "用户名或密码错误"，不区分账户是否存在。
+func (s *AuthService) loginFailed(user *models.User, credential string, client dto.ClientInfo) error {
+	var userID int64
+	if user != nil {
+		userID = user.ID
+	}
+	s.auditService.Record(AuditLoginFailed, userID, credential, client, "")
+	if err := s.countLoginFailure(user, credential, client); err != nil {
+		return err
+	}
+	return errors.New("用户名或密码错误")
+}
+
+// countLoginFailure 登录失败 (密码或两步验证码错误) 时账户与 IP 各计一次失败
+// 本次失败触发锁定时写入锁定审计并返回锁定错误，否则返回 nil。
+func (s *AuthService) countLoginFailure(user *models.User, credential string, client dto.ClientInfo) error {
+	var userID int64
+	if user != nil {
+		userID = user.ID
+	}
+
+	cfg := config.AppConfig
+	accountLocked, err := s.recordLoginFailure(throttleUser, loginAccountKey(user, credential), cfg.LoginMaxFailures)
+	if err != nil {
+		return err
+	}
+	ipLocked, err := s.recordLoginFailure(throttleIP, client.ClientIP, cfg.LoginIPMaxFailures)
+	if err != nil {
+		return err
+	}
+
+	if accountLocked {
+		s.auditService.Record(AuditAccountLocked, userID, credential, client, fmt.Sprintf("连续失败 %d 次", cfg.LoginMaxFailures))
+	}
+	if ipLocked {
+		s.auditService.Record(AuditIPLocked, userID, credential, client, fmt.Sprintf("连续失败 %d 次", cfg.LoginIPMaxFailures))
+	}
+	if accountLocked || ipLocked {
+		return &LoginThrottledError{Locked: true, RetryAfter: loginLockout()}
+	}
+	return nil
+}
+
+// loginAccountKey 账户维度的计数键
+// 账户存在时使用用户ID (用户名、邮箱、手机号登录共用一个计数)，否则使用提交的登录名。
+func loginAccountKey(user *models.User, credential string) string {
+	if user != nil {
+		return strconv.FormatInt(user.ID, 10)
+	}
+	return "name:" + truncate(strings.ToLower(strings.TrimSpace(credential)), 100)
+}
+
+// loginLockout 锁定时长 (同时作为失败计数的统计窗口)
+func loginLockout() time.Duration {
+	return time.Duration(config.AppConfig.LoginLockout) * time.Minute
+}
+
+// loginDelay 连续失败 failures 次后需等待的时间 (指数增长，不超过锁定时长)
+func loginDelay(failures int) time.Duration {
+	base := time.Duration(config.AppConfig.LoginDelayBase) * time.Second
+	if base <= 0 || failures <= 0 {
+		return 0
+	}
+	delay := base
+	for i := 1; i < failures && delay < loginLockout(); i++ {
+		delay *= 2
+	}
+	return min(delay, loginLockout())
+}
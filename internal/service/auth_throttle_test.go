@@ -0,0 +1,85 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/FruitsAI/Orange/internal/config"
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/pkg/password"
+)
+
+// setLoginLimits 临时修改账户锁定阈值，关闭失败后的等待期 (测试结束时恢复)
+func setLoginLimits(t *testing.T, maxFailures int) {
+	t.Helper()
+	saved := *config.AppConfig
+	t.Cleanup(func() { *config.AppConfig = saved })
+	config.AppConfig.LoginMaxFailures = maxFailures
+	config.AppConfig.LoginIPMaxFailures = 0
+	config.AppConfig.LoginDelayBase = 0
+}
+
+// createTwoFactorUser 创建已启用两步验证的本地用户
+func createTwoFactorUser(t *testing.T, username, pwd string) *models.User {
+	t.Helper()
+	user := createTestUser(t, username, RoleUser)
+	hashed, err := password.HashPassword(pwd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := database.GetDB().Model(user).Updates(map[string]interface{}{
+		"password":           hashed,
+		"two_factor_enabled": true,
+	}).Error; err != nil {
+		t.Fatal(err)
+	}
+	return user
+}
+
+// auditCount 统计用户的某类审计事件
+func auditCount(t *testing.T, userID int64, action string) int64 {
+	t.Helper()
+	var count int64
+	if err := database.GetDB().Model(&models.AuditLog{}).Where("user_id = ? AND action = ?", userID, action).Count(&count).Error; err != nil {
+		t.Fatal(err)
+	}
+	return count
+}
+
+func TestTwoFactorFailuresCountTowardLockout(t *testing.T) {
+	setLoginLimits(t, 3)
+	user := createTwoFactorUser(t, "throttle_2fa", "Orange-2fa-pass")
+	auth := NewAuthService()
+	client := dto.ClientInfo{ClientIP: "192.0.2.14"}
+
+	result, err := auth.Login("throttle_2fa", "Orange-2fa-pass", client)
+	if err != nil || !result.TwoFactorRequired {
+		t.Fatalf("Login: %+v %v", result, err)
+	}
+
+	// 同一挑战内的验证码错误计入账户失败次数，达到上限时锁定
+	for i := 1; i <= 3; i++ {
+		_, err := auth.VerifyTwoFactorLogin(result.ChallengeToken, "wrong-code", client)
+		var throttled *LoginThrottledError
+		if locked := errors.As(err, &throttled) && throttled.Locked; locked != (i == 3) {
+			t.Fatalf("第 %d 次错误: %v", i, err)
+		}
+	}
+	if n := auditCount(t, user.ID, AuditTwoFactorFailed); n != 3 {
+		t.Errorf("two_factor_failed 审计 %d 条，期望 3", n)
+	}
+	if n := auditCount(t, user.ID, AuditAccountLocked); n != 1 {
+		t.Errorf("account_locked 审计 %d 条，期望 1", n)
+	}
+
+	// 锁定期间即使密码正确也无法登录，触发锁定的挑战已作废
+	var throttled *LoginThrottledError
+	if _, err := auth.Login("throttle_2fa", "Orange-2fa-pass", client); !errors.As(err, &throttled) {
+		t.Errorf("锁定后登录: 期望 LoginThrottledError，实际 %v", err)
+	}
+	if _, err := auth.VerifyTwoFactorLogin(result.ChallengeToken, "wrong-code", client); !errors.Is(err, ErrLoginChallengeInvalid) {
+		t.Errorf("锁定后挑战: 期望 ErrLoginChallengeInvalid，实际 %v", err)
+	}
+}
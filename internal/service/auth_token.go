@@ -0,0 +1,204 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/config"
+	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/pkg/jwt"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrRefreshTokenInvalid 刷新令牌无效、过期或已吊销
+	ErrRefreshTokenInvalid = errors.New("登录已过期，请重新登录")
+	// ErrTokenRevoked 访问令牌已被吊销 (退出登录、修改密码或账户被禁用)
+	ErrTokenRevoked = errors.New("登录已失效，请重新登录")
+)
+
+// Refresh 使用刷新令牌换取新的令牌对
+// 旧刷新令牌随即吊销 (轮换)。已吊销的令牌被再次使用说明可能已泄露，此时吊销该用户的全部登录。
+// 新令牌沿用原登录会话，会话已被吊销时刷新失败。
+func (s *AuthService) Refresh(refreshToken string, client dto.ClientInfo) (*dto.LoginResult, error) {
+	token, err := s.tokenRepo.FindByHash(hashToken(refreshToken))
+	if err != nil {
+		return nil, ErrRefreshTokenInvalid
+	}
+
+	// 会话已下线时令牌随之失效，不视为重放
+	var session *models.UserSession
+	if token.SessionID != 0 {
+		session, err = s.sessionRepo.FindByID(token.SessionID)
+		if err != nil || session.RevokeTime != nil {
+			return nil, ErrRefreshTokenInvalid
+		}
+	}
+	if token.RevokeTime != nil {
+		_ = s.RevokeAll(token.UserID)
+		return nil, ErrRefreshTokenInvalid
+	}
+	if time.Now().After(token.ExpireTime) {
+		return nil, ErrRefreshTokenInvalid
+	}
+
+	user, err := s.userRepo.FindByID(token.UserID)
+	if err != nil || user.Status != 1 {
+		return nil, ErrRefreshTokenInvalid
+	}
+
+	// 并发刷新同一令牌时只有一方成功
+	revoked, err := s.tokenRepo.Revoke(token.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !revoked {
+		return nil, ErrRefreshTokenInvalid
+	}
+
+	// 升级前签发的刷新令牌没有关联会话，刷新时补建
+	if session == nil {
+		if session, err = s.newSession(user.ID, client); err != nil {
+			return nil, err
+		}
+	} else {
+		session.ClientIP = truncate(client.ClientIP, 64)
+		session.UserAgent = truncate(client.UserAgent, 255)
+	}
+	return s.issueTokens(user, session)
+}
+
+// Logout 退出登录，吊销刷新令牌所属的登录会话
+// refreshToken 无效时视为已退出，不返回错误。
+func (s *AuthService) Logout(refreshToken string) error {
+	token, err := s.tokenRepo.FindByHash(hashToken(refreshToken))
+	if err != nil {
+		return nil
+	}
+	if token.SessionID == 0 {
+		_, err = s.tokenRepo.Revoke(token.ID)
+		return err
+	}
+	return s.revokeSession(token.SessionID)
+}
+
+// RevokeAll 吊销用户的全部登录会话与刷新令牌，并使已签发的访问令牌立即失效
+func (s *AuthService) RevokeAll(userID int64) error {
+	if err := s.tokenRepo.RevokeAllByUser(userID); err != nil {
+		return err
+	}
+	if err := s.sessionRepo.RevokeAllByUser(userID); err != nil {
+		return err
+	}
+	return s.userRepo.IncrementTokenVersion(userID)
+}
+
+// CheckAccessToken 校验访问令牌是否仍然有效，返回令牌所属的登录会话
+// 用户不存在、已禁用、令牌版本已变更或会话已被吊销时返回 ErrTokenRevoked。
+// 会话的最近活跃时间在此更新 (同一会话每分钟最多写一次)。
+// 密码已过期时返回会话的同时返回 ErrPasswordChangeRequired；
+// 用户被要求启用两步验证但尚未启用时返回 ErrTwoFactorSetupRequired，由调用方限制可访问的接口。
+func (s *AuthService) CheckAccessToken(claims *jwt.Claims) (*models.UserSession, error) {
+	user, err := s.userRepo.FindByID(claims.UserID)
+	if err != nil || user.Status != 1 || user.TokenVersion != claims.TokenVersion {
+		return nil, ErrTokenRevoked
+	}
+	session, err := s.sessionRepo.FindByTokenID(claims.ID)
+	if err != nil || session.RevokeTime != nil || session.UserID != user.ID {
+		return nil, ErrTokenRevoked
+	}
+
+	now := time.Now()
+	if now.Sub(session.LastSeenTime) >= sessionTouchInterval {
+		_ = s.sessionRepo.UpdateFields(session.ID, map[string]interface{}{"last_seen_time": now})
+		session.LastSeenTime = now
+	}
+	if s.passwordExpired(user) {
+		return session, ErrPasswordChangeRequired
+	}
+	if !user.TwoFactorEnabled && s.twoFactorRequired(user.Role) {
+		return session, ErrTwoFactorSetupRequired
+	}
+	return session, nil
+}
+
+// issueTokens 在登录会话上签发访问令牌和刷新令牌
+// 会话绑定新访问令牌的 jti，之前签发的访问令牌随之失效。
+func (s *AuthService) issueTokens(user *models.User, session *models.UserSession) (*dto.LoginResult, error) {
+	tokenID := uuid.NewString()
+	accessToken, err := jwt.GenerateToken(user.ID, user.Username, user.Role, user.TokenVersion, tokenID)
+	if err != nil {
+		return nil, errors.New("生成Token失败")
+	}
+
+	now := time.Now()
+	if err := s.sessionRepo.UpdateFields(session.ID, map[string]interface{}{
+		"token_id":       tokenID,
+		"client_ip":      session.ClientIP,
+		"user_agent":     session.UserAgent,
+		"last_seen_time": now,
+	}); err != nil {
+		return nil, err
+	}
+
+	raw, err := newOpaqueToken()
+	if err != nil {
+		return nil, errors.New("生成Token失败")
+	}
+	expiry := time.Duration(config.AppConfig.RefreshTokenExpiry) * time.Hour
+	if err := s.tokenRepo.Create(&models.RefreshToken{
+		UserID:     user.ID,
+		SessionID:  session.ID,
+		TokenHash:  hashToken(raw),
+		ExpireTime: time.Now().Add(expiry),
+	}); err != nil {
+		return nil, err
+	}
+
+	// 顺带清理过期令牌，失败不影响登录
+	_ = s.tokenRepo.DeleteExpired()
+
+	return &dto.LoginResult{
+		Token:        accessToken,
+		RefreshToken: raw,
+		ExpiresIn:    int64(jwt.TokenExpiry / time.Second),
+		User:         user,
+		Permissions:  s.roleService.PermissionCodes(user.Role),
+	}, nil
+}
+
+// newOpaqueToken 生成随机令牌 (32 字节，base64url 编码)，用于刷新令牌与登录挑战令牌
+func newOpaqueToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken 计算随机令牌的存储哈希
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// SigningKeys 获取访问令牌签名密钥列表 (管理员)
+func (s *AuthService) SigningKeys() ([]jwt.KeyInfo, error) {
+	return jwt.ListKeys()
+}
+
+// RotateSigningKey 立即轮换访问令牌签名密钥 (管理员)
+// 新令牌使用新密钥签名；旧密钥在访问令牌有效期内继续用于校验，已登录的用户不受影响。
+func (s *AuthService) RotateSigningKey(operatorID int64) (jwt.KeyInfo, error) {
+	key, err := jwt.RotateKey()
+	if err != nil {
+		return jwt.KeyInfo{}, err
+	}
+	s.auditService.RecordOperation(AuditSigningKeyRotated, operatorID, 0, key.Kid)
+	return key, nil
+}
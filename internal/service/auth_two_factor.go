@@ -0,0 +1,340 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/pkg/secret"
+	"github.com/FruitsAI/Orange/internal/pkg/totp"
+)
+
+// 两步验证参数
+const (
+	totpIssuer                = "Orange"        // 身份验证器中显示的发行方
+	recoveryCodeCount         = 10              // 每次生成的恢复码数量
+	loginChallengeTTL         = 5 * time.Minute // 登录挑战有效期
+	loginChallengeMaxAttempts = 5               // 登录挑战允许的失败次数
+)
+
+var (
+	// ErrTwoFactorCodeInvalid 两步验证码错误 (或已使用)
+	ErrTwoFactorCodeInvalid = errors.New("验证码错误")
+	// ErrLoginChallengeInvalid 登录挑战无效、过期或失败次数过多
+	ErrLoginChallengeInvalid = errors.New("验证已过期，请重新登录")
+	// ErrTwoFactorSetupRequired 当前角色被要求启用两步验证，但尚未启用
+	ErrTwoFactorSetupRequired = errors.New("请先启用两步验证")
+)
+
+// VerifyTwoFactorLogin 两步验证登录的第二步
+// 校验挑战令牌与验证码 (TOTP 或恢复码)，成功后创建登录会话并签发令牌。
+func (s *AuthService) VerifyTwoFactorLogin(challengeToken, code string, client dto.ClientInfo) (*dto.LoginResult, error) {
+	challenge, err := s.challengeRepo.FindByHash(hashToken(challengeToken))
+	if err != nil {
+		return nil, ErrLoginChallengeInvalid
+	}
+	if time.Now().After(challenge.ExpireTime) || challenge.Attempts >= loginChallengeMaxAttempts {
+		_, _ = s.challengeRepo.Delete(challenge.ID)
+		return nil, ErrLoginChallengeInvalid
+	}
+
+	user, err := s.userRepo.FindByID(challenge.UserID)
+	if err != nil || user.Status != 1 || !user.TwoFactorEnabled {
+		return nil, ErrLoginChallengeInvalid
+	}
+	if err := s.checkLoginThrottles(user, user.Username, client); err != nil {
+		return nil, err
+	}
+
+	if err := s.verifySecondFactor(user, code, true); err != nil {
+		if errors.Is(err, ErrTwoFactorCodeInvalid) {
+			_ = s.challengeRepo.IncrementAttempts(challenge.ID)
+			s.auditService.Record(AuditTwoFactorFailed, user.ID, user.Username, client, "")
+			// 验证码错误与密码错误一样计入账户与 IP 的失败次数，避免反复重新登录获取挑战来穷举验证码
+			if lockErr := s.countLoginFailure(user, user.Username, client); lockErr != nil {
+				_, _ = s.challengeRepo.Delete(challenge.ID)
+				return nil, lockErr
+			}
+		}
+		return nil, err
+	}
+
+	// 挑战令牌只能使用一次
+	deleted, err := s.challengeRepo.Delete(challenge.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !deleted {
+		return nil, ErrLoginChallengeInvalid
+	}
+
+	if client.DeviceName == "" {
+		client.DeviceName = challenge.DeviceName
+	}
+	return s.completeLogin(user, client)
+}
+
+// TwoFactorStatus 获取用户的两步验证状态
+func (s *AuthService) TwoFactorStatus(userID int64) (*dto.TwoFactorStatus, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("用户不存在")
+	}
+	remaining, err := s.recoveryRepo.CountUnused(userID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.TwoFactorStatus{
+		Enabled:                user.TwoFactorEnabled,
+		Required:               s.twoFactorRequired(user.Role),
+		RecoveryCodesRemaining: remaining,
+	}, nil
+}
+
+// SetupTwoFactor 生成待绑定的 TOTP 密钥
+// 密钥在提交首个验证码 (EnableTwoFactor) 之前不生效，重复调用会替换为新的密钥。
+func (s *AuthService) SetupTwoFactor(userID int64) (*dto.TwoFactorSetup, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("用户不存在")
+	}
+	if user.TwoFactorEnabled {
+		return nil, errors.New("已启用两步验证")
+	}
+
+	key, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := secret.Encrypt(key)
+	if err != nil {
+		return nil, fmt.Errorf("加密两步验证密钥失败: %w", err)
+	}
+	if err := s.userRepo.UpdateFields(userID, map[string]interface{}{
+		"totp_secret":    encrypted,
+		"totp_last_step": 0,
+	}); err != nil {
+		return nil, err
+	}
+
+	return &dto.TwoFactorSetup{
+		Secret:     key,
+		OtpauthURI: totp.URI(totpIssuer, user.Username, key),
+	}, nil
+}
+
+// EnableTwoFactor 校验首个验证码并启用两步验证，返回恢复码 (仅展示一次)
+func (s *AuthService) EnableTwoFactor(userID int64, code string) ([]string, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("用户不存在")
+	}
+	if user.TwoFactorEnabled {
+		return nil, errors.New("已启用两步验证")
+	}
+	if user.TOTPSecret == "" {
+		return nil, errors.New("请先获取绑定密钥")
+	}
+
+	if err := s.verifyTOTP(user, code); err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.UpdateFields(userID, map[string]interface{}{
+		"two_factor_enabled": true,
+	}); err != nil {
+		return nil, err
+	}
+	return s.generateRecoveryCodes(userID)
+}
+
+// DisableTwoFactor 关闭两步验证，需验证密码与验证码 (TOTP 或恢复码)
+func (s *AuthService) DisableTwoFactor(userID int64, pwd, code string) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return errors.New("用户不存在")
+	}
+	if !user.TwoFactorEnabled {
+		return errors.New("未启用两步验证")
+	}
+	if !s.verifyPassword(user, pwd) {
+		return errors.New("密码错误")
+	}
+	if s.twoFactorRequired(user.Role) {
+		return errors.New("管理员必须启用两步验证")
+	}
+
+	if err := s.verifySecondFactor(user, code, true); err != nil {
+		return err
+	}
+	return s.clearTwoFactor(userID)
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码，原有恢复码全部作废
+func (s *AuthService) RegenerateRecoveryCodes(userID int64, code string) ([]string, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("用户不存在")
+	}
+	if !user.TwoFactorEnabled {
+		return nil, errors.New("未启用两步验证")
+	}
+
+	if err := s.verifyTOTP(user, code); err != nil {
+		return nil, err
+	}
+	return s.generateRecoveryCodes(userID)
+}
+
+// ResetTwoFactor 重置用户的两步验证 (管理员，用于用户丢失身份验证器且恢复码用尽)
+func (s *AuthService) ResetTwoFactor(userID int64) error {
+	if _, err := s.userRepo.FindByID(userID); err != nil {
+		return errors.New("用户不存在")
+	}
+	return s.clearTwoFactor(userID)
+}
+
+// newLoginChallenge 密码验证通过后创建登录挑战
+func (s *AuthService) newLoginChallenge(userID int64, deviceName string) (*dto.LoginResult, error) {
+	raw, err := newOpaqueToken()
+	if err != nil {
+		return nil, errors.New("生成Token失败")
+	}
+	if err := s.challengeRepo.Create(&models.LoginChallenge{
+		UserID:     userID,
+		TokenHash:  hashToken(raw),
+		DeviceName: truncate(strings.TrimSpace(deviceName), 100),
+		ExpireTime: time.Now().Add(loginChallengeTTL),
+	}); err != nil {
+		return nil, err
+	}
+
+	// 顺带清理过期挑战，失败不影响登录
+	_ = s.challengeRepo.DeleteExpired()
+
+	return &dto.LoginResult{
+		TwoFactorRequired: true,
+		ChallengeToken:    raw,
+		ExpiresIn:         int64(loginChallengeTTL / time.Second),
+	}, nil
+}
+
+// verifySecondFactor 校验两步验证码，allowRecovery 为 true 时也接受恢复码
+func (s *AuthService) verifySecondFactor(user *models.User, code string, allowRecovery bool) error {
+	code = strings.TrimSpace(code)
+	if isTOTPCode(code) {
+		return s.verifyTOTP(user, code)
+	}
+	if !allowRecovery {
+		return ErrTwoFactorCodeInvalid
+	}
+
+	used, err := s.recoveryRepo.Use(user.ID, hashRecoveryCode(code))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrTwoFactorCodeInvalid
+	}
+	return nil
+}
+
+// verifyTOTP 校验 TOTP 验证码，同一时间步的验证码只能使用一次
+func (s *AuthService) verifyTOTP(user *models.User, code string) error {
+	key, err := secret.Decrypt(user.TOTPSecret)
+	if err != nil {
+		return fmt.Errorf("解密两步验证密钥失败: %w", err)
+	}
+	step, ok := totp.Validate(key, code, time.Now())
+	if !ok {
+		return ErrTwoFactorCodeInvalid
+	}
+
+	advanced, err := s.userRepo.AdvanceTOTPStep(user.ID, step)
+	if err != nil {
+		return err
+	}
+	if !advanced {
+		return ErrTwoFactorCodeInvalid
+	}
+	return nil
+}
+
+// generateRecoveryCodes 生成一组新的恢复码，仅保存哈希
+func (s *AuthService) generateRecoveryCodes(userID int64) ([]string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	records := make([]models.RecoveryCode, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		b := make([]byte, 5)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		raw := strings.ToLower(base32.StdEncoding.EncodeToString(b))
+		code := raw[:4] + "-" + raw[4:]
+		codes = append(codes, code)
+		records = append(records, models.RecoveryCode{UserID: userID, CodeHash: hashRecoveryCode(code)})
+	}
+	if err := s.recoveryRepo.Replace(userID, records); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// clearTwoFactor 关闭两步验证并删除密钥与恢复码
+func (s *AuthService) clearTwoFactor(userID int64) error {
+	if err := s.userRepo.UpdateFields(userID, map[string]interface{}{
+		"two_factor_enabled": false,
+		"totp_secret":        "",
+		"totp_last_step":     0,
+	}); err != nil {
+		return err
+	}
+	return s.recoveryRepo.DeleteByUser(userID)
+}
+
+// adminPermissions 管理类权限，拥有任一权限的角色视为管理员，受 require_admin_2fa 约束
+var adminPermissions = []string{PermUserManage, PermRoleManage, PermSettingManage}
+
+// twoFactorRequired 安全设置是否要求该角色启用两步验证
+// 读取设置失败时按不要求处理，避免设置异常导致管理员无法使用系统。
+func (s *AuthService) twoFactorRequired(role string) bool {
+	if !s.isAdminRole(role) {
+		return false
+	}
+	settings, err := s.settingService.GetSecurity()
+	return err == nil && settings.RequireAdminTwoFactor
+}
+
+// isAdminRole 角色是否拥有任一管理类权限
+func (s *AuthService) isAdminRole(role string) bool {
+	perms := s.roleService.RolePermissions(role)
+	for _, p := range adminPermissions {
+		if perms[p] {
+			return true
+		}
+	}
+	return false
+}
+
+// isTOTPCode 是否为 TOTP 验证码格式 (纯数字，位数与 totp.Digits 一致)
+func isTOTPCode(code string) bool {
+	if len(code) != totp.Digits {
+		return false
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// hashRecoveryCode 计算恢复码的存储哈希 (忽略大小写、空格与连字符)
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	return hashToken(normalized)
+}
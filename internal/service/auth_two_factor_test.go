@@ -0,0 +1,89 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/FruitsAI/Orange/internal/dto"
+)
+
+func TestTwoFactorRequiredByAdminPermissions(t *testing.T) {
+	settings := NewSettingService()
+	saved, err := settings.GetSecurity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer settings.UpdateSecurity(saved)
+	enabled := saved
+	enabled.RequireAdminTwoFactor = true
+	if err := settings.UpdateSecurity(enabled); err != nil {
+		t.Fatal(err)
+	}
+
+	// 自定义角色拥有管理类权限即受约束，与角色编码无关
+	if _, err := NewRoleService().Create(dto.RoleRequest{Code: "security_officer", Name: "安全管理员", Permissions: []string{PermRoleManage}}); err != nil {
+		t.Fatalf("创建角色失败: %v", err)
+	}
+	if _, err := NewRoleService().Create(dto.RoleRequest{Code: "auditor", Name: "审计员", Permissions: []string{PermAuditView}}); err != nil {
+		t.Fatalf("创建角色失败: %v", err)
+	}
+
+	auth := NewAuthService()
+	cases := map[string]bool{
+		RoleAdmin:          true,
+		"security_officer": true,
+		RoleUser:           false,
+		"auditor":          false,
+	}
+	for role, want := range cases {
+		if got := auth.twoFactorRequired(role); got != want {
+			t.Errorf("twoFactorRequired(%s) = %v，期望 %v", role, got, want)
+		}
+	}
+
+	// 关闭设置后不再要求
+	enabled.RequireAdminTwoFactor = false
+	if err := settings.UpdateSecurity(enabled); err != nil {
+		t.Fatal(err)
+	}
+	if auth.twoFactorRequired(RoleAdmin) {
+		t.Error("未开启设置时不应要求两步验证")
+	}
+}
+
+func TestTwoFactorRepeatedChallengesLockAccount(t *testing.T) {
+	setLoginLimits(t, 5)
+	user := createTwoFactorUser(t, "relogin_2fa", "Orange-2fa-pass")
+	auth := NewAuthService()
+	client := dto.ClientInfo{ClientIP: "192.0.2.13"}
+
+	// 密码正确不清空失败计数: 每个挑战只试 2 次，反复重新登录仍会在累计 5 次后锁定
+	failures := 0
+	for round := 0; round < 3 && failures < 5; round++ {
+		result, err := auth.Login("relogin_2fa", "Orange-2fa-pass", client)
+		if err != nil {
+			t.Fatalf("第 %d 轮登录: %v", round+1, err)
+		}
+		for i := 0; i < 2 && failures < 5; i++ {
+			_, err := auth.VerifyTwoFactorLogin(result.ChallengeToken, "wrong-code", client)
+			failures++
+			var throttled *LoginThrottledError
+			if locked := errors.As(err, &throttled) && throttled.Locked; locked != (failures == 5) {
+				t.Fatalf("累计第 %d 次错误: %v", failures, err)
+			}
+		}
+	}
+
+	var throttled *LoginThrottledError
+	if _, err := auth.Login("relogin_2fa", "Orange-2fa-pass", client); !errors.As(err, &throttled) || !throttled.Locked {
+		t.Fatalf("锁定后登录: 期望锁定错误，实际 %v", err)
+	}
+
+	// 管理员解锁后可以重新登录
+	if err := auth.UnlockUser(user.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := auth.Login("relogin_2fa", "Orange-2fa-pass", client); err != nil {
+		t.Errorf("解锁后登录: %v", err)
+	}
+}
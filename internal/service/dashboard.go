@@ -33,10 +33,10 @@ func NewDashboardService() *DashboardService {
 }
 
 // GetStats 获取仪表盘核心统计数据
-// 根据指定的用户ID和时间周期，计算总金额、已收款、待收款、逾期金额及各项数据的环比趋势。
+// 根据当前用户的数据范围和时间周期，计算总金额、已收款、待收款、逾期金额及各项数据的环比趋势。
 //
 // 参数:
-//   - userID: 当前登录用户的ID
+//   - scope: 当前用户的数据范围 (由角色的数据范围解析得到)
 //   - period: 统计周期，可选值: "week"(本周), "month"(本月), "quarter"(本季度), "year"(本年), "all"(全部/全局)
 //
 // 返回:
@@ -46,20 +46,20 @@ func NewDashboardService() *DashboardService {
 // 说明:
 //   - 当 period 为 "all" 或空字符串时，返回全局统计数据（基于项目合同总额），此时不计算趋势（趋势值为0）。
 //   - 其他周期模式下，统计数据基于实际产生的款项（Payment）计算，并会计算与上一周期的环比趋势。
-func (s *DashboardService) GetStats(userID int64, period string) (*dto.Stats, error) {
+func (s *DashboardService) GetStats(scope repository.DataScope, period string) (*dto.Stats, error) {
 	// 模式 1: 全局统计模式（通常用于工作台概览）
 	// 当未指定周期或周期为 "all" 时触发
 	if period == "all" || period == "" {
 		// 核心逻辑: 从 Project 表获取基于合同金额的宏观统计
 		// 也就是所有项目的总合同额、已收和待收
-		totalAmount, paidAmount, pendingAmount, err := s.projectRepo.GetStats(userID)
+		totalAmount, paidAmount, pendingAmount, err := s.projectRepo.GetStats(scope)
 		if err != nil {
 			return nil, err
 		}
 
 		// 补充逻辑: 计算逾期金额
 		// 逾期金额需要基于 Payment 表中具体款项的截止日期来判断
-		overdueAmount := s.paymentRepo.SumOverdue(userID)
+		overdueAmount := s.paymentRepo.SumOverdue(scope)
 
 		// ---------------------------------------------------------------------
 		// 优化: 计算趋势 (Trend)
@@ -77,7 +77,7 @@ func (s *DashboardService) GetStats(userID int64, period string) (*dto.Stats, er
 		prevEndDate := now.AddDate(0, 0, -30).Format("2006-01-02") + " 23:59:59"
 
 		// 2. 获取本月统计作为 "当前周期值" (只用于计算 Trend)
-		currTotal, currPaid, currPending, currOverdue, currAvgDays, err := s.paymentRepo.GetStatsByPeriod(userID, startDate, endDate)
+		currTotal, currPaid, currPending, currOverdue, currAvgDays, err := s.paymentRepo.GetStatsByPeriod(scope, startDate, endDate)
 		if err != nil {
 			return nil, err
 		}
@@ -86,7 +86,7 @@ func (s *DashboardService) GetStats(userID int64, period string) (*dto.Stats, er
 		// 用户的需求是 "逾期金额也要计算"。
 
 		// 3. 获取上月统计作为 "上一周期值"
-		prevTotal, prevPaid, prevPending, prevOverdue, prevAvgDays, err := s.paymentRepo.GetStatsByPeriod(userID, prevStartDate, prevEndDate)
+		prevTotal, prevPaid, prevPending, prevOverdue, prevAvgDays, err := s.paymentRepo.GetStatsByPeriod(scope, prevStartDate, prevEndDate)
 		if err != nil {
 			return nil, err
 		}
@@ -159,16 +159,16 @@ func (s *DashboardService) GetStats(userID int64, period string) (*dto.Stats, er
 	}
 
 	// 步骤 1: 获取当前周期的各项统计指标
-	currTotal, currPaid, currPending, currOverdue, currAvgDays, err := s.paymentRepo.GetStatsByPeriod(userID, startDate, endDate)
+	currTotal, currPaid, currPending, currOverdue, currAvgDays, err := s.paymentRepo.GetStatsByPeriod(scope, startDate, endDate)
 	if err != nil {
 		return nil, err
 	}
 	// 获取当前的逾期总额（逾期是一个状态值，通常通过快照获取，但此处简单处理为当前总逾期）
-	// 注意：在 "all" 模式下，主数值 OverdueAmount 依然使用 SumOverdue(userID) 全量计算
+	// 注意：在 "all" 模式下，主数值 OverdueAmount 依然使用 SumOverdue(scope) 全量计算
 	// 而 currOverdue 仅用于计算趋势 (本周期内产生的逾期)
 
 	// 步骤 2: 获取上一周期的各项统计指标（用于对比）
-	prevTotal, prevPaid, prevPending, prevOverdue, prevAvgDays, err := s.paymentRepo.GetStatsByPeriod(userID, prevStartDate, prevEndDate)
+	prevTotal, prevPaid, prevPending, prevOverdue, prevAvgDays, err := s.paymentRepo.GetStatsByPeriod(scope, prevStartDate, prevEndDate)
 	if err != nil {
 		return nil, err
 	}
@@ -204,13 +204,13 @@ func (s *DashboardService) GetStats(userID int64, period string) (*dto.Stats, er
 // 根据指定的时间段返回用于绘制折线图的标签和数值。
 //
 // 参数:
-//   - userID: 用户ID
+//   - scope: 当前用户的数据范围
 //   - period: 时间维度，"week"和"month"按天聚合，"quarter"和"year"按月聚合
 //
 // 返回:
 //   - *dto.IncomeTrend: 包含 Labels (X轴), ActualValues (实际收入), ExpectedValues (预计收入)
 //   - error: 错误信息
-func (s *DashboardService) GetIncomeTrend(userID int64, period string) (*dto.IncomeTrend, error) {
+func (s *DashboardService) GetIncomeTrend(scope repository.DataScope, period string) (*dto.IncomeTrend, error) {
 	now := time.Now()
 	var startDate, endDate string
 	var interval string     // 聚合粒度: "day" 或 "month"
@@ -263,7 +263,7 @@ func (s *DashboardService) GetIncomeTrend(userID int64, period string) (*dto.Inc
 	}
 
 	// 从数据库查询聚合好的收入数据（Map形式）
-	expected, actual, err := s.paymentRepo.GetIncomeStats(userID, startDate, endDate, interval)
+	expected, actual, err := s.paymentRepo.GetIncomeStats(scope, startDate, endDate, interval)
 	if err != nil {
 		return nil, err
 	}
@@ -307,25 +307,25 @@ func (s *DashboardService) GetIncomeTrend(userID int64, period string) (*dto.Inc
 // 用于仪表盘"最近项目"列表展示。
 //
 // 参数:
-//   - userID: 用户ID
+//   - scope: 当前用户的数据范围
 //
 // 返回:
 //   - []models.Project: 项目列表切片
 //   - error: 错误信息
-func (s *DashboardService) GetRecentProjects(userID int64) ([]models.Project, error) {
-	return s.projectRepo.ListRecent(userID, 5)
+func (s *DashboardService) GetRecentProjects(scope repository.DataScope) ([]models.Project, error) {
+	return s.projectRepo.ListRecent(scope, 5)
 }
 
 // GetUpcomingPayments 获取即将到期的款项
 // 查询未来7天内到期的待收款项，最多返回5条。
 //
 // 参数:
-//   - userID: 用户ID
+//   - scope: 当前用户的数据范围
 //
 // 返回:
 //   - []models.Payment: 款项列表切片
 //   - error: 错误信息
-func (s *DashboardService) GetUpcomingPayments(userID int64) ([]models.Payment, error) {
-	// 参数说明: ListUpcoming(userID, days=7, limit=5)
-	return s.paymentRepo.ListUpcoming(userID, 7, 5)
+func (s *DashboardService) GetUpcomingPayments(scope repository.DataScope) ([]models.Payment, error) {
+	// 参数说明: ListUpcoming(scope, days=7, limit=5)
+	return s.paymentRepo.ListUpcoming(scope, 7, 5)
 }
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"github.com/FruitsAI/Orange/internal/repository"
+)
+
+// 数据范围 (roles.data_scope)，决定角色可访问哪些用户创建的项目与收款
+const (
+	DataScopeSelf                  = "self"                    // 仅本人
+	DataScopeDepartment            = "department"              // 本部门
+	DataScopeDepartmentAndChildren = "department_and_children" // 本部门及下级部门
+	DataScopeAll                   = "all"                     // 全部数据
+)
+
+// validDataScopes 可用的数据范围
+var validDataScopes = map[string]bool{
+	DataScopeSelf:                  true,
+	DataScopeDepartment:            true,
+	DataScopeDepartmentAndChildren: true,
+	DataScopeAll:                   true,
+}
+
+// DataScopeService 数据范围服务
+// 按当前用户的角色与所属部门解析出可访问的用户集合，供项目、收款与仪表盘查询使用。
+//
+// 依赖:
+//   - RoleService: 角色的数据范围 (带缓存)
+//   - UserRepository: 用户所属部门
+//   - DepartmentRepository: 部门树与部门成员
+type DataScopeService struct {
+	roleService    *RoleService
+	userRepo       *repository.UserRepository
+	departmentRepo *repository.DepartmentRepository
+}
+
+// NewDataScopeService 创建数据范围服务实例
+func NewDataScopeService() *DataScopeService {
+	return &DataScopeService{
+		roleService:    NewRoleService(),
+		userRepo:       repository.NewUserRepository(),
+		departmentRepo: repository.NewDepartmentRepository(),
+	}
+}
+
+// Resolve 解析用户的数据范围
+// 未分配部门的用户按部门范围解析时仅包含本人；读取失败时同样退回仅本人，不会扩大可见范围。
+func (s *DataScopeService) Resolve(userID int64, role string) repository.DataScope {
+	scope := s.roleService.RoleDataScope(role)
+	if scope == DataScopeAll {
+		return repository.DataScope{All: true}
+	}
+	if scope == DataScopeSelf {
+		return repository.SelfScope(userID)
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil || user.DepartmentID == 0 {
+		return repository.SelfScope(userID)
+	}
+	departments := []int64{user.DepartmentID}
+	if scope == DataScopeDepartmentAndChildren {
+		if departments, err = departmentSubtree(s.departmentRepo, user.DepartmentID); err != nil {
+			return repository.SelfScope(userID)
+		}
+	}
+	ids, err := s.departmentRepo.ListUserIDs(departments)
+	if err != nil {
+		return repository.SelfScope(userID)
+	}
+
+	result := repository.DataScope{UserIDs: ids}
+	if !result.Contains(userID) {
+		result.UserIDs = append(result.UserIDs, userID)
+	}
+	return result
+}
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrDepartmentNotFound 部门不存在
+	ErrDepartmentNotFound = errors.New("部门不存在")
+	// ErrDepartmentHasChildren 部门仍有下级部门
+	ErrDepartmentHasChildren = errors.New("请先删除或移走下级部门")
+	// ErrDepartmentInUse 部门仍有成员
+	ErrDepartmentInUse = errors.New("部门仍有成员，请先调整这些用户的部门")
+)
+
+// DepartmentService 部门服务
+// 部门以 parent_id 组成树，用户通过 department_id 归属部门 (users.department 同步保存部门名称便于展示)。
+//
+// 依赖:
+//   - DepartmentRepository: 部门的读写
+//   - UserRepository: 部门负责人
+type DepartmentService struct {
+	departmentRepo *repository.DepartmentRepository
+	userRepo       *repository.UserRepository
+}
+
+// NewDepartmentService 创建部门服务实例
+func NewDepartmentService() *DepartmentService {
+	return &DepartmentService{
+		departmentRepo: repository.NewDepartmentRepository(),
+		userRepo:       repository.NewUserRepository(),
+	}
+}
+
+// Tree 获取部门树 (含负责人姓名与直属成员数)
+func (s *DepartmentService) Tree() ([]dto.DepartmentNode, error) {
+	departments, err := s.departmentRepo.List()
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.departmentRepo.CountUsers()
+	if err != nil {
+		return nil, err
+	}
+	managerIDs := make([]int64, 0, len(departments))
+	for _, d := range departments {
+		if d.ManagerID > 0 {
+			managerIDs = append(managerIDs, d.ManagerID)
+		}
+	}
+	managers, err := s.userRepo.FindByIDs(managerIDs)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[int64]string, len(managers))
+	for _, u := range managers {
+		names[u.ID] = u.Name
+	}
+
+	// 按上级分组后自顶向下组装 (上级不存在的部门视为顶级部门)
+	exists := make(map[int64]bool, len(departments))
+	for _, d := range departments {
+		exists[d.ID] = true
+	}
+	children := make(map[int64][]models.Department, len(departments))
+	for _, d := range departments {
+		parent := d.ParentID
+		if !exists[parent] {
+			parent = 0
+		}
+		children[parent] = append(children[parent], d)
+	}
+	var build func(parentID int64, seen map[int64]bool) []dto.DepartmentNode
+	build = func(parentID int64, seen map[int64]bool) []dto.DepartmentNode {
+		nodes := []dto.DepartmentNode{}
+		for _, d := range children[parentID] {
+			if seen[d.ID] {
+				continue
+			}
+			seen[d.ID] = true
+			nodes = append(nodes, dto.DepartmentNode{
+				Department:  d,
+				ManagerName: names[d.ManagerID],
+				UserCount:   counts[d.ID],
+				Children:    build(d.ID, seen),
+			})
+		}
+		return nodes
+	}
+	return build(0, map[int64]bool{}), nil
+}
+
+// Create 创建部门
+func (s *DepartmentService) Create(input dto.DepartmentRequest) (*models.Department, error) {
+	if err := s.checkInput(input, 0); err != nil {
+		return nil, err
+	}
+	dept := &models.Department{Name: input.Name, ParentID: input.ParentID, ManagerID: input.ManagerID, Sort: input.Sort}
+	if err := s.departmentRepo.Create(dept); err != nil {
+		return nil, err
+	}
+	return dept, nil
+}
+
+// Update 更新部门 (上级部门不能是自身或其下级部门)
+func (s *DepartmentService) Update(id int64, input dto.DepartmentRequest) (*models.Department, error) {
+	dept, err := s.find(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkInput(input, id); err != nil {
+		return nil, err
+	}
+	if input.ParentID > 0 {
+		subtree, err := departmentSubtree(s.departmentRepo, id)
+		if err != nil {
+			return nil, err
+		}
+		for _, child := range subtree {
+			if child == input.ParentID {
+				return nil, errors.New("上级部门不能是自身或其下级部门")
+			}
+		}
+	}
+
+	dept.Name = input.Name
+	dept.ParentID = input.ParentID
+	dept.ManagerID = input.ManagerID
+	dept.Sort = input.Sort
+	if err := s.departmentRepo.Update(dept); err != nil {
+		return nil, err
+	}
+	return dept, nil
+}
+
+// Delete 删除部门 (仍有下级部门或成员时不可删除)
+func (s *DepartmentService) Delete(id int64) error {
+	if _, err := s.find(id); err != nil {
+		return err
+	}
+	children, err := s.departmentRepo.CountChildren(id)
+	if err != nil {
+		return err
+	}
+	if children > 0 {
+		return ErrDepartmentHasChildren
+	}
+	counts, err := s.departmentRepo.CountUsers()
+	if err != nil {
+		return err
+	}
+	if counts[id] > 0 {
+		return ErrDepartmentInUse
+	}
+	return s.departmentRepo.Delete(id)
+}
+
+// find 查找部门，不存在时返回 ErrDepartmentNotFound
+func (s *DepartmentService) find(id int64) (*models.Department, error) {
+	dept, err := s.departmentRepo.FindByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrDepartmentNotFound
+	}
+	return dept, err
+}
+
+// checkInput 校验上级部门、负责人与名称
+func (s *DepartmentService) checkInput(input dto.DepartmentRequest, excludeID int64) error {
+	if input.ParentID > 0 {
+		if _, err := s.find(input.ParentID); err != nil {
+			if errors.Is(err, ErrDepartmentNotFound) {
+				return errors.New("上级部门不存在")
+			}
+			return err
+		}
+	}
+	if input.ManagerID > 0 {
+		if _, err := s.userRepo.FindByID(input.ManagerID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("负责人不存在")
+			}
+			return err
+		}
+	}
+	if s.departmentRepo.ExistsByName(input.ParentID, input.Name, excludeID) {
+		return errors.New("同一上级部门下已存在同名部门")
+	}
+	return nil
+}
+
+// departmentSubtree 获取部门及其全部下级部门的ID (逐层展开，忽略成环的数据)
+func departmentSubtree(repo *repository.DepartmentRepository, departmentID int64) ([]int64, error) {
+	ids := []int64{departmentID}
+	seen := map[int64]bool{departmentID: true}
+	level := []int64{departmentID}
+	for len(level) > 0 {
+		children, err := repo.ListChildIDs(level)
+		if err != nil {
+			return nil, err
+		}
+		var next []int64
+		for _, id := range children {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+				next = append(next, id)
+			}
+		}
+		level = next
+	}
+	return ids, nil
+}
+
+// departmentName 获取部门名称 (ID 为 0 表示未分配，返回空字符串)
+func departmentName(repo *repository.DepartmentRepository, id int64) (string, error) {
+	if id == 0 {
+		return "", nil
+	}
+	dept, err := repo.FindByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", ErrDepartmentNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	return dept.Name, nil
+}
+
+// departmentUpdates 将部门名称写入待更新字段，并同步所属部门ID (部门不存在时创建为顶级部门)
+// 用于 LDAP、单点登录按组映射得到的部门名称。
+func departmentUpdates(repo *repository.DepartmentRepository, updates map[string]interface{}, name string) error {
+	id, err := repo.EnsureByName(name)
+	if err != nil {
+		return err
+	}
+	updates["department"] = name
+	updates["department_id"] = id
+	return nil
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/config"
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/pkg/jwt"
+	"github.com/FruitsAI/Orange/internal/pkg/secret"
+)
+
+// TestMain 使用临时目录中的 SQLite 数据库运行服务层测试
+// 数据库连接为进程内单例，各测试共用同一个库，需自行创建互不冲突的数据。
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "orange-service-test")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	os.Setenv("DB_TYPE", "sqlite")
+	os.Setenv("DB_PATH", filepath.Join(dir, "orange.db"))
+	os.Setenv("DATA_DIR", dir)
+	config.Load()
+	secret.KeyFile = filepath.Join(dir, "secret.key")
+	jwt.KeyDir = filepath.Join(dir, "jwt-keys")
+	jwt.TokenExpiry = time.Hour
+	if err := jwt.LoadKeys(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	db := database.GetDB()
+	if err := database.Migrate(db); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	code := m.Run()
+	database.Close()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// createTestUser 创建指定角色的正常状态用户
+func createTestUser(t *testing.T, username, role string) *models.User {
+	t.Helper()
+	user := &models.User{Username: username, Password: "-", Name: username, Role: role, Status: 1, AuthSource: "local"}
+	if err := database.GetDB().Create(user).Error; err != nil {
+		t.Fatalf("创建用户 %s 失败: %v", username, err)
+	}
+	return user
+}
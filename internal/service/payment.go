@@ -1,6 +1,7 @@
 package service
 
 import (
+	"errors"
 	"time"
 
 	"github.com/FruitsAI/Orange/internal/database"
@@ -13,12 +14,13 @@ import (
 
 // PaymentService 款项(回款)服务
 // 负责处理所有与款项相关的业务逻辑，包括生成收款计划、更新收款状态、
-// 执行回款确认事务以及自动计算回款百分比。
+// 执行回款确认事务以及自动计算回款百分比。按项目或款项 ID 操作时校验操作者的项目角色。
 //
 // 依赖:
 //   - PaymentRepository: 款项数据操作
 //   - ProjectRepository: 项目数据操作 (用于更新项目总已收金额)
 type PaymentService struct {
+	access      projectAccess
 	paymentRepo *repository.PaymentRepository
 	projectRepo *repository.ProjectRepository
 }
@@ -29,6 +31,7 @@ type PaymentService struct {
 //   - *PaymentService: 初始化的服务实例
 func NewPaymentService() *PaymentService {
 	return &PaymentService{
+		access:      newProjectAccess(),
 		paymentRepo: repository.NewPaymentRepository(),
 		projectRepo: repository.NewProjectRepository(),
 	}
@@ -39,53 +42,62 @@ func NewPaymentService() *PaymentService {
 //
 // 参数:
 //   - projectID: 项目ID
+//   - actor: 操作者，须可查看该项目
 //
 // 返回:
 //   - []models.Payment: 款项列表
-//   - error: 数据库查询错误
-func (s *PaymentService) ListByProject(projectID int64) ([]models.Payment, error) {
+//   - error: 无权查看或数据库查询错误
+func (s *PaymentService) ListByProject(projectID int64, actor ProjectActor) ([]models.Payment, error) {
+	if _, _, err := s.access.authorize(projectID, actor, projectActionView); err != nil {
+		return nil, err
+	}
 	return s.paymentRepo.ListByProject(projectID)
 }
 
-// ListUpcoming 获取指定用户近期即将到期的待收款项 (Dashboard用)
+// ListUpcoming 获取数据范围内近期即将到期的待收款项 (Dashboard用)
 // 通常用于首页"即将收款"卡片，提醒用户关注近期回款。
 //
 // 参数:
-//   - userID: 用户ID
+//   - scope: 当前用户的数据范围
 //   - days: 未来多少天内 (如 7天)
 //   - limit: 最大返回数量 (如 5条)
 //
 // 返回:
 //   - []models.Payment: 即将到期的款项列表
 //   - error: 数据库查询错误
-func (s *PaymentService) ListUpcoming(userID int64, days, limit int) ([]models.Payment, error) {
-	return s.paymentRepo.ListUpcoming(userID, days, limit)
+func (s *PaymentService) ListUpcoming(scope repository.DataScope, days, limit int) ([]models.Payment, error) {
+	return s.paymentRepo.ListUpcoming(scope, days, limit)
 }
 
 // ListByDateRange 获取指定日期范围内的所有款项记录 (报表/日历用)
 // 包含起始日期和结束日期（闭区间）。
 //
 // 参数:
-//   - userID: 用户ID
+//   - scope: 当前用户的数据范围
 //   - startDate: 开始日期 "YYYY-MM-DD"
 //   - endDate: 结束日期 "YYYY-MM-DD"
 //
 // 返回:
 //   - []models.Payment: 范围内的款项列表
 //   - error: 数据库查询错误
-func (s *PaymentService) ListByDateRange(userID int64, startDate, endDate string) ([]models.Payment, error) {
-	return s.paymentRepo.ListByDateRange(userID, startDate, endDate)
+func (s *PaymentService) ListByDateRange(scope repository.DataScope, startDate, endDate string) ([]models.Payment, error) {
+	return s.paymentRepo.ListByDateRange(scope, startDate, endDate)
 }
 
 // Create 创建新的收款/回款计划
 //
 // 参数:
 //   - input: 收款请求DTO
+//   - actor: 操作者，须为项目负责人、编辑或财务成员
 //
 // 返回:
 //   - *models.Payment: 创建成功的款项实体
-//   - error: 业务规则校验失败或数据库错误
-func (s *PaymentService) Create(input dto.PaymentRequest) (*models.Payment, error) {
+//   - error: 无权操作、业务规则校验失败或数据库错误
+func (s *PaymentService) Create(input dto.PaymentRequest, actor ProjectActor) (*models.Payment, error) {
+	if _, _, err := s.access.authorize(input.ProjectID, actor, projectActionPayment); err != nil {
+		return nil, err
+	}
+
 	planDate, err := time.Parse("2006-01-02", input.PlanDate)
 	if err != nil {
 		return nil, err
@@ -129,16 +141,20 @@ func (s *PaymentService) Create(input dto.PaymentRequest) (*models.Payment, erro
 //
 // 参数:
 //   - id: 款项ID
-//   - input: 更新内容
+//   - input: 更新内容 (所属项目不可修改)
+//   - actor: 操作者，须为项目负责人、编辑或财务成员
 //
 // 返回:
 //   - *models.Payment: 更新后的实体
-//   - error: 更新失败
-func (s *PaymentService) Update(id int64, input dto.PaymentRequest) (*models.Payment, error) {
-	payment, err := s.paymentRepo.FindByID(id)
+//   - error: 收款不存在、无权操作、修改所属项目或更新失败
+func (s *PaymentService) Update(id int64, input dto.PaymentRequest, actor ProjectActor) (*models.Payment, error) {
+	payment, err := s.access.authorizePayment(id, actor, projectActionPayment)
 	if err != nil {
 		return nil, err
 	}
+	if input.ProjectID != payment.ProjectID {
+		return nil, ErrPaymentProjectChanged
+	}
 
 	planDate, err := time.Parse("2006-01-02", input.PlanDate)
 	if err != nil {
@@ -226,8 +242,11 @@ func (s *PaymentService) syncProjectReceivedAmount(projectID int64) error {
 	return nil
 }
 
-// Delete 删除收款
-func (s *PaymentService) Delete(id int64) error {
+// Delete 删除收款 (操作者须为项目负责人、编辑或财务成员)
+func (s *PaymentService) Delete(id int64, actor ProjectActor) error {
+	if _, err := s.access.authorizePayment(id, actor, projectActionPayment); err != nil {
+		return err
+	}
 	return s.paymentRepo.Delete(id)
 }
 
@@ -245,14 +264,22 @@ func (s *PaymentService) Delete(id int64) error {
 //   - id: 款项ID
 //   - actualDate: 实际收款日期字符串
 //   - method: 收款方式 (如 银行转账, 支付宝)
+//   - actor: 操作者，须为项目负责人、编辑或财务成员
 //
 // 返回:
-//   - error: 事务执行失败
-func (s *PaymentService) Confirm(id int64, actualDate, method string) error {
+//   - error: 无权操作或事务执行失败
+func (s *PaymentService) Confirm(id int64, actualDate, method string, actor ProjectActor) error {
+	if _, err := s.access.authorizePayment(id, actor, projectActionPayment); err != nil {
+		return err
+	}
+
 	return database.GetDB().Transaction(func(tx *gorm.DB) error {
-		// 1. 锁定并获取当前收款记录 (防止并发修改)
+		// 1. 锁定并获取当前收款记录 (防止并发修改)，校验后被删除时同样视为不存在
 		var payment models.Payment
 		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&payment, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrPaymentNotFound
+			}
 			return err
 		}
 
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrPositionNotFound 职位不存在
+	ErrPositionNotFound = errors.New("职位不存在")
+	// ErrPositionInUse 职位仍有用户担任
+	ErrPositionInUse = errors.New("仍有用户担任该职位，请先调整这些用户的职位")
+)
+
+// PositionService 职位服务
+// 用户通过 position_id 引用职位 (users.position 同步保存职位名称便于展示)。
+//
+// 依赖:
+//   - PositionRepository: 职位的读写
+type PositionService struct {
+	positionRepo *repository.PositionRepository
+}
+
+// NewPositionService 创建职位服务实例
+func NewPositionService() *PositionService {
+	return &PositionService{
+		positionRepo: repository.NewPositionRepository(),
+	}
+}
+
+// List 获取全部职位 (含使用人数)
+func (s *PositionService) List() ([]dto.PositionView, error) {
+	positions, err := s.positionRepo.List()
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.positionRepo.CountUsers()
+	if err != nil {
+		return nil, err
+	}
+	views := make([]dto.PositionView, 0, len(positions))
+	for _, p := range positions {
+		views = append(views, dto.PositionView{Position: p, UserCount: counts[p.ID]})
+	}
+	return views, nil
+}
+
+// Create 创建职位
+func (s *PositionService) Create(input dto.PositionRequest) (*models.Position, error) {
+	if s.positionRepo.ExistsByName(input.Name, 0) {
+		return nil, errors.New("职位名称已存在")
+	}
+	position := &models.Position{Name: input.Name, Description: input.Description, Sort: input.Sort}
+	if err := s.positionRepo.Create(position); err != nil {
+		return nil, err
+	}
+	return position, nil
+}
+
+// Update 更新职位
+func (s *PositionService) Update(id int64, input dto.PositionRequest) (*models.Position, error) {
+	position, err := s.find(id)
+	if err != nil {
+		return nil, err
+	}
+	if s.positionRepo.ExistsByName(input.Name, id) {
+		return nil, errors.New("职位名称已存在")
+	}
+
+	position.Name = input.Name
+	position.Description = input.Description
+	position.Sort = input.Sort
+	if err := s.positionRepo.Update(position); err != nil {
+		return nil, err
+	}
+	return position, nil
+}
+
+// Delete 删除职位 (仍有用户担任时不可删除)
+func (s *PositionService) Delete(id int64) error {
+	if _, err := s.find(id); err != nil {
+		return err
+	}
+	counts, err := s.positionRepo.CountUsers()
+	if err != nil {
+		return err
+	}
+	if counts[id] > 0 {
+		return ErrPositionInUse
+	}
+	return s.positionRepo.Delete(id)
+}
+
+// find 查找职位，不存在时返回 ErrPositionNotFound
+func (s *PositionService) find(id int64) (*models.Position, error) {
+	position, err := s.positionRepo.FindByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrPositionNotFound
+	}
+	return position, err
+}
+
+// positionName 获取职位名称 (ID 为 0 表示未分配，返回空字符串)
+func positionName(repo *repository.PositionRepository, id int64) (string, error) {
+	if id == 0 {
+		return "", nil
+	}
+	position, err := repo.FindByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", ErrPositionNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	return position.Name, nil
+}
+
+// positionUpdates 将职位名称写入待更新字段，并同步职位ID (职位不存在时自动创建)
+// 用于 LDAP、单点登录按组映射得到的职位名称。
+func positionUpdates(repo *repository.PositionRepository, updates map[string]interface{}, name string) error {
+	id, err := repo.EnsureByName(name)
+	if err != nil {
+		return err
+	}
+	updates["position"] = name
+	updates["position_id"] = id
+	return nil
+}
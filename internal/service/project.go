@@ -13,12 +13,13 @@ import (
 
 // ProjectService 项目服务
 // 负责处理项目管理模块的所有核心业务逻辑，包括项目的增删改查、状态管理、
-// 合同编号生成以及相关的款项级联操作。
+// 合同编号生成以及相关的款项级联操作。按 ID 操作项目时校验操作者的项目角色。
 //
 // 依赖:
 //   - ProjectRepository: 项目数据持久化接口
 //   - PaymentRepository: 款项数据持久化接口
 type ProjectService struct {
+	access      projectAccess
 	projectRepo *repository.ProjectRepository
 	paymentRepo *repository.PaymentRepository
 }
@@ -29,16 +30,17 @@ type ProjectService struct {
 //   - *ProjectService: 包含已初始化 Repository 的服务实例
 func NewProjectService() *ProjectService {
 	return &ProjectService{
+		access:      newProjectAccess(),
 		projectRepo: repository.NewProjectRepository(),
 		paymentRepo: repository.NewPaymentRepository(),
 	}
 }
 
 // List 分页获取项目列表
-// 支持根据用户ID、项目状态和关键词进行过滤查询。
+// 支持根据数据范围、项目状态和关键词进行过滤查询。
 //
 // 参数:
-//   - userID: 当前用户ID，强制数据隔离
+//   - scope: 当前用户的数据范围，强制数据隔离
 //   - status: 项目状态筛选 (如 "active", "completed", "archived")，为空则查全部
 //   - keyword: 搜索关键词，支持匹配项目名称、公司名或合同编号
 //   - page: 页码，从1开始
@@ -47,7 +49,7 @@ func NewProjectService() *ProjectService {
 // 返回:
 //   - *dto.ProjectListResult: 包含项目列表数据、总数及分页信息
 //   - error: 数据库查询错误
-func (s *ProjectService) List(userID int64, status, keyword string, page, pageSize int) (*dto.ProjectListResult, error) {
+func (s *ProjectService) List(scope repository.DataScope, status, keyword string, page, pageSize int) (*dto.ProjectListResult, error) {
 	// 参数校验与默认值填充
 	if page <= 0 {
 		page = 1
@@ -57,7 +59,7 @@ func (s *ProjectService) List(userID int64, status, keyword string, page, pageSi
 	}
 
 	// 执行查询
-	projects, total, err := s.projectRepo.List(userID, status, keyword, page, pageSize)
+	projects, total, err := s.projectRepo.List(scope, status, keyword, page, pageSize)
 	if err != nil {
 		return nil, err
 	}
@@ -76,13 +78,23 @@ func (s *ProjectService) List(userID int64, status, keyword string, page, pageSi
 //
 // 参数:
 //   - id: 项目ID
+//   - actor: 操作者，须可查看该项目
 //
 // 返回:
-//   - *models.Project: 项目实体（包含 Preloaded Payments）
-//   - error: 记录不存在或数据库错误
-func (s *ProjectService) Get(id int64) (*models.Project, error) {
+//   - *dto.ProjectDetail: 项目实体（包含 Preloaded Payments）及操作者的项目角色
+//   - error: ErrProjectNotFound、ErrProjectForbidden 或数据库错误
+func (s *ProjectService) Get(id int64, actor ProjectActor) (*dto.ProjectDetail, error) {
+	_, role, err := s.access.authorize(id, actor, projectActionView)
+	if err != nil {
+		return nil, err
+	}
+
 	// 使用 FindByIDWithPayments 确保在详情页能展示关联的收款计划
-	return s.projectRepo.FindByIDWithPayments(id)
+	project, err := s.projectRepo.FindByIDWithPayments(id)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.ProjectDetail{Project: *project, MyRole: role}, nil
 }
 
 // Create 创建新项目
@@ -150,13 +162,14 @@ func (s *ProjectService) Create(input dto.CreateProjectRequest) (*models.Project
 // 参数:
 //   - id: 项目ID
 //   - input: 更新请求DTO
+//   - actor: 操作者，须为负责人或编辑成员
 //
 // 返回:
 //   - *models.Project: 更新后的项目实体
-//   - error: 记录不存在或更新失败
-func (s *ProjectService) Update(id int64, input dto.CreateProjectRequest) (*models.Project, error) {
-	// 1. 检查是否存在
-	project, err := s.projectRepo.FindByID(id)
+//   - error: 记录不存在、无权操作或更新失败
+func (s *ProjectService) Update(id int64, input dto.CreateProjectRequest, actor ProjectActor) (*models.Project, error) {
+	// 1. 检查是否存在及操作权限
+	project, _, err := s.access.authorize(id, actor, projectActionEdit)
 	if err != nil {
 		return nil, err
 	}
@@ -201,19 +214,27 @@ func (s *ProjectService) Update(id int64, input dto.CreateProjectRequest) (*mode
 }
 
 // Delete 删除项目及关联数据
-// 这是一个事务操作，会同时删除项目本身及其下属的所有款项记录。
+// 这是一个事务操作，会同时删除项目本身及其下属的所有款项与成员记录。
 //
 // 参数:
 //   - id: 待删除的项目ID
+//   - actor: 操作者，须为项目负责人
 //
 // 返回:
-//   - error: 事务执行错误
-func (s *ProjectService) Delete(id int64) error {
+//   - error: 无权操作或事务执行错误
+func (s *ProjectService) Delete(id int64, actor ProjectActor) error {
+	if _, _, err := s.access.authorize(id, actor, projectActionManage); err != nil {
+		return err
+	}
+
 	return database.GetDB().Transaction(func(tx *gorm.DB) error {
-		// 1. 级联删除: 先删除项目关联的所有款项 (Payments)
+		// 1. 级联删除: 先删除项目关联的所有款项 (Payments) 与成员
 		if err := tx.Where("project_id = ?", id).Delete(&models.Payment{}).Error; err != nil {
 			return err
 		}
+		if err := tx.Where("project_id = ?", id).Delete(&models.ProjectMember{}).Error; err != nil {
+			return err
+		}
 		// 2. 主体删除: 删除项目本身
 		if err := tx.Delete(&models.Project{}, id).Error; err != nil {
 			return err
@@ -224,7 +245,11 @@ func (s *ProjectService) Delete(id int64) error {
 
 // Archive 归档项目
 // 将项目状态更新为 "archived"，归档后的项目通常只读或不显示在主列表中。
-func (s *ProjectService) Archive(id int64) error {
+// 操作者须为负责人或编辑成员。
+func (s *ProjectService) Archive(id int64, actor ProjectActor) error {
+	if _, _, err := s.access.authorize(id, actor, projectActionEdit); err != nil {
+		return err
+	}
 	return s.projectRepo.UpdateStatus(id, "archived")
 }
 
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/repository"
+	"gorm.io/gorm"
+)
+
+// 项目角色
+const (
+	ProjectRoleOwner   = "owner"   // 负责人: 全部操作，管理成员、转让与删除项目
+	ProjectRoleEditor  = "editor"  // 编辑: 编辑、归档项目，维护收款
+	ProjectRoleFinance = "finance" // 财务: 查看项目，维护与确认收款
+	ProjectRoleViewer  = "viewer"  // 查看: 只读
+)
+
+// 项目操作
+const (
+	projectActionView    = "view"    // 查看项目、收款与成员
+	projectActionEdit    = "edit"    // 编辑、归档项目
+	projectActionPayment = "payment" // 录入、修改、删除与确认收款
+	projectActionManage  = "manage"  // 删除项目、管理成员、转让项目
+)
+
+// projectRoleActions 各项目角色可执行的操作
+var projectRoleActions = map[string]map[string]bool{
+	ProjectRoleOwner:   {projectActionView: true, projectActionEdit: true, projectActionPayment: true, projectActionManage: true},
+	ProjectRoleEditor:  {projectActionView: true, projectActionEdit: true, projectActionPayment: true},
+	ProjectRoleFinance: {projectActionView: true, projectActionPayment: true},
+	ProjectRoleViewer:  {projectActionView: true},
+}
+
+var (
+	// ErrProjectNotFound 项目不存在
+	ErrProjectNotFound = errors.New("项目不存在")
+	// ErrProjectForbidden 无权对项目执行该操作
+	ErrProjectForbidden = errors.New("无权操作该项目")
+	// ErrPaymentNotFound 收款记录不存在
+	ErrPaymentNotFound = errors.New("收款记录不存在")
+	// ErrPaymentProjectChanged 收款不能改到其他项目
+	ErrPaymentProjectChanged = errors.New("不能修改收款所属的项目")
+)
+
+// ProjectActor 项目与收款操作的发起人
+type ProjectActor struct {
+	UserID  int64
+	Manager bool                 // 拥有 project.manage 权限，视同所有项目的负责人
+	Scope   repository.DataScope // 数据范围，范围内的项目仅可查看
+}
+
+// projectAccess 项目访问校验 (ProjectService、PaymentService、ProjectMemberService 共用)
+// 按 ID 操作项目或收款前均须经过校验: 记录不存在时返回 ErrProjectNotFound / ErrPaymentNotFound，
+// 存在但操作者的项目角色不允许该操作时返回 ErrProjectForbidden。
+// 操作者在项目中的角色: 负责人为 owner，成员为其项目角色，仅通过数据范围可见时为 viewer。
+type projectAccess struct {
+	projectRepo *repository.ProjectRepository
+	memberRepo  *repository.ProjectMemberRepository
+	paymentRepo *repository.PaymentRepository
+}
+
+// newProjectAccess 创建项目访问校验
+func newProjectAccess() projectAccess {
+	return projectAccess{
+		projectRepo: repository.NewProjectRepository(),
+		memberRepo:  repository.NewProjectMemberRepository(),
+		paymentRepo: repository.NewPaymentRepository(),
+	}
+}
+
+// authorize 校验操作者可对项目执行指定操作，返回项目及操作者的项目角色
+func (a projectAccess) authorize(projectID int64, actor ProjectActor, action string) (*models.Project, string, error) {
+	project, err := a.projectRepo.FindByID(projectID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, "", ErrProjectNotFound
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	role, err := a.role(project, actor)
+	if err != nil {
+		return nil, "", err
+	}
+	if !projectRoleActions[role][action] {
+		return nil, "", ErrProjectForbidden
+	}
+	return project, role, nil
+}
+
+// authorizePayment 查找收款并校验操作者可对其所属项目执行指定操作
+func (a projectAccess) authorizePayment(paymentID int64, actor ProjectActor, action string) (*models.Payment, error) {
+	payment, err := a.paymentRepo.FindByID(paymentID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrPaymentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := a.authorize(payment.ProjectID, actor, action); err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+// role 解析操作者在项目中的角色，无权访问时返回空
+func (a projectAccess) role(project *models.Project, actor ProjectActor) (string, error) {
+	if actor.Manager || project.UserID == actor.UserID {
+		return ProjectRoleOwner, nil
+	}
+
+	member, err := a.memberRepo.Find(project.ID, actor.UserID)
+	if err == nil {
+		return member.Role, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", err
+	}
+
+	visible, err := a.projectRepo.InScope(project.ID, actor.Scope)
+	if err != nil || !visible {
+		return "", err
+	}
+	return ProjectRoleViewer, nil
+}
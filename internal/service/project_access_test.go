@@ -0,0 +1,248 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/repository"
+)
+
+// missingID 测试中不存在的项目与收款ID
+const missingID int64 = 1 << 40
+
+// actorOf 返回仅能看到本人数据的操作者
+func actorOf(user *models.User) ProjectActor {
+	return ProjectActor{UserID: user.ID, Scope: repository.DataScope{UserIDs: []int64{user.ID}}}
+}
+
+// createTestProject 以 owner 为负责人创建项目
+func createTestProject(t *testing.T, owner *models.User, name string) *models.Project {
+	t.Helper()
+	project, err := NewProjectService().Create(dto.CreateProjectRequest{
+		Name:        name,
+		Company:     "测试公司",
+		TotalAmount: 10000,
+		Type:        "development",
+		StartDate:   "2026-01-01",
+		EndDate:     "2026-12-31",
+		UserID:      owner.ID,
+	})
+	if err != nil {
+		t.Fatalf("创建项目失败: %v", err)
+	}
+	return project
+}
+
+// paymentInput 返回项目下一笔待收款的请求
+func paymentInput(projectID int64) dto.PaymentRequest {
+	return dto.PaymentRequest{ProjectID: projectID, Stage: "首付款", Amount: 3000, PlanDate: "2026-03-01"}
+}
+
+func TestProjectAccessForbidsOtherUsers(t *testing.T) {
+	owner := createTestUser(t, "access_owner", RoleUser)
+	stranger := createTestUser(t, "access_stranger", RoleUser)
+	project := createTestProject(t, owner, "访问校验项目")
+
+	payments := NewPaymentService()
+	payment, err := payments.Create(paymentInput(project.ID), actorOf(owner))
+	if err != nil {
+		t.Fatalf("创建收款失败: %v", err)
+	}
+
+	projects := NewProjectService()
+	other := actorOf(stranger)
+	update := dto.CreateProjectRequest{
+		Name: "篡改", Company: "测试公司", TotalAmount: 1, Type: "development",
+		StartDate: "2026-01-01", EndDate: "2026-12-31",
+	}
+	cases := []struct {
+		name string
+		call func() error
+	}{
+		{"Project.Get", func() error { _, err := projects.Get(project.ID, other); return err }},
+		{"Project.Update", func() error { _, err := projects.Update(project.ID, update, other); return err }},
+		{"Project.Archive", func() error { return projects.Archive(project.ID, other) }},
+		{"Project.Delete", func() error { return projects.Delete(project.ID, other) }},
+		{"Payment.ListByProject", func() error { _, err := payments.ListByProject(project.ID, other); return err }},
+		{"Payment.Create", func() error { _, err := payments.Create(paymentInput(project.ID), other); return err }},
+		{"Payment.Update", func() error { _, err := payments.Update(payment.ID, paymentInput(project.ID), other); return err }},
+		{"Payment.Confirm", func() error { return payments.Confirm(payment.ID, "2026-03-02", "bank", other) }},
+		{"Payment.Delete", func() error { return payments.Delete(payment.ID, other) }},
+	}
+	for _, tc := range cases {
+		if err := tc.call(); !errors.Is(err, ErrProjectForbidden) {
+			t.Errorf("%s: 期望 ErrProjectForbidden，实际 %v", tc.name, err)
+		}
+	}
+
+	// 被拒绝的操作不得修改数据
+	detail, err := projects.Get(project.ID, actorOf(owner))
+	if err != nil {
+		t.Fatalf("负责人查看项目失败: %v", err)
+	}
+	if detail.Name != "访问校验项目" || detail.Status != "active" {
+		t.Errorf("项目被修改: name=%q status=%q", detail.Name, detail.Status)
+	}
+	list, err := payments.ListByProject(project.ID, actorOf(owner))
+	if err != nil {
+		t.Fatalf("负责人查看收款失败: %v", err)
+	}
+	if len(list) != 1 || list[0].Status != "pending" {
+		t.Errorf("收款被修改: %+v", list)
+	}
+}
+
+func TestProjectAccessViewerIsReadOnly(t *testing.T) {
+	owner := createTestUser(t, "viewer_owner", RoleUser)
+	viewer := createTestUser(t, "viewer_member", RoleUser)
+	project := createTestProject(t, owner, "只读成员项目")
+	payment, err := NewPaymentService().Create(paymentInput(project.ID), actorOf(owner))
+	if err != nil {
+		t.Fatalf("创建收款失败: %v", err)
+	}
+	if _, err := NewProjectMemberService().Add(project.ID, dto.ProjectMemberRequest{UserID: viewer.ID, Role: ProjectRoleViewer}, actorOf(owner)); err != nil {
+		t.Fatalf("添加成员失败: %v", err)
+	}
+
+	member := actorOf(viewer)
+	detail, err := NewProjectService().Get(project.ID, member)
+	if err != nil {
+		t.Fatalf("成员查看项目失败: %v", err)
+	}
+	if detail.MyRole != ProjectRoleViewer {
+		t.Errorf("my_role = %q，期望 viewer", detail.MyRole)
+	}
+	if _, err := NewPaymentService().ListByProject(project.ID, member); err != nil {
+		t.Errorf("成员查看收款失败: %v", err)
+	}
+	if err := NewProjectService().Archive(project.ID, member); !errors.Is(err, ErrProjectForbidden) {
+		t.Errorf("Archive: 期望 ErrProjectForbidden，实际 %v", err)
+	}
+	if err := NewPaymentService().Confirm(payment.ID, "2026-03-02", "bank", member); !errors.Is(err, ErrProjectForbidden) {
+		t.Errorf("Confirm: 期望 ErrProjectForbidden，实际 %v", err)
+	}
+}
+
+func TestProjectAccessNotFound(t *testing.T) {
+	owner := createTestUser(t, "missing_owner", RoleUser)
+	actor := actorOf(owner)
+	projects := NewProjectService()
+	payments := NewPaymentService()
+	update := dto.CreateProjectRequest{
+		Name: "不存在", Company: "测试公司", TotalAmount: 1, Type: "development",
+		StartDate: "2026-01-01", EndDate: "2026-12-31",
+	}
+
+	projectCases := []struct {
+		name string
+		call func() error
+	}{
+		{"Project.Get", func() error { _, err := projects.Get(missingID, actor); return err }},
+		{"Project.Update", func() error { _, err := projects.Update(missingID, update, actor); return err }},
+		{"Project.Archive", func() error { return projects.Archive(missingID, actor) }},
+		{"Project.Delete", func() error { return projects.Delete(missingID, actor) }},
+		{"Payment.ListByProject", func() error { _, err := payments.ListByProject(missingID, actor); return err }},
+		{"Payment.Create", func() error { _, err := payments.Create(paymentInput(missingID), actor); return err }},
+	}
+	for _, tc := range projectCases {
+		if err := tc.call(); !errors.Is(err, ErrProjectNotFound) {
+			t.Errorf("%s: 期望 ErrProjectNotFound，实际 %v", tc.name, err)
+		}
+	}
+
+	paymentCases := []struct {
+		name string
+		call func() error
+	}{
+		{"Payment.Update", func() error { _, err := payments.Update(missingID, paymentInput(missingID), actor); return err }},
+		{"Payment.Confirm", func() error { return payments.Confirm(missingID, "2026-03-02", "bank", actor) }},
+		{"Payment.Delete", func() error { return payments.Delete(missingID, actor) }},
+	}
+	for _, tc := range paymentCases {
+		if err := tc.call(); !errors.Is(err, ErrPaymentNotFound) {
+			t.Errorf("%s: 期望 ErrPaymentNotFound，实际 %v", tc.name, err)
+		}
+	}
+}
+
+func TestPaymentUpdateRejectsProjectChange(t *testing.T) {
+	owner := createTestUser(t, "move_owner", RoleUser)
+	actor := actorOf(owner)
+	source := createTestProject(t, owner, "原项目")
+	target := createTestProject(t, owner, "目标项目")
+
+	payments := NewPaymentService()
+	payment, err := payments.Create(paymentInput(source.ID), actor)
+	if err != nil {
+		t.Fatalf("创建收款失败: %v", err)
+	}
+
+	// 即使操作者同为两个项目的负责人，也不能把收款移到其他项目
+	if _, err := payments.Update(payment.ID, paymentInput(target.ID), actor); !errors.Is(err, ErrPaymentProjectChanged) {
+		t.Fatalf("期望 ErrPaymentProjectChanged，实际 %v", err)
+	}
+	list, err := payments.ListByProject(target.ID, actor)
+	if err != nil {
+		t.Fatalf("查看收款失败: %v", err)
+	}
+	if len(list) != 0 {
+		t.Errorf("目标项目不应有收款，实际 %d 条", len(list))
+	}
+
+	// 所属项目不变时可正常修改
+	input := paymentInput(source.ID)
+	input.Amount = 4000
+	updated, err := payments.Update(payment.ID, input, actor)
+	if err != nil {
+		t.Fatalf("修改收款失败: %v", err)
+	}
+	if updated.Amount != 4000 || updated.ProjectID != source.ID {
+		t.Errorf("修改结果不正确: %+v", updated)
+	}
+}
+
+func TestProjectAccessAllScopeIsReadOnly(t *testing.T) {
+	owner := createTestUser(t, "scope_owner", RoleUser)
+	member := createTestUser(t, "scope_member", RoleUser)
+	outsider := createTestUser(t, "scope_outsider", RoleUser)
+	project := createTestProject(t, owner, "全部数据范围项目")
+	members := NewProjectMemberService()
+	if _, err := members.Add(project.ID, dto.ProjectMemberRequest{UserID: member.ID, Role: ProjectRoleEditor}, actorOf(owner)); err != nil {
+		t.Fatalf("添加成员失败: %v", err)
+	}
+
+	// 数据范围只决定可查看的项目: 全部数据范围但没有 project.manage 权限时仅为查看者
+	actor := ProjectActor{UserID: outsider.ID, Scope: repository.DataScope{All: true}}
+	detail, err := NewProjectService().Get(project.ID, actor)
+	if err != nil {
+		t.Fatalf("查看项目失败: %v", err)
+	}
+	if detail.MyRole != ProjectRoleViewer {
+		t.Errorf("my_role = %q，期望 viewer", detail.MyRole)
+	}
+	if err := members.Remove(project.ID, member.ID, actor); !errors.Is(err, ErrProjectForbidden) {
+		t.Errorf("Remove: 期望 ErrProjectForbidden，实际 %v", err)
+	}
+	if err := members.Transfer(project.ID, outsider.ID, actor); !errors.Is(err, ErrProjectForbidden) {
+		t.Errorf("Transfer: 期望 ErrProjectForbidden，实际 %v", err)
+	}
+	if err := NewProjectService().Archive(project.ID, actor); !errors.Is(err, ErrProjectForbidden) {
+		t.Errorf("Archive: 期望 ErrProjectForbidden，实际 %v", err)
+	}
+
+	list, err := members.List(project.ID, actorOf(owner))
+	if err != nil {
+		t.Fatalf("查看成员失败: %v", err)
+	}
+	if len(list) != 2 || list[0].UserID != owner.ID || list[1].UserID != member.ID {
+		t.Errorf("成员或负责人被修改: %+v", list)
+	}
+
+	// 拥有 project.manage 权限时视同负责人
+	manager := ProjectActor{UserID: outsider.ID, Manager: true, Scope: repository.DataScope{All: true}}
+	if err := members.Remove(project.ID, member.ID, manager); err != nil {
+		t.Errorf("管理者移除成员: %v", err)
+	}
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidProjectRole 无效的项目角色 (成员只能是 editor、viewer、finance)
+	ErrInvalidProjectRole = errors.New("无效的项目角色")
+	// ErrProjectMemberNotFound 项目成员不存在
+	ErrProjectMemberNotFound = errors.New("项目成员不存在")
+	// ErrProjectMemberExists 用户已是项目负责人或成员
+	ErrProjectMemberExists = errors.New("该用户已是项目成员")
+	// ErrProjectMemberUser 成员用户不存在或已禁用
+	ErrProjectMemberUser = errors.New("用户不存在或已禁用")
+	// ErrProjectAlreadyOwner 转让对象已是项目负责人
+	ErrProjectAlreadyOwner = errors.New("该用户已是项目负责人")
+)
+
+// ProjectMemberService 项目成员服务
+// 负责人管理项目成员及其项目角色，并可将项目转让给其他用户。
+//
+// 依赖:
+//   - ProjectMemberRepository: 成员记录的读写
+//   - UserRepository: 成员用户校验
+type ProjectMemberService struct {
+	access     projectAccess
+	memberRepo *repository.ProjectMemberRepository
+	userRepo   *repository.UserRepository
+}
+
+// NewProjectMemberService 创建项目成员服务实例
+func NewProjectMemberService() *ProjectMemberService {
+	return &ProjectMemberService{
+		access:     newProjectAccess(),
+		memberRepo: repository.NewProjectMemberRepository(),
+		userRepo:   repository.NewUserRepository(),
+	}
+}
+
+// List 获取项目成员 (负责人排在首位，角色为 owner)
+func (s *ProjectMemberService) List(projectID int64, actor ProjectActor) ([]models.ProjectMember, error) {
+	project, _, err := s.access.authorize(projectID, actor, projectActionView)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.memberRepo.ListByProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	owner := models.ProjectMember{
+		ProjectID:  project.ID,
+		UserID:     project.UserID,
+		Role:       ProjectRoleOwner,
+		CreateTime: project.CreateTime,
+	}
+	if user, err := s.userRepo.FindByID(project.UserID); err == nil {
+		owner.User = &models.User{ID: user.ID, Username: user.Username, Name: user.Name, Department: user.Department, Position: user.Position}
+	}
+	return append([]models.ProjectMember{owner}, members...), nil
+}
+
+// Add 添加项目成员 (仅负责人)
+func (s *ProjectMemberService) Add(projectID int64, input dto.ProjectMemberRequest, actor ProjectActor) (*models.ProjectMember, error) {
+	project, _, err := s.access.authorize(projectID, actor, projectActionManage)
+	if err != nil {
+		return nil, err
+	}
+	if !isMemberRole(input.Role) {
+		return nil, ErrInvalidProjectRole
+	}
+	if err := s.checkUser(input.UserID); err != nil {
+		return nil, err
+	}
+	if input.UserID == project.UserID {
+		return nil, ErrProjectMemberExists
+	}
+	if _, err := s.memberRepo.Find(projectID, input.UserID); err == nil {
+		return nil, ErrProjectMemberExists
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	member := &models.ProjectMember{ProjectID: projectID, UserID: input.UserID, Role: input.Role}
+	if err := s.memberRepo.Create(member); err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+// UpdateRole 修改成员的项目角色 (仅负责人)
+func (s *ProjectMemberService) UpdateRole(projectID, userID int64, role string, actor ProjectActor) error {
+	if _, _, err := s.access.authorize(projectID, actor, projectActionManage); err != nil {
+		return err
+	}
+	if !isMemberRole(role) {
+		return ErrInvalidProjectRole
+	}
+	if err := s.findMember(projectID, userID); err != nil {
+		return err
+	}
+	return s.memberRepo.UpdateRole(projectID, userID, role)
+}
+
+// Remove 移除成员 (负责人可移除任意成员，成员可退出项目)
+func (s *ProjectMemberService) Remove(projectID, userID int64, actor ProjectActor) error {
+	action := projectActionManage
+	if userID == actor.UserID {
+		action = projectActionView
+	}
+	if _, _, err := s.access.authorize(projectID, actor, action); err != nil {
+		return err
+	}
+	if err := s.findMember(projectID, userID); err != nil {
+		return err
+	}
+	return s.memberRepo.Delete(projectID, userID)
+}
+
+// Transfer 将项目转让给其他用户 (仅负责人)，原负责人保留为编辑成员
+func (s *ProjectMemberService) Transfer(projectID, toUserID int64, actor ProjectActor) error {
+	project, _, err := s.access.authorize(projectID, actor, projectActionManage)
+	if err != nil {
+		return err
+	}
+	if toUserID == project.UserID {
+		return ErrProjectAlreadyOwner
+	}
+	if err := s.checkUser(toUserID); err != nil {
+		return err
+	}
+	return s.memberRepo.TransferOwner(projectID, project.UserID, toUserID, ProjectRoleEditor)
+}
+
+// Candidates 搜索可添加为成员或转让对象的用户 (最多 20 个)
+func (s *ProjectMemberService) Candidates(keyword string) ([]models.User, error) {
+	return s.userRepo.SearchBrief(keyword, 20)
+}
+
+// findMember 校验成员存在，不存在时返回 ErrProjectMemberNotFound
+func (s *ProjectMemberService) findMember(projectID, userID int64) error {
+	_, err := s.memberRepo.Find(projectID, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrProjectMemberNotFound
+	}
+	return err
+}
+
+// checkUser 校验成员用户存在且未禁用
+func (s *ProjectMemberService) checkUser(userID int64) error {
+	user, err := s.userRepo.FindByID(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && user.Status != 1) {
+		return ErrProjectMemberUser
+	}
+	return err
+}
+
+// isMemberRole 判断是否为可分配给成员的项目角色 (owner 只能通过转让变更)
+func isMemberRole(role string) bool {
+	return role != ProjectRoleOwner && projectRoleActions[role] != nil
+}
@@ -0,0 +1,318 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/repository"
+	"gorm.io/gorm"
+)
+
+// 权限编码 (与 permissions 表一致，新增权限须同时追加迁移)
+const (
+	PermProjectView      = "project.view"      // 查看项目
+	PermProjectCreate    = "project.create"    // 创建项目
+	PermProjectEdit      = "project.edit"      // 编辑项目
+	PermProjectDelete    = "project.delete"    // 删除项目
+	PermProjectArchive   = "project.archive"   // 归档项目
+	PermProjectManage    = "project.manage"    // 管理全部项目 (视同所有项目的负责人)
+	PermPaymentView      = "payment.view"      // 查看收款
+	PermPaymentCreate    = "payment.create"    // 创建收款
+	PermPaymentEdit      = "payment.edit"      // 编辑收款
+	PermPaymentDelete    = "payment.delete"    // 删除收款
+	PermPaymentConfirm   = "payment.confirm"   // 确认收款
+	PermDashboardView    = "dashboard.view"    // 查看仪表盘
+	PermDictionaryEdit   = "dictionary.edit"   // 维护数据字典
+	PermNotificationSend = "notification.send" // 发送与管理通知
+	PermSyncExecute      = "sync.execute"      // 执行数据同步
+	PermSyncManage       = "sync.manage"       // 管理同步连接配置、直接填写连接信息
+	PermUserManage       = "user.manage"       // 用户管理
+	PermRoleManage       = "role.manage"       // 角色与权限管理
+	PermAuditView        = "audit.view"        // 查看审计日志
+	PermSettingManage    = "setting.manage"    // 安全设置与签名密钥
+	PermOrgManage        = "org.manage"        // 部门与职位管理
+)
+
+// RoleAdmin 内置管理员角色，始终拥有全部权限
+const RoleAdmin = "admin"
+
+// RoleUser 内置普通用户角色 (新用户的默认角色)
+const RoleUser = "user"
+
+// rolePermissionTTL 角色权限缓存有效期
+// 同一进程内修改角色会立即清空缓存，该有效期仅用于感知其他实例 (如多个服务共用数据库) 的修改。
+const rolePermissionTTL = time.Minute
+
+var (
+	// ErrRoleNotFound 角色不存在
+	ErrRoleNotFound = errors.New("角色不存在")
+	// ErrRoleBuiltIn 内置角色不可删除
+	ErrRoleBuiltIn = errors.New("内置角色不可删除")
+	// ErrRoleInUse 角色仍有用户使用
+	ErrRoleInUse = errors.New("仍有用户使用该角色，请先调整这些用户的角色")
+	// ErrInvalidDataScope 数据范围无效
+	ErrInvalidDataScope = errors.New("无效的数据范围")
+)
+
+// roleCodePattern 角色编码格式
+var roleCodePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,19}$`)
+
+// rolePermissionCache 角色权限缓存 (角色编码 -> 权限集合、数据范围)，进程内共享
+var rolePermissionCache struct {
+	mu       sync.RWMutex
+	roles    map[string]map[string]bool
+	scopes   map[string]string
+	loadTime time.Time
+}
+
+// RoleService 角色与权限服务
+// 用户通过 users.role 引用角色编码，权限判断以角色为单位缓存，避免每个请求查询数据库。
+//
+// 依赖:
+//   - RoleRepository: 角色、权限及其关联的读写
+type RoleService struct {
+	roleRepo *repository.RoleRepository
+}
+
+// NewRoleService 创建角色服务实例
+func NewRoleService() *RoleService {
+	return &RoleService{
+		roleRepo: repository.NewRoleRepository(),
+	}
+}
+
+// RolePermissions 获取角色拥有的权限集合 (读取失败或角色不存在时为空)
+func (s *RoleService) RolePermissions(role string) map[string]bool {
+	perms, _ := s.cached(role)
+	return perms
+}
+
+// RoleDataScope 获取角色的数据范围 (读取失败或角色不存在时为仅本人)
+func (s *RoleService) RoleDataScope(role string) string {
+	_, scope := s.cached(role)
+	if scope == "" {
+		return DataScopeSelf
+	}
+	return scope
+}
+
+// cached 从缓存读取角色的权限集合与数据范围，缓存过期时重新加载
+func (s *RoleService) cached(role string) (map[string]bool, string) {
+	rolePermissionCache.mu.RLock()
+	if rolePermissionCache.roles != nil && time.Since(rolePermissionCache.loadTime) < rolePermissionTTL {
+		perms, scope := rolePermissionCache.roles[role], rolePermissionCache.scopes[role]
+		rolePermissionCache.mu.RUnlock()
+		return perms, scope
+	}
+	rolePermissionCache.mu.RUnlock()
+
+	rolePermissionCache.mu.Lock()
+	defer rolePermissionCache.mu.Unlock()
+	if rolePermissionCache.roles == nil || time.Since(rolePermissionCache.loadTime) >= rolePermissionTTL {
+		list, err := s.roleRepo.List()
+		if err != nil {
+			return nil, ""
+		}
+		codes, err := s.roleRepo.ListRolePermissions()
+		if err != nil {
+			return nil, ""
+		}
+		roles := make(map[string]map[string]bool, len(codes))
+		for code, list := range codes {
+			set := make(map[string]bool, len(list))
+			for _, p := range list {
+				set[p] = true
+			}
+			roles[code] = set
+		}
+		scopes := make(map[string]string, len(list))
+		for _, r := range list {
+			scopes[r.Code] = r.DataScope
+		}
+		rolePermissionCache.roles = roles
+		rolePermissionCache.scopes = scopes
+		rolePermissionCache.loadTime = time.Now()
+	}
+	return rolePermissionCache.roles[role], rolePermissionCache.scopes[role]
+}
+
+// PermissionCodes 获取角色拥有的权限编码列表
+func (s *RoleService) PermissionCodes(role string) []string {
+	perms := s.RolePermissions(role)
+	codes := make([]string, 0, len(perms))
+	permissions, err := s.roleRepo.ListPermissions()
+	if err != nil {
+		return codes
+	}
+	for _, p := range permissions {
+		if perms[p.Code] {
+			codes = append(codes, p.Code)
+		}
+	}
+	return codes
+}
+
+// HasPermission 判断角色是否拥有指定权限
+func (s *RoleService) HasPermission(role, permission string) bool {
+	return s.RolePermissions(role)[permission]
+}
+
+// Exists 判断角色是否存在
+func (s *RoleService) Exists(role string) bool {
+	return s.roleRepo.ExistsByCode(role)
+}
+
+// ListPermissions 获取全部权限
+func (s *RoleService) ListPermissions() ([]models.Permission, error) {
+	return s.roleRepo.ListPermissions()
+}
+
+// List 获取全部角色 (含权限与使用人数)
+func (s *RoleService) List() ([]dto.RoleView, error) {
+	roles, err := s.roleRepo.List()
+	if err != nil {
+		return nil, err
+	}
+	codes, err := s.roleRepo.ListRolePermissions()
+	if err != nil {
+		return nil, err
+	}
+	views := make([]dto.RoleView, 0, len(roles))
+	for _, role := range roles {
+		count, err := s.roleRepo.CountUsers(role.Code)
+		if err != nil {
+			return nil, err
+		}
+		perms := codes[role.Code]
+		if perms == nil {
+			perms = []string{}
+		}
+		views = append(views, dto.RoleView{Role: role, Permissions: perms, UserCount: count})
+	}
+	return views, nil
+}
+
+// Create 创建角色
+func (s *RoleService) Create(input dto.RoleRequest) (*models.Role, error) {
+	if !roleCodePattern.MatchString(input.Code) {
+		return nil, errors.New("角色编码须以小写字母开头，由 2-20 位小写字母、数字或下划线组成")
+	}
+	if s.roleRepo.ExistsByCode(input.Code) {
+		return nil, errors.New("角色编码已存在")
+	}
+	if input.DataScope == "" {
+		input.DataScope = DataScopeSelf
+	} else if !validDataScopes[input.DataScope] {
+		return nil, ErrInvalidDataScope
+	}
+	ids, err := s.permissionIDs(input.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	role := &models.Role{Code: input.Code, Name: input.Name, Description: input.Description, DataScope: input.DataScope}
+	if err := s.roleRepo.Create(role, ids); err != nil {
+		return nil, err
+	}
+	invalidateRolePermissions()
+	return role, nil
+}
+
+// Update 更新角色名称、描述、数据范围与权限
+// 内置管理员角色始终拥有全部权限与全部数据，忽略提交的权限列表与数据范围；数据范围为空表示不修改。
+func (s *RoleService) Update(id int64, input dto.RoleRequest) (*models.Role, error) {
+	role, err := s.find(id)
+	if err != nil {
+		return nil, err
+	}
+	if input.DataScope != "" && !validDataScopes[input.DataScope] {
+		return nil, ErrInvalidDataScope
+	}
+
+	var ids []int64
+	if role.Code != RoleAdmin {
+		if ids, err = s.permissionIDs(input.Permissions); err != nil {
+			return nil, err
+		}
+		if input.DataScope != "" {
+			role.DataScope = input.DataScope
+		}
+	}
+	role.Name = input.Name
+	role.Description = input.Description
+	if err := s.roleRepo.Update(role, ids); err != nil {
+		return nil, err
+	}
+	invalidateRolePermissions()
+	return role, nil
+}
+
+// Delete 删除角色 (内置角色与仍有用户使用的角色不可删除)
+func (s *RoleService) Delete(id int64) error {
+	role, err := s.find(id)
+	if err != nil {
+		return err
+	}
+	if role.BuiltIn {
+		return ErrRoleBuiltIn
+	}
+	count, err := s.roleRepo.CountUsers(role.Code)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrRoleInUse
+	}
+	if err := s.roleRepo.Delete(id); err != nil {
+		return err
+	}
+	invalidateRolePermissions()
+	return nil
+}
+
+// find 查找角色，不存在时返回 ErrRoleNotFound
+func (s *RoleService) find(id int64) (*models.Role, error) {
+	role, err := s.roleRepo.FindByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrRoleNotFound
+	}
+	return role, err
+}
+
+// permissionIDs 将权限编码转换为权限ID (返回非 nil 切片，表示替换为该列表)
+func (s *RoleService) permissionIDs(codes []string) ([]int64, error) {
+	permissions, err := s.roleRepo.ListPermissions()
+	if err != nil {
+		return nil, err
+	}
+	byCode := make(map[string]int64, len(permissions))
+	for _, p := range permissions {
+		byCode[p.Code] = p.ID
+	}
+
+	ids := make([]int64, 0, len(codes))
+	seen := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		id, ok := byCode[code]
+		if !ok {
+			return nil, fmt.Errorf("未知的权限: %s", code)
+		}
+		if !seen[code] {
+			seen[code] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// invalidateRolePermissions 清空角色权限缓存
+func invalidateRolePermissions() {
+	rolePermissionCache.mu.Lock()
+	rolePermissionCache.roles = nil
+	rolePermissionCache.mu.Unlock()
+}
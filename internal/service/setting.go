@@ -0,0 +1,62 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/pkg/password"
+	"github.com/FruitsAI/Orange/internal/repository"
+)
+
+// settingKeySecurity 安全设置在 system_settings 中的键
+const settingKeySecurity = "security"
+
+// SettingService 系统设置服务
+// 设置以 JSON 形式按分组存储，读取时以默认值为基础合并，新增字段无需迁移数据。
+//
+// 依赖:
+//   - SettingRepository: 系统设置的读写
+type SettingService struct {
+	settingRepo *repository.SettingRepository
+}
+
+// NewSettingService 创建系统设置服务实例
+func NewSettingService() *SettingService {
+	return &SettingService{
+		settingRepo: repository.NewSettingRepository(),
+	}
+}
+
+// GetSecurity 获取安全设置
+func (s *SettingService) GetSecurity() (dto.SecuritySettings, error) {
+	settings := dto.SecuritySettings{PasswordPolicy: password.DefaultPolicy()}
+	value, err := s.settingRepo.Get(settingKeySecurity)
+	if err != nil || value == "" {
+		return settings, err
+	}
+	if err := json.Unmarshal([]byte(value), &settings); err != nil {
+		return settings, fmt.Errorf("安全设置格式错误: %w", err)
+	}
+	settings.PasswordPolicy = settings.PasswordPolicy.Normalize()
+	return settings, nil
+}
+
+// GetPasswordPolicy 获取密码策略，读取失败时使用默认策略
+func (s *SettingService) GetPasswordPolicy() password.Policy {
+	settings, err := s.GetSecurity()
+	if err != nil {
+		return password.DefaultPolicy()
+	}
+	return settings.PasswordPolicy
+}
+
+// UpdateSecurity 保存安全设置
+func (s *SettingService) UpdateSecurity(settings dto.SecuritySettings) error {
+	settings.PasswordPolicy = settings.PasswordPolicy.Normalize()
+	value, err := json.Marshal(settings)
+	if err != nil {
+		return err
+	}
+	return s.settingRepo.Set(settingKeySecurity, string(value))
+}
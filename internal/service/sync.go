@@ -1,19 +1,30 @@
 package service
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"path/filepath"
+	"time"
 
+	"github.com/FruitsAI/Orange/internal/config"
 	"github.com/FruitsAI/Orange/internal/database"
 	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/repository"
+	"github.com/glebarez/sqlite"
 	_ "github.com/go-sql-driver/mysql"
 	_ "github.com/jackc/pgx/v5/stdlib"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
 )
 
 // SyncConfig 云端数据库连接配置
 type SyncConfig struct {
-	DBType   string `json:"db_type"`  // postgres, mysql
+	DBType   string `json:"db_type"`  // postgres, mysql, sqlite
+	Path     string `json:"path"`     // SQLite 文件路径 (仅 sqlite)
 	Host     string `json:"host"`     // 主机地址
 	Port     int    `json:"port"`     // 端口号
 	User     string `json:"user"`     // 用户名
@@ -24,25 +35,64 @@ type SyncConfig struct {
 
 // TableCompareResult 表对比结果
 type TableCompareResult struct {
-	TableName   string `json:"table_name"`   // 表名
-	LocalCount  int64  `json:"local_count"`  // 本地记录数
-	RemoteCount int64  `json:"remote_count"` // 云端记录数
+	TableName          string         `json:"table_name"`           // 表名
+	LocalCount         int64          `json:"local_count"`          // 本地记录数
+	RemoteCount        int64          `json:"remote_count"`         // 云端记录数
+	PendingCount       int64          `json:"pending_count"`        // 待推送的变更数 (自上次同步以来的新增/修改/删除)
+	RemotePendingCount int64          `json:"remote_pending_count"` // 待拉取的云端变更数
+	Conflicts          []SyncConflict `json:"conflicts"`            // 两侧都发生变更的记录 (按指定策略给出处理结果)
+	LastSyncTime       *time.Time     `json:"last_sync_time"`       // 最近一次成功同步时间
+}
+
+// SyncOptions 同步选项
+type SyncOptions struct {
+	Tables []string // 要同步的表
+	Full   bool     // 是否强制全量同步 (忽略水位线，处理全部记录)
+	Mode   string   // 同步方向: push/pull/merge，默认 push
+	Policy string   // 冲突解决策略 (pull/merge 生效)，默认 last_writer_wins
+	// Progress 进度回调 (可选)
+	Progress SyncProgressFunc
 }
 
 // SyncResult 同步结果
 type SyncResult struct {
-	TableName    string `json:"table_name"`    // 表名
-	SyncedCount  int64  `json:"synced_count"`  // 同步记录数
-	Success      bool   `json:"success"`       // 是否成功
-	ErrorMessage string `json:"error_message"` // 错误信息
+	TableName         string         `json:"table_name"`          // 表名
+	Mode              string         `json:"mode"`                // 同步方向
+	SyncedCount       int64          `json:"synced_count"`        // 推送到云端的记录数
+	DeletedCount      int64          `json:"deleted_count"`       // 删除的云端记录数
+	PulledCount       int64          `json:"pulled_count"`        // 拉取到本地的记录数
+	LocalDeletedCount int64          `json:"local_deleted_count"` // 删除的本地记录数
+	Conflicts         []SyncConflict `json:"conflicts"`           // 冲突记录
+	Incremental       bool           `json:"incremental"`         // 是否为增量同步
+	Success           bool           `json:"success"`             // 是否成功
+	ErrorMessage      string         `json:"error_message"`       // 错误信息
 }
 
 // SyncService 数据同步服务
-type SyncService struct{}
+//
+// 依赖:
+//   - SyncRepository: 同步水位线与删除墓碑的读写
+type SyncService struct {
+	syncRepo *repository.SyncRepository
+}
 
 // NewSyncService 创建同步服务实例
 func NewSyncService() *SyncService {
-	return &SyncService{}
+	return &SyncService{
+		syncRepo: repository.NewSyncRepository(),
+	}
+}
+
+// targetKey 生成同步目标的唯一标识，不同云端库分别维护水位线
+func (c SyncConfig) targetKey() string {
+	if c.DBType == "sqlite" {
+		path, err := filepath.Abs(c.Path)
+		if err != nil {
+			path = c.Path
+		}
+		return "sqlite://" + filepath.ToSlash(path)
+	}
+	return fmt.Sprintf("%s://%s@%s:%d/%s", c.DBType, c.User, c.Host, c.Port, c.DBName)
 }
 
 // buildDSN 根据配置构建数据库连接字符串
@@ -58,6 +108,9 @@ func (s *SyncService) buildDSN(cfg SyncConfig) string {
 		}
 		return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
 			cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DBName, sslMode)
+	case "sqlite":
+		// 同步期间本地应用可能也在读写该文件，等待锁而不是立即失败
+		return cfg.Path + "?_pragma=busy_timeout(5000)"
 	default:
 		return ""
 	}
@@ -70,6 +123,8 @@ func (s *SyncService) getDriverName(dbType string) string {
 		return "mysql"
 	case "postgres":
 		return "pgx"
+	case "sqlite":
+		return sqlite.DriverName
 	default:
 		return ""
 	}
@@ -81,6 +136,9 @@ func (s *SyncService) TestConnection(cfg SyncConfig) error {
 	if driver == "" {
 		return fmt.Errorf("不支持的数据库类型: %s", cfg.DBType)
 	}
+	if err := checkSQLiteTarget(cfg); err != nil {
+		return err
+	}
 
 	dsn := s.buildDSN(cfg)
 	db, err := sql.Open(driver, dsn)
@@ -97,45 +155,142 @@ func (s *SyncService) TestConnection(cfg SyncConfig) error {
 	return nil
 }
 
-// CompareData 对比本地与云端表的记录数
-func (s *SyncService) CompareData(cfg SyncConfig) ([]TableCompareResult, error) {
-	// 连接云端数据库
+// remoteConn 云端数据库连接
+// 写入使用原生 SQL (sqlDB)，读取复用本地模型定义 (gormDB)。
+type remoteConn struct {
+	sqlDB  *sql.DB
+	gormDB *gorm.DB
+	dbType string
+}
+
+// Close 关闭云端连接
+func (r *remoteConn) Close() error {
+	return r.sqlDB.Close()
+}
+
+// openRemote 打开云端数据库连接
+func (s *SyncService) openRemote(cfg SyncConfig) (*remoteConn, error) {
 	driver := s.getDriverName(cfg.DBType)
 	if driver == "" {
 		return nil, fmt.Errorf("不支持的数据库类型: %s", cfg.DBType)
 	}
+	if err := checkSQLiteTarget(cfg); err != nil {
+		return nil, err
+	}
 
-	dsn := s.buildDSN(cfg)
-	remoteDB, err := sql.Open(driver, dsn)
+	sqlDB, err := sql.Open(driver, s.buildDSN(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSyncConnect, err)
+	}
+
+	var dialector gorm.Dialector
+	switch cfg.DBType {
+	case "postgres":
+		dialector = postgres.New(postgres.Config{Conn: sqlDB})
+	case "sqlite":
+		dialector = &sqlite.Dialector{Conn: sqlDB}
+	default:
+		dialector = mysql.New(mysql.Config{Conn: sqlDB})
+	}
+	gormDB, err := gorm.Open(dialector, &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+		// 云端数据由本地复制而来，不创建外键约束，避免按表同步时的先后顺序问题
+		DisableForeignKeyConstraintWhenMigrating: true,
+	})
+	if err != nil {
+		sqlDB.Close()
+		return nil, fmt.Errorf("%w: %w", ErrSyncConnect, err)
+	}
+
+	return &remoteConn{sqlDB: sqlDB, gormDB: gormDB, dbType: cfg.DBType}, nil
+}
+
+// checkSQLiteTarget 校验 SQLite 同步目标
+// 目标文件不能是本地数据库自身，否则同步会在同一个文件上读写。
+func checkSQLiteTarget(cfg SyncConfig) error {
+	if cfg.DBType != "sqlite" {
+		return nil
+	}
+	if cfg.Path == "" {
+		return errors.New("请指定 SQLite 文件路径")
+	}
+	if database.GetDBType() != "sqlite" {
+		return nil
+	}
+	target, err := filepath.Abs(cfg.Path)
+	if err != nil {
+		return err
+	}
+	local, err := filepath.Abs(config.AppConfig.DBPath)
+	if err == nil && target == local {
+		return errors.New("同步目标不能是本地数据库文件")
+	}
+	return nil
+}
+
+// CompareData 对比本地与云端表的记录数
+// 对已同步过的表，同时统计两侧待同步的变更，并按 policy 给出冲突记录及其处理结果。
+func (s *SyncService) CompareData(cfg SyncConfig, policy string) ([]TableCompareResult, error) {
+	if policy == "" {
+		policy = ConflictLastWriterWins
+	}
+	if !validConflictPolicy(policy) {
+		return nil, fmt.Errorf("不支持的冲突解决策略: %s", policy)
+	}
+
+	// 连接云端数据库
+	remote, err := s.openRemote(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("连接云端数据库失败: %w", err)
+		return nil, err
 	}
-	defer remoteDB.Close()
+	defer remote.Close()
 
 	// 获取本地数据库
 	localDB := database.GetDB()
 
 	// 要对比的表
-	tables := []string{"users", "projects", "payments", "dictionaries", "dictionary_item", "notifications", "user_notifications"}
-	results := make([]TableCompareResult, 0, len(tables))
+	results := make([]TableCompareResult, 0, len(syncTables))
 
-	for _, table := range tables {
-		result := TableCompareResult{TableName: table}
+	for i := range syncTables {
+		table := &syncTables[i]
+		result := TableCompareResult{TableName: table.Name, Conflicts: []SyncConflict{}}
 
 		// 本地计数
 		var localCount int64
-		localDB.Table(table).Count(&localCount)
+		localDB.Table(table.Name).Count(&localCount)
 		result.LocalCount = localCount
 
 		// 云端计数 (表可能不存在)
 		var remoteCount int64
-		row := remoteDB.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM %s", table))
+		row := remote.sqlDB.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM %s", table.Name))
 		if err := row.Scan(&remoteCount); err != nil {
 			result.RemoteCount = -1 // -1 表示表不存在或查询失败
 		} else {
 			result.RemoteCount = remoteCount
 		}
 
+		// 待同步变更数 (从未同步过时为全部记录)
+		result.PendingCount = localCount
+		result.RemotePendingCount = result.RemoteCount
+		state, err := s.syncRepo.FindState(cfg.targetKey(), table.Name)
+		if err != nil || state.LastSyncTime == nil || result.RemoteCount < 0 {
+			results = append(results, result)
+			continue
+		}
+		result.LastSyncTime = state.LastSyncTime
+
+		local, err := s.localChanges(localDB, table, state, true)
+		if err != nil {
+			return nil, fmt.Errorf("读取本地变更失败: %w", err)
+		}
+		remoteChanges, err := s.remoteChanges(remote, table, state, true)
+		if err != nil {
+			return nil, fmt.Errorf("读取云端变更失败: %w", err)
+		}
+		result.PendingCount = int64(len(local.rows) + len(local.deletes))
+		result.RemotePendingCount = int64(len(remoteChanges.rows) + len(remoteChanges.deletes))
+		result.Conflicts = append(result.Conflicts, detectConflicts(table.Name, local, remoteChanges, policy)...)
+
 		results = append(results, result)
 	}
 
@@ -143,298 +298,308 @@ func (s *SyncService) CompareData(cfg SyncConfig) ([]TableCompareResult, error)
 }
 
 // SyncTables 执行数据同步
-func (s *SyncService) SyncTables(cfg SyncConfig, tables []string) ([]SyncResult, error) {
-	// 连接云端数据库
-	driver := s.getDriverName(cfg.DBType)
-	if driver == "" {
-		return nil, fmt.Errorf("不支持的数据库类型: %s", cfg.DBType)
-	}
+// 同步前会按本地模型在云端创建或迁移所选表的结构 (见 ProvisionSchema)。
+// 默认为增量同步: 仅处理自上次成功同步以来变更的记录 (本地按 update_time / delete_time，云端按变更序号)。
+// 首次同步或 opts.Full 为 true 时执行全量同步。按 opts.Mode 决定方向:
+//   - push: 本地变更覆盖云端，全量时清理云端多余数据
+//   - pull: 云端变更写入本地，全量时清理本地多余数据
+//   - merge: 双向交换变更，不做多余数据清理
+//
+// pull/merge 会检测两侧都发生变更的记录并按 opts.Policy 解决；manual 策略下冲突记录
+// 两侧均不处理，且该表水位线不推进，冲突会在下次同步时再次报告。
+//
+// ctx 取消后正在同步的表整表回滚，剩余的表不再同步，返回已完成的结果与 ctx.Err()。
+// 同一时间只允许一次同步，已有同步在执行时返回 ErrSyncInProgress。
+func (s *SyncService) SyncTables(ctx context.Context, cfg SyncConfig, opts SyncOptions) ([]SyncResult, error) {
+	if opts.Mode == "" {
+		opts.Mode = SyncModePush
+	}
+	if opts.Policy == "" {
+		opts.Policy = ConflictLastWriterWins
+	}
+	if !validSyncMode(opts.Mode) {
+		return nil, fmt.Errorf("不支持的同步方向: %s", opts.Mode)
+	}
+	if !validConflictPolicy(opts.Policy) {
+		return nil, fmt.Errorf("不支持的冲突解决策略: %s", opts.Policy)
+	}
+	if !syncRunLock.TryLock() {
+		return nil, ErrSyncInProgress
+	}
+	defer syncRunLock.Unlock()
 
-	dsn := s.buildDSN(cfg)
-	remoteDB, err := sql.Open(driver, dsn)
+	// 连接云端数据库
+	remote, err := s.openRemote(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("连接云端数据库失败: %w", err)
+		return nil, err
 	}
-	defer remoteDB.Close()
+	defer remote.Close()
+	remote.gormDB = remote.gormDB.WithContext(ctx)
 
-	// 获取本地数据库
-	localDB := database.GetDB()
-	results := make([]SyncResult, 0, len(tables))
-
-	for _, table := range tables {
-		result := SyncResult{TableName: table, Success: true}
-
-		switch table {
-		case "users":
-			result.SyncedCount, result.ErrorMessage = s.syncUsers(localDB, remoteDB, cfg.DBType)
-		case "projects":
-			result.SyncedCount, result.ErrorMessage = s.syncProjects(localDB, remoteDB, cfg.DBType)
-		case "payments":
-			result.SyncedCount, result.ErrorMessage = s.syncPayments(localDB, remoteDB, cfg.DBType)
-		case "dictionaries":
-			result.SyncedCount, result.ErrorMessage = s.syncDictionaries(localDB, remoteDB, cfg.DBType)
-		case "dictionary_item":
-			result.SyncedCount, result.ErrorMessage = s.syncDictionaryItems(localDB, remoteDB, cfg.DBType)
-		case "notifications":
-			result.SyncedCount, result.ErrorMessage = s.syncNotifications(localDB, remoteDB, cfg.DBType)
-		case "user_notifications":
-			result.SyncedCount, result.ErrorMessage = s.syncUserNotifications(localDB, remoteDB, cfg.DBType)
-		default:
-			result.ErrorMessage = "未知表名"
+	// 推送前确保云端表结构存在且与本地模型一致 (未知表名在下方逐表报告)
+	known := make([]string, 0, len(opts.Tables))
+	for _, name := range opts.Tables {
+		if findSyncTable(name) != nil {
+			known = append(known, name)
 		}
-
-		if result.ErrorMessage != "" {
-			result.Success = false
-		}
-		results = append(results, result)
 	}
-
-	return results, nil
-}
-
-// deleteExtras 删除云端多余的数据
-func (s *SyncService) deleteExtras(remoteDB *sql.DB, table string, keepIDs []interface{}, dbType string) error {
-	if len(keepIDs) == 0 {
-		_, err := remoteDB.Exec(fmt.Sprintf("DELETE FROM %s", table))
-		return err
+	if len(known) > 0 {
+		if _, err := s.provisionSchema(remote, known, false); err != nil {
+			return nil, err
+		}
 	}
 
-	// 简单的分批处理，防止 SQL 过长 (假设每批 500)
-	// 这里简化处理，直接拼接。对于大量数据建议分批或使用临时表。
-	// 注意：PostgreSQL $1, $2... 占位符处理比较麻烦，因为需要动态生成序号。
-	// 为简单起见，这里假设 ID 数量不会非常巨大，直接拼接值（注意防止注入，ID是数字相对安全，但最好还是参数化）。
-	// 鉴于 Wails 应用场景，我们使用参数化查询。
-
-	query := fmt.Sprintf("DELETE FROM %s WHERE id NOT IN (", table)
-	args := make([]interface{}, len(keepIDs))
+	// 获取本地数据库
+	localDB := database.GetDB().WithContext(ctx)
+	results := make([]SyncResult, 0, len(opts.Tables))
 
-	for i, id := range keepIDs {
-		args[i] = id
-		if i > 0 {
-			query += ","
+	for _, name := range opts.Tables {
+		if err := ctx.Err(); err != nil {
+			return results, err
 		}
-		if dbType == "postgres" {
-			query += fmt.Sprintf("$%d", i+1)
-		} else {
-			query += "?"
+		table := findSyncTable(name)
+		if table == nil {
+			results = append(results, SyncResult{TableName: name, Mode: opts.Mode, ErrorMessage: "未知表名"})
+			continue
 		}
+		results = append(results, s.syncTable(ctx, localDB, remote, cfg, table, opts))
 	}
-	query += ")"
 
-	_, err := remoteDB.Exec(query, args...)
-	return err
+	return results, ctx.Err()
 }
 
-// syncUsers 同步用户表
-func (s *SyncService) syncUsers(localDB *gorm.DB, remoteDB *sql.DB, dbType string) (int64, string) {
-	var users []models.User
-	if err := localDB.Find(&users).Error; err != nil {
-		return 0, fmt.Sprintf("读取本地数据失败: %v", err)
+// syncTable 同步单张表
+func (s *SyncService) syncTable(ctx context.Context, localDB *gorm.DB, remote *remoteConn, cfg SyncConfig, table *syncTable, opts SyncOptions) SyncResult {
+	result := SyncResult{TableName: table.Name, Mode: opts.Mode, Conflicts: []SyncConflict{}}
+
+	// 1. 读取水位线，决定全量或增量
+	state, err := s.syncRepo.FindState(cfg.targetKey(), table.Name)
+	if err != nil {
+		result.ErrorMessage = fmt.Sprintf("读取同步状态失败: %v", err)
+		return result
 	}
+	result.Incremental = !opts.Full && state.LastSyncTime != nil
 
-	var ids []interface{}
-	for _, u := range users {
-		ids = append(ids, u.ID)
-		query := s.buildUpsertQuery("users", []string{"id", "username", "password", "name", "email", "phone", "avatar", "role", "department", "position", "status", "create_time", "update_time"}, dbType)
-		_, err := remoteDB.Exec(query, u.ID, u.Username, u.Password, u.Name, u.Email, u.Phone, u.Avatar, u.Role, u.Department, u.Position, u.Status, u.CreateTime, u.UpdateTime)
-		if err != nil {
-			return 0, fmt.Sprintf("同步失败: %v", err)
+	// 2. 收集两侧变更 (推送无需读取云端)
+	start := time.Now()
+	local, err := s.localChanges(localDB, table, state, result.Incremental)
+	if err != nil {
+		result.ErrorMessage = fmt.Sprintf("读取本地数据失败: %v", err)
+		return result
+	}
+	var remoteChanges *changeSet
+	if opts.Mode != SyncModePush {
+		if remoteChanges, err = s.remoteChanges(remote, table, state, result.Incremental); err != nil {
+			result.ErrorMessage = fmt.Sprintf("读取云端数据失败: %v", err)
+			return result
 		}
 	}
 
-	// 删除多余数据
-	if err := s.deleteExtras(remoteDB, "users", ids, dbType); err != nil {
-		// 忽略删除错误，可能是外键约束，下次同步解决
-		fmt.Printf("清理 users 多余数据失败: %v\n", err)
+	// 3. 冲突检测与解决 (push 以本地为准，不检测冲突)
+	unsolved := false
+	if opts.Mode != SyncModePush {
+		result.Conflicts = append(result.Conflicts, detectConflicts(table.Name, local, remoteChanges, opts.Policy)...)
+		applyResolutions(result.Conflicts, local, remoteChanges)
+		for _, c := range result.Conflicts {
+			if c.Resolution == resolutionUnsolved {
+				unsolved = true
+			}
+		}
 	}
 
-	return int64(len(users)), ""
-}
-
-// syncProjects 同步项目表
-func (s *SyncService) syncProjects(localDB *gorm.DB, remoteDB *sql.DB, dbType string) (int64, string) {
-	var projects []models.Project
-	if err := localDB.Find(&projects).Error; err != nil {
-		return 0, fmt.Sprintf("读取本地数据失败: %v", err)
+	// 4. 在云端、本地事务中写入变更，任一步失败整表回滚
+	var total int
+	if opts.Mode != SyncModePull {
+		total += len(local.rows) + len(local.deletes)
 	}
-
-	var ids []interface{}
-	for _, p := range projects {
-		ids = append(ids, p.ID)
-		query := s.buildUpsertQuery("projects", []string{"id", "name", "company", "total_amount", "received_amount", "status", "type", "contract_number", "contract_date", "payment_method", "start_date", "end_date", "description", "user_id", "create_time", "update_time"}, dbType)
-		_, err := remoteDB.Exec(query, p.ID, p.Name, p.Company, p.TotalAmount, p.ReceivedAmount, p.Status, p.Type, p.ContractNumber, p.ContractDate, p.PaymentMethod, p.StartDate, p.EndDate, p.Description, p.UserID, p.CreateTime, p.UpdateTime)
-		if err != nil {
-			return 0, fmt.Sprintf("同步失败: %v", err)
+	if opts.Mode != SyncModePush {
+		total += len(remoteChanges.rows) + len(remoteChanges.deletes)
+	}
+	progress := newSyncProgress(table.Name, int64(total), opts.Progress)
+	counts, err := s.applyChanges(ctx, localDB, remote, table, local, remoteChanges, opts.Mode, result.Incremental, progress)
+	if err != nil {
+		result.ErrorMessage = err.Error()
+		return result
+	}
+	result.SyncedCount = counts.synced
+	result.DeletedCount = counts.deleted
+	result.PulledCount = counts.pulled
+	result.LocalDeletedCount = counts.localDeleted
+
+	// 5. 推进并持久化水位线 (存在未解决冲突时保持原水位线)
+	// 本地水位线只由本地记录推进，云端水位线只由云端分配的变更序号 (及兼容旧水位线的云端记录) 推进。
+	if !unsolved {
+		if opts.Mode != SyncModePull {
+			state.LastUpdateTime = laterOf(state.LastUpdateTime, local.rowMax)
+			state.LastTombstoneTime = laterOf(state.LastTombstoneTime, local.deleteMax)
+		}
+		if opts.Mode != SyncModePush {
+			state.LastRemoteSeq = max(state.LastRemoteSeq, remoteChanges.seq)
+			state.LastRemoteUpdate = laterOf(state.LastRemoteUpdate, remoteChanges.rowMax)
+			state.LastRemoteDelete = laterOf(state.LastRemoteDelete, remoteChanges.deleteMax)
+			// 拉取的记录保持云端的更新时间，本地没有待推送变更 (或已在本次推送) 时无需再推送回去；
+			// 以本次同步开始时间为上限，其他设备时钟超前时也不会跳过本地随后的修改
+			if opts.Mode == SyncModeMerge || local.empty() {
+				state.LastUpdateTime = laterOf(state.LastUpdateTime, earlierOf(remoteChanges.rowMax, start))
+			}
+		}
+		// 分配的序号紧接在已拉取的序号之后，说明期间没有其他设备推送，刚推送的记录无需再拉取回来
+		// (增量推送且尚未按序号拉取过时除外: 云端可能还有旧版本写入、未登记到变更日志的记录)
+		legacy := opts.Mode == SyncModePush && result.Incremental && state.LastRemoteSeq == 0
+		if counts.seq > 0 && counts.seq == state.LastRemoteSeq+1 && !legacy {
+			state.LastRemoteSeq = counts.seq
 		}
 	}
-
-	if err := s.deleteExtras(remoteDB, "projects", ids, dbType); err != nil {
-		fmt.Printf("清理 projects 多余数据失败: %v\n", err)
+	now := time.Now()
+	state.LastSyncTime = &now
+	if err := s.syncRepo.SaveState(state); err != nil {
+		result.ErrorMessage = fmt.Sprintf("保存同步状态失败: %v", err)
+		return result
 	}
 
-	return int64(len(projects)), ""
+	result.Success = true
+	return result
 }
 
-// syncPayments 同步收款表
-func (s *SyncService) syncPayments(localDB *gorm.DB, remoteDB *sql.DB, dbType string) (int64, string) {
-	var payments []models.Payment
-	if err := localDB.Find(&payments).Error; err != nil {
-		return 0, fmt.Sprintf("读取本地数据失败: %v", err)
-	}
+// localChanges 收集本地变更
+// 增量: 水位线之后的记录与删除墓碑；全量: 全部记录，墓碑仅用于推进水位线。
+func (s *SyncService) localChanges(localDB *gorm.DB, table *syncTable, state *models.SyncState, incremental bool) (*changeSet, error) {
+	changes := newChangeSet()
 
-	var ids []interface{}
-	for _, p := range payments {
-		ids = append(ids, p.ID)
-		query := s.buildUpsertQuery("payments", []string{"id", "project_id", "stage", "amount", "percentage", "plan_date", "status", "actual_date", "method", "remark", "user_id", "create_time", "update_time"}, dbType)
-		_, err := remoteDB.Exec(query, p.ID, p.ProjectID, p.Stage, p.Amount, p.Percentage, p.PlanDate, p.Status, p.ActualDate, p.Method, p.Remark, p.UserID, p.CreateTime, p.UpdateTime)
-		if err != nil {
-			return 0, fmt.Sprintf("同步失败: %v", err)
-		}
+	var since *time.Time
+	if incremental {
+		since = state.LastUpdateTime
 	}
-
-	if err := s.deleteExtras(remoteDB, "payments", ids, dbType); err != nil {
-		fmt.Printf("清理 payments 多余数据失败: %v\n", err)
+	rows, err := table.Load(localDB, since)
+	if err != nil {
+		return nil, err
 	}
-
-	return int64(len(payments)), ""
-}
-
-// syncDictionaries 同步字典表
-func (s *SyncService) syncDictionaries(localDB *gorm.DB, remoteDB *sql.DB, dbType string) (int64, string) {
-	var dicts []models.Dictionary
-	if err := localDB.Find(&dicts).Error; err != nil {
-		return 0, fmt.Sprintf("读取本地数据失败: %v", err)
+	for _, row := range rows {
+		changes.addRow(row)
 	}
 
-	var ids []interface{}
-	for _, d := range dicts {
-		ids = append(ids, d.ID)
-		query := s.buildUpsertQuery("dictionaries", []string{"id", "code", "name", "status", "remark", "create_time", "update_time"}, dbType)
-		_, err := remoteDB.Exec(query, d.ID, d.Code, d.Name, d.Status, d.Remark, d.CreateTime, d.UpdateTime)
-		if err != nil {
-			return 0, fmt.Sprintf("同步失败: %v", err)
-		}
+	if !incremental {
+		changes.deleteMax, err = s.syncRepo.LatestTombstoneTime(table.Name)
+		return changes, err
 	}
-
-	if err := s.deleteExtras(remoteDB, "dictionaries", ids, dbType); err != nil {
-		fmt.Printf("清理 dictionaries 多余数据失败: %v\n", err)
+	tombstones, err := s.syncRepo.ListTombstones(table.Name, state.LastTombstoneTime)
+	if err != nil {
+		return nil, err
 	}
-
-	return int64(len(dicts)), ""
+	for _, t := range tombstones {
+		changes.addDelete(t.RecordID, t.DeleteTime)
+	}
+	return changes, nil
 }
 
-// syncDictionaryItems 同步字典项表
-func (s *SyncService) syncDictionaryItems(localDB *gorm.DB, remoteDB *sql.DB, dbType string) (int64, string) {
-	var items []models.DictionaryItem
-	if err := localDB.Find(&items).Error; err != nil {
-		return 0, fmt.Sprintf("读取本地数据失败: %v", err)
+// remoteChanges 收集云端变更
+// 先读取云端当前的变更序号 (序号与变更日志在同一事务中写入，读到的序号及之前的变更均已提交)。
+// 增量: 变更日志中序号晚于水位线的记录及其删除墓碑；全量: 全部记录，墓碑仅用于统计。
+// 水位线尚无变更序号时 (云端由旧版本写入)，另按更新时间读取未登记到变更日志的记录。
+func (s *SyncService) remoteChanges(remote *remoteConn, table *syncTable, state *models.SyncState, incremental bool) (*changeSet, error) {
+	changes := newChangeSet()
+
+	seq, err := s.remoteSequence(remote)
+	if err != nil {
+		return nil, err
 	}
+	changes.seq = seq
 
-	var ids []interface{}
-	for _, item := range items {
-		ids = append(ids, item.ID)
-		query := s.buildUpsertQuery("dictionary_item", []string{"id", "dictionary_id", "label", "value", "sort", "status", "remark", "create_time", "update_time"}, dbType)
-		_, err := remoteDB.Exec(query, item.ID, item.DictionaryID, item.Label, item.Value, item.Sort, item.Status, item.Remark, item.CreateTime, item.UpdateTime)
+	if !incremental {
+		rows, err := table.Load(remote.gormDB, nil)
 		if err != nil {
-			return 0, fmt.Sprintf("同步失败: %v", err)
+			return nil, err
 		}
+		for _, row := range rows {
+			changes.addRow(row)
+		}
+		tombstones, err := s.listRemoteTombstones(remote, table.Name, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range tombstones {
+			changes.deleteMax = maxTime(changes.deleteMax, t.DeleteTime)
+		}
+		return changes, nil
 	}
 
-	if err := s.deleteExtras(remoteDB, "dictionary_item", ids, dbType); err != nil {
-		fmt.Printf("清理 dictionary_item 多余数据失败: %v\n", err)
-	}
-
-	return int64(len(items)), ""
-}
-
-// syncNotifications 同步通知表
-func (s *SyncService) syncNotifications(localDB *gorm.DB, remoteDB *sql.DB, dbType string) (int64, string) {
-	var notifications []models.Notification
-	if err := localDB.Find(&notifications).Error; err != nil {
-		return 0, fmt.Sprintf("读取本地数据失败: %v", err)
+	// 1. 按变更序号读取
+	ids, err := s.listRemoteChanges(remote, table.Name, state.LastRemoteSeq, seq)
+	if err != nil {
+		return nil, err
 	}
-
-	var ids []interface{}
-	for _, n := range notifications {
-		ids = append(ids, n.ID)
-		query := s.buildUpsertQuery("notifications", []string{"id", "title", "content", "type", "sender_id", "is_global", "create_time", "update_time"}, dbType)
-		_, err := remoteDB.Exec(query, n.ID, n.Title, n.Content, n.Type, n.SenderID, n.IsGlobal, n.CreateTime, n.UpdateTime)
+	for start := 0; start < len(ids); start += syncBatchSize {
+		batch := ids[start:min(start+syncBatchSize, len(ids))]
+		rows, err := table.Load(remote.gormDB.Where("id IN ?", batch), nil)
 		if err != nil {
-			return 0, fmt.Sprintf("同步失败: %v", err)
+			return nil, err
+		}
+		for _, row := range rows {
+			changes.addRow(row)
+		}
+		var tombstones []models.SyncTombstone
+		if err := remote.gormDB.Where("table_name = ? AND record_id IN ?", table.Name, batch).Find(&tombstones).Error; err != nil {
+			return nil, err
+		}
+		for _, t := range tombstones {
+			changes.addDelete(t.RecordID, t.DeleteTime)
 		}
 	}
-
-	if err := s.deleteExtras(remoteDB, "notifications", ids, dbType); err != nil {
-		fmt.Printf("清理 notifications 多余数据失败: %v\n", err)
+	if state.LastRemoteSeq > 0 {
+		return changes, nil
 	}
 
-	return int64(len(notifications)), ""
-}
-
-// syncUserNotifications 同步用户通知关联表
-func (s *SyncService) syncUserNotifications(localDB *gorm.DB, remoteDB *sql.DB, dbType string) (int64, string) {
-	var userNotifications []models.UserNotification
-	if err := localDB.Find(&userNotifications).Error; err != nil {
-		return 0, fmt.Sprintf("读取本地数据失败: %v", err)
+	// 2. 兼容启用变更序号前的水位线
+	rows, err := table.Load(remote.gormDB, state.LastRemoteUpdate)
+	if err != nil {
+		return nil, err
 	}
-
-	var ids []interface{}
-	for _, un := range userNotifications {
-		ids = append(ids, un.ID)
-		query := s.buildUpsertQuery("user_notifications", []string{"id", "user_id", "notification_id", "is_read", "read_time"}, dbType)
-		_, err := remoteDB.Exec(query, un.ID, un.UserID, un.NotificationID, un.IsRead, un.ReadTime)
-		if err != nil {
-			return 0, fmt.Sprintf("同步失败: %v", err)
-		}
+	for _, row := range rows {
+		changes.addRow(row)
 	}
-
-	if err := s.deleteExtras(remoteDB, "user_notifications", ids, dbType); err != nil {
-		fmt.Printf("清理 user_notifications 多余数据失败: %v\n", err)
+	tombstones, err := s.listRemoteTombstones(remote, table.Name, state.LastRemoteDelete)
+	if err != nil {
+		return nil, err
 	}
-
-	return int64(len(userNotifications)), ""
+	for _, t := range tombstones {
+		changes.addDelete(t.RecordID, t.DeleteTime)
+	}
+	return changes, nil
 }
 
-// buildUpsertQuery 构建 UPSERT 语句 (支持 PostgreSQL 和 MySQL)
-func (s *SyncService) buildUpsertQuery(table string, columns []string, dbType string) string {
-	// 构建占位符
-	placeholders := ""
-	updateSet := ""
-	for i, col := range columns {
-		if i > 0 {
-			placeholders += ", "
-			if col != "id" {
-				if updateSet != "" {
-					updateSet += ", "
-				}
-			}
-		}
-		if dbType == "postgres" {
-			placeholders += fmt.Sprintf("$%d", i+1)
-			if col != "id" {
-				updateSet += fmt.Sprintf("%s = EXCLUDED.%s", col, col)
-			}
-		} else {
-			placeholders += "?"
-			if col != "id" {
-				updateSet += fmt.Sprintf("%s = VALUES(%s)", col, col)
-			}
-		}
+// remoteSequence 读取云端已提交的最大变更序号，云端尚未创建计数器时返回 0
+func (s *SyncService) remoteSequence(remote *remoteConn) (int64, error) {
+	if !remote.gormDB.Migrator().HasTable(&models.SyncSequence{}) {
+		return 0, nil
 	}
+	var seq int64
+	err := remote.gormDB.Model(&models.SyncSequence{}).Where("id = ?", 1).
+		Select("COALESCE(MAX(seq), 0)").Scan(&seq).Error
+	return seq, err
+}
 
-	colNames := ""
-	for i, col := range columns {
-		if i > 0 {
-			colNames += ", "
-		}
-		colNames += col
+// listRemoteChanges 读取变更日志中序号在 (after, upTo] 之间的记录 ID，云端尚未创建变更日志时返回空
+func (s *SyncService) listRemoteChanges(remote *remoteConn, table string, after, upTo int64) ([]int64, error) {
+	if upTo <= after || !remote.gormDB.Migrator().HasTable(&models.SyncChange{}) {
+		return nil, nil
 	}
+	var ids []int64
+	err := remote.gormDB.Model(&models.SyncChange{}).
+		Where("table_name = ? AND seq > ? AND seq <= ?", table, after, upTo).
+		Order("record_id").Pluck("record_id", &ids).Error
+	return ids, err
+}
 
-	if dbType == "postgres" {
-		return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (id) DO UPDATE SET %s",
-			table, colNames, placeholders, updateSet)
+// listRemoteTombstones 读取云端删除墓碑，云端尚未创建墓碑表时返回空
+func (s *SyncService) listRemoteTombstones(remote *remoteConn, table string, since *time.Time) ([]models.SyncTombstone, error) {
+	if !remote.gormDB.Migrator().HasTable(&models.SyncTombstone{}) {
+		return nil, nil
+	}
+	var tombstones []models.SyncTombstone
+	query := remote.gormDB.Where("table_name = ?", table)
+	if since != nil {
+		query = query.Where("delete_time > ?", *since)
 	}
-	// MySQL
-	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) ON DUPLICATE KEY UPDATE %s",
-		table, colNames, placeholders, updateSet)
+	err := query.Order("delete_time ASC").Find(&tombstones).Error
+	return tombstones, err
 }
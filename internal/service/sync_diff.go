@@ -0,0 +1,209 @@
+package service
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/database"
+)
+
+// syncDiffSampleLimit 每张表返回的字段级差异样例数
+const syncDiffSampleLimit = 20
+
+// syncMaskedColumns 差异样例中不展示原值的列
+var syncMaskedColumns = map[string]bool{"password": true}
+
+// SyncFieldDiff 单个字段的差异
+type SyncFieldDiff struct {
+	Column string `json:"column"` // 列名
+	Local  string `json:"local"`  // 本地值 (规范化后)
+	Remote string `json:"remote"` // 云端值 (规范化后)
+}
+
+// SyncRowDiff 单条记录的字段级差异
+type SyncRowDiff struct {
+	ID     int64           `json:"id"`     // 记录ID
+	Fields []SyncFieldDiff `json:"fields"` // 不一致的字段
+}
+
+// TableDiffResult 表的行级对比结果
+type TableDiffResult struct {
+	TableName    string        `json:"table_name"`    // 表名
+	LocalCount   int64         `json:"local_count"`   // 本地记录数
+	RemoteCount  int64         `json:"remote_count"`  // 云端记录数 (-1 表示表不存在或读取失败)
+	SameCount    int64         `json:"same_count"`    // 内容一致的记录数
+	LocalOnly    []int64       `json:"local_only"`    // 仅本地存在的记录ID
+	RemoteOnly   []int64       `json:"remote_only"`   // 仅云端存在的记录ID
+	Changed      []int64       `json:"changed"`       // 两侧都存在但内容不同的记录ID
+	Samples      []SyncRowDiff `json:"samples"`       // 字段级差异样例 (最多 syncDiffSampleLimit 条)
+	ErrorMessage string        `json:"error_message"` // 错误信息
+}
+
+// DiffData 逐行对比本地与云端数据
+// 两侧各自读取整表，按行计算规范化后的内容哈希，返回仅本地存在、仅云端存在及内容不同的记录ID，
+// 并给出部分记录的字段级差异，用于在执行同步前确认同步将修改哪些数据。
+// tables 为空时对比全部同步表。结果与水位线无关，不修改任何数据。
+func (s *SyncService) DiffData(cfg SyncConfig, tables []string) ([]TableDiffResult, error) {
+	targets := make([]*syncTable, 0, len(syncTables))
+	if len(tables) == 0 {
+		for i := range syncTables {
+			targets = append(targets, &syncTables[i])
+		}
+	} else {
+		for _, name := range tables {
+			table := findSyncTable(name)
+			if table == nil {
+				return nil, fmt.Errorf("不支持同步的表: %s", name)
+			}
+			targets = append(targets, table)
+		}
+	}
+
+	remote, err := s.openRemote(cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer remote.Close()
+
+	localDB := database.GetDB()
+
+	results := make([]TableDiffResult, 0, len(targets))
+	for _, table := range targets {
+		result := TableDiffResult{
+			TableName:  table.Name,
+			LocalOnly:  []int64{},
+			RemoteOnly: []int64{},
+			Changed:    []int64{},
+			Samples:    []SyncRowDiff{},
+		}
+
+		localRows, err := table.Load(localDB, nil)
+		if err != nil {
+			return nil, fmt.Errorf("读取本地数据失败: %w", err)
+		}
+		result.LocalCount = int64(len(localRows))
+
+		if !remote.gormDB.Migrator().HasTable(table.Name) {
+			result.RemoteCount = -1
+			result.ErrorMessage = "云端表不存在"
+			result.LocalOnly = append(result.LocalOnly, rowIDs(localRows)...)
+			results = append(results, result)
+			continue
+		}
+		remoteRows, err := table.Load(remote.gormDB, nil)
+		if err != nil {
+			result.RemoteCount = -1
+			result.ErrorMessage = fmt.Sprintf("读取云端数据失败: %v", err)
+			results = append(results, result)
+			continue
+		}
+		result.RemoteCount = int64(len(remoteRows))
+
+		diffRows(&result, table.Columns, localRows, remoteRows)
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// diffRows 按 ID 对齐两侧记录并比较内容哈希
+func diffRows(result *TableDiffResult, columns []string, localRows, remoteRows []syncRow) {
+	remoteByID := make(map[int64]syncRow, len(remoteRows))
+	for _, row := range remoteRows {
+		remoteByID[row.ID] = row
+	}
+
+	for _, local := range localRows {
+		remote, ok := remoteByID[local.ID]
+		if !ok {
+			result.LocalOnly = append(result.LocalOnly, local.ID)
+			continue
+		}
+		delete(remoteByID, local.ID)
+
+		localValues := normalizeRow(columns, local.Values)
+		remoteValues := normalizeRow(columns, remote.Values)
+		if rowHash(localValues) == rowHash(remoteValues) {
+			result.SameCount++
+			continue
+		}
+
+		result.Changed = append(result.Changed, local.ID)
+		if len(result.Samples) < syncDiffSampleLimit {
+			result.Samples = append(result.Samples, SyncRowDiff{
+				ID:     local.ID,
+				Fields: diffFields(columns, localValues, remoteValues),
+			})
+		}
+	}
+
+	for id := range remoteByID {
+		result.RemoteOnly = append(result.RemoteOnly, id)
+	}
+	sort.Slice(result.RemoteOnly, func(i, j int) bool { return result.RemoteOnly[i] < result.RemoteOnly[j] })
+}
+
+// diffFields 列出不一致的字段，敏感列不展示原值
+func diffFields(columns, localValues, remoteValues []string) []SyncFieldDiff {
+	fields := make([]SyncFieldDiff, 0)
+	for i, column := range columns {
+		if localValues[i] == remoteValues[i] {
+			continue
+		}
+		diff := SyncFieldDiff{Column: column, Local: localValues[i], Remote: remoteValues[i]}
+		if syncMaskedColumns[column] {
+			diff.Local, diff.Remote = "******", "******"
+		}
+		fields = append(fields, diff)
+	}
+	return fields
+}
+
+// rowHash 计算规范化后整行内容的哈希
+func rowHash(values []string) [sha256.Size]byte {
+	return sha256.Sum256([]byte(strings.Join(values, "\x1f")))
+}
+
+// normalizeRow 将一行的列值规范化为字符串
+// 不同数据库对同一值的表示存在差异 (时间精度与时区、DATE 列、浮点精度)，规范化后再比较，
+// 避免把仅存储格式不同的记录误报为变更。
+func normalizeRow(columns []string, values []interface{}) []string {
+	normalized := make([]string, len(values))
+	for i, v := range values {
+		normalized[i] = normalizeValue(v, strings.HasSuffix(columns[i], "_date"))
+	}
+	return normalized
+}
+
+// normalizeValue 规范化单个值，isDate 表示该列为 DATE 类型 (仅比较日期部分)
+func normalizeValue(v interface{}, isDate bool) string {
+	if v == nil {
+		return "NULL"
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return "NULL"
+		}
+		return normalizeValue(rv.Elem().Interface(), isDate)
+	}
+
+	switch val := v.(type) {
+	case time.Time:
+		if isDate {
+			return val.Format("2006-01-02")
+		}
+		return val.UTC().Truncate(time.Second).Format(time.RFC3339)
+	case float32:
+		return strconv.FormatFloat(float64(val), 'f', 2, 64)
+	case float64:
+		return strconv.FormatFloat(val, 'f', 2, 64)
+	default:
+		return fmt.Sprint(val)
+	}
+}
@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// 同步任务状态
+const (
+	SyncJobPending   = "pending"   // 等待执行
+	SyncJobRunning   = "running"   // 执行中
+	SyncJobSucceeded = "succeeded" // 全部表同步成功
+	SyncJobFailed    = "failed"    // 存在失败的表或执行出错
+	SyncJobCanceled  = "canceled"  // 已取消
+)
+
+// syncJobRetention 已结束任务在内存中的保留时长
+const syncJobRetention = time.Hour
+
+// ErrSyncJobNotFound 同步任务不存在 (或已过期清理)
+var ErrSyncJobNotFound = errors.New("同步任务不存在")
+
+// SyncTableProgress 单张表的同步进度
+type SyncTableProgress struct {
+	TableName string `json:"table_name"` // 表名
+	Done      int64  `json:"done"`       // 已写入记录数
+	Total     int64  `json:"total"`      // 待写入记录数
+}
+
+// SyncJob 后台同步任务
+type SyncJob struct {
+	ID           string              `json:"id"`            // 任务ID
+	Status       string              `json:"status"`        // 任务状态
+	Mode         string              `json:"mode"`          // 同步方向
+	Progress     []SyncTableProgress `json:"progress"`      // 各表进度 (顺序与提交的表一致)
+	Results      []SyncResult        `json:"results"`       // 各表同步结果 (结束后填充)
+	ErrorMessage string              `json:"error_message"` // 错误信息
+	CreateTime   time.Time           `json:"create_time"`   // 提交时间
+	FinishTime   *time.Time          `json:"finish_time"`   // 结束时间
+}
+
+// Finished 任务是否已结束
+func (j *SyncJob) Finished() bool {
+	return j.Status == SyncJobSucceeded || j.Status == SyncJobFailed || j.Status == SyncJobCanceled
+}
+
+// syncJobEntry 任务及其运行时状态
+type syncJobEntry struct {
+	job         SyncJob
+	cancel      context.CancelFunc
+	subscribers map[chan struct{}]struct{}
+}
+
+// syncJobManager 同步任务管理器
+// 任务保存在内存中，应用重启后丢失。
+type syncJobManager struct {
+	mu   sync.Mutex
+	jobs map[string]*syncJobEntry
+}
+
+// syncJobs 全局任务管理器 (Handler 每次创建新的 SyncService，任务需跨实例共享)
+var syncJobs = &syncJobManager{jobs: make(map[string]*syncJobEntry)}
+
+// SubmitJob 提交后台同步任务，立即返回任务快照
+// 任务在独立的 goroutine 中执行，可通过 GetJob 轮询、SubscribeJob 订阅进度，CancelJob 取消。
+func (s *SyncService) SubmitJob(cfg SyncConfig, opts SyncOptions) SyncJob {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	mode := opts.Mode
+	if mode == "" {
+		mode = SyncModePush
+	}
+	progress := make([]SyncTableProgress, len(opts.Tables))
+	for i, name := range opts.Tables {
+		progress[i] = SyncTableProgress{TableName: name}
+	}
+	entry := &syncJobEntry{
+		job: SyncJob{
+			ID:         uuid.NewString(),
+			Status:     SyncJobPending,
+			Mode:       mode,
+			Progress:   progress,
+			Results:    []SyncResult{},
+			CreateTime: time.Now(),
+		},
+		cancel:      cancel,
+		subscribers: make(map[chan struct{}]struct{}),
+	}
+	syncJobs.add(entry)
+	snapshot, _ := syncJobs.get(entry.job.ID)
+
+	// 进度回调在同步 goroutine 中调用
+	opts.Progress = func(table string, done, total int64) {
+		syncJobs.update(snapshot.ID, func(job *SyncJob) {
+			for i := range job.Progress {
+				if job.Progress[i].TableName == table {
+					job.Progress[i].Done = done
+					job.Progress[i].Total = total
+				}
+			}
+		})
+	}
+
+	go func() {
+		defer cancel()
+		syncJobs.update(snapshot.ID, func(job *SyncJob) { job.Status = SyncJobRunning })
+
+		results, err := s.RunSync(ctx, SyncTriggerManual, cfg, opts)
+
+		syncJobs.finish(snapshot.ID, func(job *SyncJob) {
+			now := time.Now()
+			job.FinishTime = &now
+			if results != nil {
+				job.Results = results
+			}
+			switch {
+			case errors.Is(err, context.Canceled):
+				job.Status = SyncJobCanceled
+				job.ErrorMessage = "同步已取消"
+			case err != nil:
+				job.Status = SyncJobFailed
+				job.ErrorMessage = err.Error()
+			default:
+				job.Status = SyncJobSucceeded
+				for _, r := range results {
+					if !r.Success {
+						job.Status = SyncJobFailed
+					}
+				}
+			}
+		})
+	}()
+
+	return snapshot
+}
+
+// GetJob 获取任务快照
+func (s *SyncService) GetJob(id string) (SyncJob, error) {
+	return syncJobs.get(id)
+}
+
+// CancelJob 取消任务
+// 正在同步的表整表回滚，尚未开始的表不再同步；已结束的任务不受影响。
+func (s *SyncService) CancelJob(id string) (SyncJob, error) {
+	syncJobs.mu.Lock()
+	entry, ok := syncJobs.jobs[id]
+	syncJobs.mu.Unlock()
+	if !ok {
+		return SyncJob{}, ErrSyncJobNotFound
+	}
+	entry.cancel()
+	return syncJobs.get(id)
+}
+
+// SubscribeJob 订阅任务进度
+// 任务每次更新时向返回的通道发送信号 (多次更新可能合并为一次)，调用方收到信号后
+// 通过 GetJob 读取最新快照；任务结束后通道关闭。使用完毕须调用返回的取消订阅函数。
+func (s *SyncService) SubscribeJob(id string) (<-chan struct{}, func(), error) {
+	syncJobs.mu.Lock()
+	defer syncJobs.mu.Unlock()
+
+	entry, ok := syncJobs.jobs[id]
+	if !ok {
+		return nil, nil, ErrSyncJobNotFound
+	}
+
+	ch := make(chan struct{}, 1)
+	if entry.job.Finished() {
+		close(ch)
+		return ch, func() {}, nil
+	}
+	entry.subscribers[ch] = struct{}{}
+
+	unsubscribe := func() {
+		syncJobs.mu.Lock()
+		defer syncJobs.mu.Unlock()
+		if _, ok := entry.subscribers[ch]; ok {
+			delete(entry.subscribers, ch)
+			close(ch)
+		}
+	}
+	return ch, unsubscribe, nil
+}
+
+// add 登记新任务，同时清理过期的已结束任务
+func (m *syncJobManager) add(entry *syncJobEntry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, e := range m.jobs {
+		if e.job.FinishTime != nil && time.Since(*e.job.FinishTime) > syncJobRetention {
+			delete(m.jobs, id)
+		}
+	}
+	m.jobs[entry.job.ID] = entry
+}
+
+// get 获取任务快照 (复制切片，避免与同步 goroutine 共享)
+func (m *syncJobManager) get(id string) (SyncJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.jobs[id]
+	if !ok {
+		return SyncJob{}, ErrSyncJobNotFound
+	}
+	job := entry.job
+	job.Progress = append(make([]SyncTableProgress, 0, len(job.Progress)), job.Progress...)
+	job.Results = append(make([]SyncResult, 0, len(job.Results)), job.Results...)
+	return job, nil
+}
+
+// update 修改任务并通知订阅者
+func (m *syncJobManager) update(id string, fn func(job *SyncJob)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.jobs[id]
+	if !ok {
+		return
+	}
+	fn(&entry.job)
+	for ch := range entry.subscribers {
+		select {
+		case ch <- struct{}{}:
+		default: // 订阅者尚未处理上一次通知，合并
+		}
+	}
+}
+
+// finish 标记任务结束并关闭所有订阅通道
+func (m *syncJobManager) finish(id string, fn func(job *SyncJob)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.jobs[id]
+	if !ok {
+		return
+	}
+	fn(&entry.job)
+	for ch := range entry.subscribers {
+		delete(entry.subscribers, ch)
+		close(ch)
+	}
+}
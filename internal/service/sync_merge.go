@@ -0,0 +1,244 @@
+package service
+
+import (
+	"sort"
+	"time"
+)
+
+// 同步方向
+const (
+	SyncModePush  = "push"  // 推送: 本地覆盖云端 (默认)
+	SyncModePull  = "pull"  // 拉取: 云端变更写入本地
+	SyncModeMerge = "merge" // 双向合并: 推送本地变更并拉取云端变更
+)
+
+// 冲突解决策略
+const (
+	ConflictLastWriterWins = "last_writer_wins" // 以更新时间较晚的一方为准 (默认，时间相同时本地优先)
+	ConflictLocalWins      = "local_wins"       // 以本地为准
+	ConflictRemoteWins     = "remote_wins"      // 以云端为准
+	ConflictManual         = "manual"           // 不自动解决，仅报告，由用户手动处理
+)
+
+// 变更动作
+const (
+	changeUpdate = "update" // 新增或修改
+	changeDelete = "delete" // 删除
+)
+
+// 冲突处理结果
+const (
+	resolutionLocal    = "local"    // 采用本地版本
+	resolutionRemote   = "remote"   // 采用云端版本
+	resolutionUnsolved = "unsolved" // 未解决 (manual 策略)
+)
+
+// SyncConflict 同步冲突
+// 同一条记录自上次同步以来在本地和云端都发生了变更。
+type SyncConflict struct {
+	TableName    string    `json:"table_name"`    // 表名
+	RecordID     int64     `json:"record_id"`     // 记录ID
+	LocalAction  string    `json:"local_action"`  // 本地变更: update/delete
+	RemoteAction string    `json:"remote_action"` // 云端变更: update/delete
+	LocalTime    time.Time `json:"local_time"`    // 本地变更时间
+	RemoteTime   time.Time `json:"remote_time"`   // 云端变更时间
+	Resolution   string    `json:"resolution"`    // 处理结果: local/remote/unsolved
+}
+
+// validSyncMode 校验同步方向
+func validSyncMode(mode string) bool {
+	return mode == SyncModePush || mode == SyncModePull || mode == SyncModeMerge
+}
+
+// validConflictPolicy 校验冲突解决策略
+func validConflictPolicy(policy string) bool {
+	switch policy {
+	case ConflictLastWriterWins, ConflictLocalWins, ConflictRemoteWins, ConflictManual:
+		return true
+	}
+	return false
+}
+
+// changeSet 一侧 (本地或云端) 自上次同步以来的变更集合
+type changeSet struct {
+	rows      map[int64]syncRow   // 新增/修改的记录
+	deletes   map[int64]time.Time // 删除的记录 ID -> 删除时间
+	rowMax    *time.Time          // 变更记录的最大 update_time
+	deleteMax *time.Time          // 删除墓碑的最大 delete_time
+	seq       int64               // 读取时云端已提交的最大变更序号 (仅云端)
+}
+
+// newChangeSet 创建空的变更集合
+func newChangeSet() *changeSet {
+	return &changeSet{
+		rows:    make(map[int64]syncRow),
+		deletes: make(map[int64]time.Time),
+	}
+}
+
+// addRow 记录一条新增/修改
+func (c *changeSet) addRow(row syncRow) {
+	c.rows[row.ID] = row
+	c.rowMax = maxTime(c.rowMax, row.UpdateTime)
+}
+
+// addDelete 记录一条删除
+// 同一 ID 既有删除又有更新时 (删除后 ID 被复用) 以时间较晚者为准。
+func (c *changeSet) addDelete(id int64, deleteTime time.Time) {
+	c.deleteMax = maxTime(c.deleteMax, deleteTime)
+	if row, ok := c.rows[id]; ok && !deleteTime.After(row.UpdateTime) {
+		return
+	}
+	delete(c.rows, id)
+	c.deletes[id] = deleteTime
+}
+
+// change 获取指定记录的变更动作与时间
+func (c *changeSet) change(id int64) (action string, at time.Time, ok bool) {
+	if row, ok := c.rows[id]; ok {
+		return changeUpdate, row.UpdateTime, true
+	}
+	if t, ok := c.deletes[id]; ok {
+		return changeDelete, t, true
+	}
+	return "", time.Time{}, false
+}
+
+// empty 是否没有任何变更
+func (c *changeSet) empty() bool {
+	return len(c.rows) == 0 && len(c.deletes) == 0
+}
+
+// sortedRows 按 ID 升序返回变更记录
+func (c *changeSet) sortedRows() []syncRow {
+	rows := make([]syncRow, 0, len(c.rows))
+	for _, row := range c.rows {
+		rows = append(rows, row)
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].ID < rows[j].ID })
+	return rows
+}
+
+// deleteIDs 按 ID 升序返回删除的记录
+func (c *changeSet) deleteIDs() []int64 {
+	ids := make([]int64, 0, len(c.deletes))
+	for id := range c.deletes {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// drop 从变更集合中移除指定记录 (冲突中落败或未解决的一方)
+func (c *changeSet) drop(id int64) {
+	delete(c.rows, id)
+	delete(c.deletes, id)
+}
+
+// detectConflicts 找出两侧都发生变更的记录并按策略给出处理结果
+// 两侧都删除、或两侧 update_time 相同 (同一版本，如上次同步的回写) 不视为冲突。
+func detectConflicts(table string, local, remote *changeSet, policy string) []SyncConflict {
+	var conflicts []SyncConflict
+	for _, id := range unionIDs(local, remote) {
+		localAction, localTime, inLocal := local.change(id)
+		remoteAction, remoteTime, inRemote := remote.change(id)
+		if !inLocal || !inRemote {
+			continue
+		}
+		if localAction == changeDelete && remoteAction == changeDelete {
+			continue
+		}
+		if localAction == remoteAction && localTime.Truncate(time.Second).Equal(remoteTime.Truncate(time.Second)) {
+			continue
+		}
+		conflicts = append(conflicts, SyncConflict{
+			TableName:    table,
+			RecordID:     id,
+			LocalAction:  localAction,
+			RemoteAction: remoteAction,
+			LocalTime:    localTime,
+			RemoteTime:   remoteTime,
+			Resolution:   resolveConflict(policy, localTime, remoteTime),
+		})
+	}
+	return conflicts
+}
+
+// resolveConflict 按策略决定冲突采用哪一方
+func resolveConflict(policy string, localTime, remoteTime time.Time) string {
+	switch policy {
+	case ConflictLocalWins:
+		return resolutionLocal
+	case ConflictRemoteWins:
+		return resolutionRemote
+	case ConflictManual:
+		return resolutionUnsolved
+	default: // last_writer_wins
+		if remoteTime.After(localTime) {
+			return resolutionRemote
+		}
+		return resolutionLocal
+	}
+}
+
+// applyResolutions 根据冲突处理结果裁剪两侧变更集合
+// 采用本地的记录不再拉取，采用云端的记录不再推送，未解决的记录两侧都不处理。
+func applyResolutions(conflicts []SyncConflict, local, remote *changeSet) {
+	for _, c := range conflicts {
+		switch c.Resolution {
+		case resolutionLocal:
+			remote.drop(c.RecordID)
+		case resolutionRemote:
+			local.drop(c.RecordID)
+		default:
+			local.drop(c.RecordID)
+			remote.drop(c.RecordID)
+		}
+	}
+}
+
+// unionIDs 返回两侧变更涉及的全部记录 ID (升序)
+func unionIDs(a, b *changeSet) []int64 {
+	seen := make(map[int64]bool)
+	var ids []int64
+	for _, c := range []*changeSet{a, b} {
+		for id := range c.rows {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+		for id := range c.deletes {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// maxTime 返回 current 与 t 中较晚的时间
+func maxTime(current *time.Time, t time.Time) *time.Time {
+	if current == nil || t.After(*current) {
+		return &t
+	}
+	return current
+}
+
+// earlierOf 返回可空时间 a 与 b 中较早的一个 (a 为 nil 时返回 nil)
+func earlierOf(a *time.Time, b time.Time) *time.Time {
+	if a == nil || a.Before(b) {
+		return a
+	}
+	return &b
+}
+
+// laterOf 返回两个可空时间中较晚的一个
+func laterOf(a, b *time.Time) *time.Time {
+	if b == nil {
+		return a
+	}
+	return maxTime(a, *b)
+}
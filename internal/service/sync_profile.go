@@ -0,0 +1,220 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/pkg/secret"
+	"github.com/FruitsAI/Orange/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrSyncProfileNotFound 同步连接配置不存在
+	ErrSyncProfileNotFound = errors.New("同步连接配置不存在")
+	// ErrSyncProfileUntrusted 连接配置的创建人已无 sync.manage 权限，不能用于拉取
+	ErrSyncProfileUntrusted = errors.New("该连接配置的创建人已无管理同步的权限，不能用于拉取或双向同步")
+)
+
+// SyncProfileInput 新增/更新同步连接配置的参数
+type SyncProfileInput struct {
+	Name     string
+	DBType   string
+	Path     string // SQLite 文件路径 (仅 sqlite)
+	Host     string
+	Port     int
+	User     string
+	Password string // 更新时为空表示保留原密码
+	DBName   string
+	SSLMode  string
+}
+
+// SyncProfileView 对外展示的连接配置 (不含密码)
+type SyncProfileView struct {
+	models.SyncProfile
+	HasPassword bool `json:"has_password"` // 是否已保存密码
+}
+
+// SyncProfileService 同步连接配置服务
+// 负责连接配置的维护，以及将配置解析为可直接使用的 SyncConfig (解密密码)。
+//
+// 依赖:
+//   - SyncProfileRepository: 连接配置的读写
+//   - UserRepository、RoleService: 校验配置创建人的权限
+type SyncProfileService struct {
+	profileRepo *repository.SyncProfileRepository
+	userRepo    *repository.UserRepository
+	roleService *RoleService
+}
+
+// NewSyncProfileService 创建同步连接配置服务实例
+func NewSyncProfileService() *SyncProfileService {
+	return &SyncProfileService{
+		profileRepo: repository.NewSyncProfileRepository(),
+		userRepo:    repository.NewUserRepository(),
+		roleService: NewRoleService(),
+	}
+}
+
+// List 获取全部连接配置
+func (s *SyncProfileService) List() ([]SyncProfileView, error) {
+	profiles, err := s.profileRepo.List()
+	if err != nil {
+		return nil, err
+	}
+	views := make([]SyncProfileView, 0, len(profiles))
+	for _, p := range profiles {
+		views = append(views, toSyncProfileView(&p))
+	}
+	return views, nil
+}
+
+// Create 新增连接配置
+func (s *SyncProfileService) Create(input SyncProfileInput, userID int64) (*SyncProfileView, error) {
+	if err := s.checkInput(input, 0); err != nil {
+		return nil, err
+	}
+
+	encrypted, err := secret.Encrypt(input.Password)
+	if err != nil {
+		return nil, fmt.Errorf("加密密码失败: %w", err)
+	}
+	profile := &models.SyncProfile{
+		Name:      input.Name,
+		DBType:    input.DBType,
+		Path:      input.Path,
+		Host:      input.Host,
+		Port:      input.Port,
+		User:      input.User,
+		Password:  encrypted,
+		DBName:    input.DBName,
+		SSLMode:   input.SSLMode,
+		CreatedBy: userID,
+	}
+	if err := s.profileRepo.Create(profile); err != nil {
+		return nil, err
+	}
+
+	view := toSyncProfileView(profile)
+	return &view, nil
+}
+
+// Update 更新连接配置，Password 为空时保留原密码
+func (s *SyncProfileService) Update(id int64, input SyncProfileInput) (*SyncProfileView, error) {
+	profile, err := s.find(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkInput(input, id); err != nil {
+		return nil, err
+	}
+
+	profile.Name = input.Name
+	profile.DBType = input.DBType
+	profile.Path = input.Path
+	profile.Host = input.Host
+	profile.Port = input.Port
+	profile.User = input.User
+	profile.DBName = input.DBName
+	profile.SSLMode = input.SSLMode
+	if input.Password != "" {
+		if profile.Password, err = secret.Encrypt(input.Password); err != nil {
+			return nil, fmt.Errorf("加密密码失败: %w", err)
+		}
+	}
+	if err := s.profileRepo.Update(profile); err != nil {
+		return nil, err
+	}
+
+	view := toSyncProfileView(profile)
+	return &view, nil
+}
+
+// Delete 删除连接配置
+func (s *SyncProfileService) Delete(id int64) error {
+	if _, err := s.find(id); err != nil {
+		return err
+	}
+	return s.profileRepo.Delete(id)
+}
+
+// ResolveConfig 将连接配置解析为 SyncConfig (解密密码)
+func (s *SyncProfileService) ResolveConfig(id int64) (SyncConfig, error) {
+	profile, err := s.find(id)
+	if err != nil {
+		return SyncConfig{}, err
+	}
+	password, err := secret.Decrypt(profile.Password)
+	if err != nil {
+		return SyncConfig{}, fmt.Errorf("解密连接密码失败: %w", err)
+	}
+	return SyncConfig{
+		DBType:   profile.DBType,
+		Path:     profile.Path,
+		Host:     profile.Host,
+		Port:     profile.Port,
+		User:     profile.User,
+		Password: password,
+		DBName:   profile.DBName,
+		SSLMode:  profile.SSLMode,
+	}, nil
+}
+
+// ResolvePullConfig 解析用于拉取或双向同步的连接配置
+// 拉取会将云端数据写入本地，要求配置的创建人为正常状态且仍拥有 sync.manage 权限。
+func (s *SyncProfileService) ResolvePullConfig(id int64) (SyncConfig, error) {
+	profile, err := s.find(id)
+	if err != nil {
+		return SyncConfig{}, err
+	}
+	creator, err := s.userRepo.FindByID(profile.CreatedBy)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return SyncConfig{}, ErrSyncProfileUntrusted
+	}
+	if err != nil {
+		return SyncConfig{}, err
+	}
+	if creator.Status != 1 || !s.roleService.HasPermission(creator.Role, PermSyncManage) {
+		return SyncConfig{}, ErrSyncProfileUntrusted
+	}
+	return s.ResolveConfig(id)
+}
+
+// find 查找连接配置，不存在时返回 ErrSyncProfileNotFound
+func (s *SyncProfileService) find(id int64) (*models.SyncProfile, error) {
+	profile, err := s.profileRepo.FindByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrSyncProfileNotFound
+	}
+	return profile, err
+}
+
+// checkInput 校验配置参数
+func (s *SyncProfileService) checkInput(input SyncProfileInput, excludeID int64) error {
+	switch input.DBType {
+	case "sqlite":
+		if input.Path == "" {
+			return errors.New("请指定 SQLite 文件路径")
+		}
+	case "postgres", "mysql":
+		if input.Host == "" || input.Port == 0 || input.User == "" || input.DBName == "" {
+			return errors.New("请填写完整的连接信息")
+		}
+	default:
+		return fmt.Errorf("不支持的数据库类型: %s", input.DBType)
+	}
+	exists, err := s.profileRepo.ExistsByName(input.Name, excludeID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return errors.New("配置名称已存在")
+	}
+	return nil
+}
+
+// toSyncProfileView 转换为对外展示结构
+func toSyncProfileView(p *models.SyncProfile) SyncProfileView {
+	return SyncProfileView{SyncProfile: *p, HasPassword: p.Password != ""}
+}
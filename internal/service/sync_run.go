@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/models"
+)
+
+// 同步触发方式
+const (
+	SyncTriggerManual   = "manual"   // 手动 (页面操作)
+	SyncTriggerSchedule = "schedule" // 定时调度
+)
+
+// 同步执行记录状态
+const (
+	SyncRunRunning  = "running"  // 执行中
+	SyncRunSuccess  = "success"  // 全部表同步成功
+	SyncRunPartial  = "partial"  // 部分表同步失败
+	SyncRunFailed   = "failed"   // 执行出错或全部表失败
+	SyncRunSkipped  = "skipped"  // 已有同步在执行，本次跳过
+	SyncRunCanceled = "canceled" // 已取消
+)
+
+var (
+	// ErrSyncInProgress 已有同步正在执行
+	ErrSyncInProgress = errors.New("已有同步正在执行，请稍后再试")
+	// ErrSyncConnect 连接云端数据库失败 (可重试)
+	ErrSyncConnect = errors.New("连接云端数据库失败")
+)
+
+// syncRunLock 同一时间只允许一次同步，避免并发同步互相覆盖水位线
+var syncRunLock sync.Mutex
+
+// RunSync 执行同步并记录执行历史 (sync_runs)
+func (s *SyncService) RunSync(ctx context.Context, trigger string, cfg SyncConfig, opts SyncOptions) ([]SyncResult, error) {
+	run := s.startRun(trigger, opts)
+	results, err := s.SyncTables(ctx, cfg, opts)
+	s.finishRun(run, results, err)
+	return results, err
+}
+
+// ListRuns 分页获取同步执行记录
+func (s *SyncService) ListRuns(status string, page, pageSize int) ([]models.SyncRun, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+	offset := (page - 1) * pageSize
+	return s.syncRepo.ListRuns(status, offset, pageSize)
+}
+
+// startRun 写入一条执行中的记录
+// 记录历史失败不影响同步本身。
+func (s *SyncService) startRun(trigger string, opts SyncOptions) *models.SyncRun {
+	mode := opts.Mode
+	if mode == "" {
+		mode = SyncModePush
+	}
+	run := &models.SyncRun{
+		Trigger:   trigger,
+		Mode:      mode,
+		Tables:    strings.Join(opts.Tables, ","),
+		Status:    SyncRunRunning,
+		Attempts:  1,
+		StartTime: time.Now(),
+	}
+	_ = s.syncRepo.CreateRun(run)
+	return run
+}
+
+// finishRun 汇总同步结果并更新执行记录
+func (s *SyncService) finishRun(run *models.SyncRun, results []SyncResult, err error) {
+	now := time.Now()
+	run.FinishTime = &now
+
+	failed := 0
+	for _, r := range results {
+		run.SyncedCount += r.SyncedCount
+		run.PulledCount += r.PulledCount
+		run.DeletedCount += r.DeletedCount + r.LocalDeletedCount
+		run.ConflictCount += int64(len(r.Conflicts))
+		if !r.Success {
+			failed++
+		}
+	}
+	if detail, e := json.Marshal(results); e == nil && results != nil {
+		run.Detail = string(detail)
+	}
+
+	switch {
+	case errors.Is(err, ErrSyncInProgress):
+		run.Status = SyncRunSkipped
+		run.ErrorMessage = err.Error()
+	case errors.Is(err, context.Canceled):
+		run.Status = SyncRunCanceled
+		run.ErrorMessage = "同步已取消"
+	case err != nil:
+		run.Status = SyncRunFailed
+		run.ErrorMessage = err.Error()
+	case failed == 0:
+		run.Status = SyncRunSuccess
+	case failed == len(results):
+		run.Status = SyncRunFailed
+	default:
+		run.Status = SyncRunPartial
+	}
+
+	_ = s.syncRepo.SaveRun(run)
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/config"
+	"github.com/robfig/cron/v3"
+)
+
+// SyncScheduler 自动同步调度器
+// 按配置的 cron 表达式或固定间隔在后台执行同步，每次执行写入 sync_runs。
+// 到点时若已有同步在执行 (手动或上一次定时同步) 则跳过本次；
+// 连接云端失败时按指数退避重试，重试次数用尽后记为失败，等待下一个周期。
+type SyncScheduler struct {
+	syncService *SyncService
+	schedule    cron.Schedule
+	target      SyncConfig
+	opts        SyncOptions
+	retryMax    int
+	backoff     time.Duration
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewSyncScheduler 根据全局配置创建自动同步调度器
+// 未配置 SYNC_CRON / SYNC_INTERVAL 或未配置云端数据库时返回 nil (不启用自动同步)。
+func NewSyncScheduler() (*SyncScheduler, error) {
+	cfg := config.AppConfig
+
+	var schedule cron.Schedule
+	switch {
+	case cfg.SyncCron != "":
+		s, err := cron.ParseStandard(cfg.SyncCron)
+		if err != nil {
+			return nil, fmt.Errorf("SYNC_CRON 格式错误: %w", err)
+		}
+		schedule = s
+	case cfg.SyncInterval > 0:
+		schedule = cron.Every(time.Duration(cfg.SyncInterval) * time.Minute)
+	default:
+		return nil, nil
+	}
+
+	if cfg.SyncDBType == "" || (cfg.SyncDBType == "sqlite" && cfg.SyncDBPath == "") || (cfg.SyncDBType != "sqlite" && cfg.SyncDBHost == "") {
+		return nil, errors.New("已配置自动同步，但未配置云端数据库 (SYNC_DB_*)")
+	}
+
+	tables := make([]string, 0, len(syncTables))
+	if cfg.SyncTables == "" {
+		for _, t := range syncTables {
+			tables = append(tables, t.Name)
+		}
+	} else {
+		for _, name := range strings.Split(cfg.SyncTables, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				tables = append(tables, name)
+			}
+		}
+	}
+
+	return &SyncScheduler{
+		syncService: NewSyncService(),
+		schedule:    schedule,
+		target: SyncConfig{
+			DBType:   cfg.SyncDBType,
+			Path:     cfg.SyncDBPath,
+			Host:     cfg.SyncDBHost,
+			Port:     cfg.SyncDBPort,
+			User:     cfg.SyncDBUser,
+			Password: cfg.SyncDBPassword,
+			DBName:   cfg.SyncDBName,
+			SSLMode:  cfg.SyncSSLMode,
+		},
+		opts: SyncOptions{
+			Tables: tables,
+			Mode:   cfg.SyncMode,
+			Policy: cfg.SyncPolicy,
+		},
+		retryMax: cfg.SyncRetryMax,
+		backoff:  time.Duration(cfg.SyncRetryBackoff) * time.Second,
+	}, nil
+}
+
+// Start 在后台启动调度
+func (s *SyncScheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+
+	go func() {
+		defer close(s.done)
+		for {
+			next := s.schedule.Next(time.Now())
+			slog.Info("Next scheduled sync", "time", next)
+
+			timer := time.NewTimer(time.Until(next))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+				s.runOnce(ctx)
+			}
+		}
+	}()
+}
+
+// Stop 停止调度并等待正在执行的同步结束 (正在同步的表整表回滚)
+func (s *SyncScheduler) Stop() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	<-s.done
+}
+
+// runOnce 执行一次定时同步 (含连接失败重试)
+func (s *SyncScheduler) runOnce(ctx context.Context) {
+	run := s.syncService.startRun(SyncTriggerSchedule, s.opts)
+
+	var results []SyncResult
+	var err error
+	for attempt := 1; ; attempt++ {
+		run.Attempts = attempt
+		results, err = s.syncService.SyncTables(ctx, s.target, s.opts)
+		if !errors.Is(err, ErrSyncConnect) || attempt > s.retryMax {
+			break
+		}
+
+		wait := s.backoff << (attempt - 1)
+		slog.Warn("Scheduled sync failed to connect, retrying", "attempt", attempt, "wait", wait, "error", err)
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			err = ctx.Err()
+		case <-timer.C:
+			continue
+		}
+		break
+	}
+
+	s.syncService.finishRun(run, results, err)
+	switch {
+	case errors.Is(err, ErrSyncInProgress):
+		slog.Info("Scheduled sync skipped, another sync is in progress")
+	case err != nil:
+		slog.Error("Scheduled sync failed", "status", run.Status, "error", err)
+	default:
+		slog.Info("Scheduled sync finished", "status", run.Status, "synced", run.SyncedCount, "pulled", run.PulledCount)
+	}
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/FruitsAI/Orange/internal/models"
+	"gorm.io/gorm"
+)
+
+// ProvisionSchema 在云端创建或迁移同步所需的表结构
+// 使用与本地 AutoMigrate 相同的模型定义，tables 为空时处理全部同步表，
+// 同步墓碑表 (sync_tombstones) 与变更日志表 (sync_changes、sync_sequences) 始终包含在内。
+// dryRun 为 true 时不修改云端，仅返回将要执行的 DDL 语句。
+func (s *SyncService) ProvisionSchema(cfg SyncConfig, tables []string, dryRun bool) ([]string, error) {
+	remote, err := s.openRemote(cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer remote.Close()
+
+	return s.provisionSchema(remote, tables, dryRun)
+}
+
+// provisionSchema 在已打开的云端连接上创建或迁移表结构
+func (s *SyncService) provisionSchema(remote *remoteConn, tables []string, dryRun bool) ([]string, error) {
+	targets, err := schemaModels(tables)
+	if err != nil {
+		return nil, err
+	}
+
+	// 记录迁移过程中执行的 DDL
+	// 迁移器的结构查询 (HasTable/ColumnTypes 等) 走 Row 回调照常执行，
+	// DDL 走 Raw 回调: 正常模式下先记录再执行，dry-run 模式下只记录不执行。
+	var statements []string
+	db := remote.gormDB.Session(&gorm.Session{NewDB: true})
+	record := func(tx *gorm.DB) {
+		statements = append(statements, tx.Dialector.Explain(tx.Statement.SQL.String(), tx.Statement.Vars...))
+	}
+	if dryRun {
+		err = db.Callback().Raw().Replace("gorm:raw", record)
+	} else {
+		err = db.Callback().Raw().Before("gorm:raw").Register("orange:record_ddl", record)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.AutoMigrate(targets...); err != nil {
+		return statements, fmt.Errorf("迁移云端表结构失败: %w", err)
+	}
+	if !dryRun {
+		// 初始化变更序号计数器 (已存在时不修改)
+		if err := db.Where(models.SyncSequence{ID: 1}).FirstOrCreate(&models.SyncSequence{ID: 1}).Error; err != nil {
+			return statements, fmt.Errorf("初始化云端变更序号失败: %w", err)
+		}
+	}
+	return statements, nil
+}
+
+// schemaModels 获取需要在云端创建的模型列表 (含同步墓碑表与变更日志表)
+func schemaModels(tables []string) ([]interface{}, error) {
+	targets := make([]interface{}, 0, len(syncTables)+3)
+	if len(tables) == 0 {
+		for _, t := range syncTables {
+			targets = append(targets, t.Model)
+		}
+	} else {
+		for _, name := range tables {
+			table := findSyncTable(name)
+			if table == nil {
+				return nil, fmt.Errorf("未知表名: %s", name)
+			}
+			targets = append(targets, table.Model)
+		}
+	}
+	return append(targets, &models.SyncTombstone{}, &models.SyncChange{}, &models.SyncSequence{}), nil
+}
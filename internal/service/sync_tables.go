@@ -0,0 +1,164 @@
+package service
+
+import (
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/models"
+	"gorm.io/gorm"
+)
+
+// syncRow 待同步的一行数据
+type syncRow struct {
+	ID         int64         // 主键
+	UpdateTime time.Time     // 最后更新时间 (用于推进水位线)
+	Values     []interface{} // 列值，顺序与 syncTable.Columns 一致
+}
+
+// syncTable 参与同步的表定义
+type syncTable struct {
+	Name    string      // 表名
+	Model   interface{} // 对应的模型，用于在云端创建/迁移表结构
+	Columns []string    // 同步的列，第一列固定为 id
+	// PullDefaults 拉取到本地时不采用云端值的列
+	// 已有记录保留本地值，新增记录使用此处的值 (如用户的密码、角色与状态不随云端改变)。
+	PullDefaults map[string]interface{}
+	// Load 读取本地记录
+	// since 为 nil 时读取全表 (全量同步)，否则仅读取 update_time 晚于 since 的记录 (增量同步)。
+	Load func(db *gorm.DB, since *time.Time) ([]syncRow, error)
+}
+
+// loadRows 基于模型构造表读取函数
+func loadRows[T any](toRow func(*T) syncRow) func(*gorm.DB, *time.Time) ([]syncRow, error) {
+	return func(db *gorm.DB, since *time.Time) ([]syncRow, error) {
+		var list []T
+		query := db.Model(new(T))
+		if since != nil {
+			query = query.Where("update_time > ?", *since)
+		}
+		if err := query.Order("id").Find(&list).Error; err != nil {
+			return nil, err
+		}
+
+		rows := make([]syncRow, 0, len(list))
+		for i := range list {
+			rows = append(rows, toRow(&list[i]))
+		}
+		return rows, nil
+	}
+}
+
+// syncTables 所有参与同步的表 (按依赖顺序排列)
+var syncTables = []syncTable{
+	{
+		Name:    "departments",
+		Model:   &models.Department{},
+		Columns: []string{"id", "name", "parent_id", "manager_id", "sort", "create_time", "update_time"},
+		Load: loadRows(func(d *models.Department) syncRow {
+			return syncRow{ID: d.ID, UpdateTime: d.UpdateTime, Values: []interface{}{
+				d.ID, d.Name, d.ParentID, d.ManagerID, d.Sort, d.CreateTime, d.UpdateTime,
+			}}
+		}),
+	},
+	{
+		Name:    "positions",
+		Model:   &models.Position{},
+		Columns: []string{"id", "name", "description", "sort", "create_time", "update_time"},
+		Load: loadRows(func(p *models.Position) syncRow {
+			return syncRow{ID: p.ID, UpdateTime: p.UpdateTime, Values: []interface{}{
+				p.ID, p.Name, p.Description, p.Sort, p.CreateTime, p.UpdateTime,
+			}}
+		}),
+	},
+	{
+		Name:    "users",
+		Model:   &models.User{},
+		Columns: []string{"id", "username", "password", "name", "email", "phone", "avatar", "role", "department", "department_id", "position", "position_id", "status", "create_time", "update_time"},
+		// 云端可能被他人改写，拉取的用户不能借此获得密码或角色；新用户禁用且无密码，需管理员启用并重置密码
+		PullDefaults: map[string]interface{}{"password": "", "role": RoleUser, "status": 0},
+		Load: loadRows(func(u *models.User) syncRow {
+			return syncRow{ID: u.ID, UpdateTime: u.UpdateTime, Values: []interface{}{
+				u.ID, u.Username, u.Password, u.Name, u.Email, u.Phone, u.Avatar, u.Role, u.Department, u.DepartmentID, u.Position, u.PositionID, u.Status, u.CreateTime, u.UpdateTime,
+			}}
+		}),
+	},
+	{
+		Name:    "projects",
+		Model:   &models.Project{},
+		Columns: []string{"id", "name", "company", "total_amount", "received_amount", "status", "type", "contract_number", "contract_date", "payment_method", "start_date", "end_date", "description", "user_id", "create_time", "update_time"},
+		Load: loadRows(func(p *models.Project) syncRow {
+			return syncRow{ID: p.ID, UpdateTime: p.UpdateTime, Values: []interface{}{
+				p.ID, p.Name, p.Company, p.TotalAmount, p.ReceivedAmount, p.Status, p.Type, p.ContractNumber, p.ContractDate, p.PaymentMethod, p.StartDate, p.EndDate, p.Description, p.UserID, p.CreateTime, p.UpdateTime,
+			}}
+		}),
+	},
+	{
+		Name:    "project_members",
+		Model:   &models.ProjectMember{},
+		Columns: []string{"id", "project_id", "user_id", "role", "create_time", "update_time"},
+		Load: loadRows(func(m *models.ProjectMember) syncRow {
+			return syncRow{ID: m.ID, UpdateTime: m.UpdateTime, Values: []interface{}{
+				m.ID, m.ProjectID, m.UserID, m.Role, m.CreateTime, m.UpdateTime,
+			}}
+		}),
+	},
+	{
+		Name:    "payments",
+		Model:   &models.Payment{},
+		Columns: []string{"id", "project_id", "stage", "amount", "percentage", "plan_date", "status", "actual_date", "method", "remark", "user_id", "create_time", "update_time"},
+		Load: loadRows(func(p *models.Payment) syncRow {
+			return syncRow{ID: p.ID, UpdateTime: p.UpdateTime, Values: []interface{}{
+				p.ID, p.ProjectID, p.Stage, p.Amount, p.Percentage, p.PlanDate, p.Status, p.ActualDate, p.Method, p.Remark, p.UserID, p.CreateTime, p.UpdateTime,
+			}}
+		}),
+	},
+	{
+		Name:    "dictionaries",
+		Model:   &models.Dictionary{},
+		Columns: []string{"id", "code", "name", "status", "remark", "create_time", "update_time"},
+		Load: loadRows(func(d *models.Dictionary) syncRow {
+			return syncRow{ID: d.ID, UpdateTime: d.UpdateTime, Values: []interface{}{
+				d.ID, d.Code, d.Name, d.Status, d.Remark, d.CreateTime, d.UpdateTime,
+			}}
+		}),
+	},
+	{
+		Name:    "dictionary_item",
+		Model:   &models.DictionaryItem{},
+		Columns: []string{"id", "dictionary_id", "label", "value", "sort", "status", "remark", "create_time", "update_time"},
+		Load: loadRows(func(item *models.DictionaryItem) syncRow {
+			return syncRow{ID: item.ID, UpdateTime: item.UpdateTime, Values: []interface{}{
+				item.ID, item.DictionaryID, item.Label, item.Value, item.Sort, item.Status, item.Remark, item.CreateTime, item.UpdateTime,
+			}}
+		}),
+	},
+	{
+		Name:    "notifications",
+		Model:   &models.Notification{},
+		Columns: []string{"id", "title", "content", "type", "sender_id", "is_global", "create_time", "update_time"},
+		Load: loadRows(func(n *models.Notification) syncRow {
+			return syncRow{ID: n.ID, UpdateTime: n.UpdateTime, Values: []interface{}{
+				n.ID, n.Title, n.Content, n.Type, n.SenderID, n.IsGlobal, n.CreateTime, n.UpdateTime,
+			}}
+		}),
+	},
+	{
+		Name:    "user_notifications",
+		Model:   &models.UserNotification{},
+		Columns: []string{"id", "user_id", "notification_id", "is_read", "read_time", "update_time"},
+		Load: loadRows(func(un *models.UserNotification) syncRow {
+			return syncRow{ID: un.ID, UpdateTime: un.UpdateTime, Values: []interface{}{
+				un.ID, un.UserID, un.NotificationID, un.IsRead, un.ReadTime, un.UpdateTime,
+			}}
+		}),
+	},
+}
+
+// findSyncTable 根据表名查找同步表定义
+func findSyncTable(name string) *syncTable {
+	for i := range syncTables {
+		if syncTables[i].Name == name {
+			return &syncTables[i]
+		}
+	}
+	return nil
+}
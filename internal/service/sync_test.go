@@ -0,0 +1,303 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/models"
+)
+
+// pushAsOtherDevice 模拟另一台设备将字典记录推送到云端
+func pushAsOtherDevice(t *testing.T, svc *SyncService, cfg SyncConfig, d models.Dictionary) {
+	t.Helper()
+	remote, err := svc.openRemote(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer remote.Close()
+
+	table := findSyncTable("dictionaries")
+	changes := newChangeSet()
+	changes.addRow(syncRow{ID: d.ID, UpdateTime: d.UpdateTime, Values: []interface{}{
+		d.ID, d.Code, d.Name, d.Status, d.Remark, d.CreateTime, d.UpdateTime,
+	}})
+	tx, err := remote.sqlDB.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+	if _, _, _, err := svc.pushChanges(context.Background(), tx, remote.dbType, table, changes, false, newSyncProgress(table.Name, 1, nil)); err != nil {
+		t.Fatalf("模拟推送失败: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// syncDictionaries 同步字典表并返回结果
+func syncDictionaries(t *testing.T, svc *SyncService, cfg SyncConfig, mode string) SyncResult {
+	t.Helper()
+	results, err := svc.SyncTables(context.Background(), cfg, SyncOptions{Tables: []string{"dictionaries"}, Mode: mode})
+	if err != nil {
+		t.Fatalf("同步失败: %v", err)
+	}
+	if len(results) != 1 || !results[0].Success {
+		t.Fatalf("同步失败: %+v", results)
+	}
+	return results[0]
+}
+
+func TestSyncPullsRemoteChangesFromSkewedClock(t *testing.T) {
+	svc := NewSyncService()
+	cfg := SyncConfig{DBType: "sqlite", Path: filepath.Join(t.TempDir(), "remote.db")}
+	db := database.GetDB()
+
+	local := models.Dictionary{Code: "sync_local", Name: "本机"}
+	if err := db.Create(&local).Error; err != nil {
+		t.Fatal(err)
+	}
+	syncDictionaries(t, svc, cfg, SyncModeMerge)
+
+	// 另一台设备的时钟慢一小时，推送的记录 update_time 早于本机已同步的记录
+	behind := time.Now().Add(-time.Hour)
+	pushAsOtherDevice(t, svc, cfg, models.Dictionary{ID: 9001, Code: "sync_remote", Name: "云端", Status: 1, CreateTime: behind, UpdateTime: behind})
+	if err := db.Model(&local).Update("name", "本机修改").Error; err != nil {
+		t.Fatal(err)
+	}
+
+	result := syncDictionaries(t, svc, cfg, SyncModeMerge)
+	if result.PulledCount != 1 || result.SyncedCount != 1 {
+		t.Errorf("双向同步: pulled=%d synced=%d，期望各 1 条", result.PulledCount, result.SyncedCount)
+	}
+	var pulled models.Dictionary
+	if err := db.First(&pulled, 9001).Error; err != nil || pulled.Name != "云端" {
+		t.Fatalf("未拉取到云端记录: %+v %v", pulled, err)
+	}
+
+	// 没有新变更时不再拉取，本机刚推送的记录也不会被拉取回来
+	result = syncDictionaries(t, svc, cfg, SyncModeMerge)
+	if result.PulledCount != 0 || result.SyncedCount != 0 {
+		t.Errorf("无变更时: pulled=%d synced=%d，期望均为 0", result.PulledCount, result.SyncedCount)
+	}
+
+	// 延迟推送的修改 (更新时间早于本机的全部记录) 同样能拉取
+	pushAsOtherDevice(t, svc, cfg, models.Dictionary{ID: 9001, Code: "sync_remote", Name: "云端修改", Status: 1, CreateTime: behind, UpdateTime: behind.Add(time.Minute)})
+	result = syncDictionaries(t, svc, cfg, SyncModePull)
+	if result.PulledCount != 1 {
+		t.Errorf("拉取: pulled=%d，期望 1", result.PulledCount)
+	}
+	if err := db.First(&pulled, 9001).Error; err != nil || pulled.Name != "云端修改" {
+		t.Errorf("未拉取到云端修改: %+v %v", pulled, err)
+	}
+}
+
+// remoteMemberCount 统计云端项目的成员数
+func remoteMemberCount(t *testing.T, svc *SyncService, cfg SyncConfig, projectID int64) int64 {
+	t.Helper()
+	remote, err := svc.openRemote(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer remote.Close()
+	var count int64
+	if err := remote.gormDB.Model(&models.ProjectMember{}).Where("project_id = ?", projectID).Count(&count).Error; err != nil {
+		t.Fatal(err)
+	}
+	return count
+}
+
+func TestSyncProjectMembers(t *testing.T) {
+	svc := NewSyncService()
+	cfg := SyncConfig{DBType: "sqlite", Path: filepath.Join(t.TempDir(), "remote.db")}
+	owner := createTestUser(t, "sync_member_owner", RoleUser)
+	member := createTestUser(t, "sync_member", RoleUser)
+	project := createTestProject(t, owner, "同步成员项目")
+	members := NewProjectMemberService()
+	if _, err := members.Add(project.ID, dto.ProjectMemberRequest{UserID: member.ID, Role: ProjectRoleEditor}, actorOf(owner)); err != nil {
+		t.Fatalf("添加成员失败: %v", err)
+	}
+
+	// 成员表排在用户与项目之后，随项目一同推送
+	tables := []string{"users", "projects", "project_members"}
+	if _, err := svc.SyncTables(context.Background(), cfg, SyncOptions{Tables: tables, Mode: SyncModePush}); err != nil {
+		t.Fatalf("同步失败: %v", err)
+	}
+	if n := remoteMemberCount(t, svc, cfg, project.ID); n != 1 {
+		t.Fatalf("云端成员数 = %d，期望 1", n)
+	}
+
+	// 移除成员后增量同步将删除推送到云端
+	if err := members.Remove(project.ID, member.ID, actorOf(owner)); err != nil {
+		t.Fatalf("移除成员失败: %v", err)
+	}
+	if _, err := svc.SyncTables(context.Background(), cfg, SyncOptions{Tables: tables, Mode: SyncModePush}); err != nil {
+		t.Fatalf("同步失败: %v", err)
+	}
+	if n := remoteMemberCount(t, svc, cfg, project.ID); n != 0 {
+		t.Errorf("云端成员数 = %d，期望 0", n)
+	}
+}
+
+func TestSyncDepartmentsAndPositions(t *testing.T) {
+	svc := NewSyncService()
+	cfg := SyncConfig{DBType: "sqlite", Path: filepath.Join(t.TempDir(), "remote.db")}
+	dept, err := NewDepartmentService().Create(dto.DepartmentRequest{Name: "同步部门"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	positions := NewPositionService()
+	assigned, err := positions.Create(dto.PositionRequest{Name: "同步职位"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	unused, err := positions.Create(dto.PositionRequest{Name: "同步空缺职位"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := createTestUser(t, "sync_org_user", RoleUser)
+	if err := database.GetDB().Model(user).Updates(map[string]interface{}{
+		"department_id": dept.ID, "department": dept.Name,
+		"position_id": assigned.ID, "position": assigned.Name,
+	}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// 部门与职位随用户一同推送，用户保留所属部门与职位的 ID
+	tables := []string{"departments", "positions", "users"}
+	if _, err := svc.SyncTables(context.Background(), cfg, SyncOptions{Tables: tables, Mode: SyncModePush}); err != nil {
+		t.Fatalf("同步失败: %v", err)
+	}
+	remote, err := svc.openRemote(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer remote.Close()
+	var remoteUser models.User
+	if err := remote.gormDB.First(&remoteUser, user.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if remoteUser.DepartmentID != dept.ID || remoteUser.PositionID != assigned.ID {
+		t.Errorf("云端用户部门/职位 = %d/%d，期望 %d/%d", remoteUser.DepartmentID, remoteUser.PositionID, dept.ID, assigned.ID)
+	}
+	var count int64
+	remote.gormDB.Model(&models.Department{}).Where("id = ?", dept.ID).Count(&count)
+	if count != 1 {
+		t.Errorf("云端部门数 = %d，期望 1", count)
+	}
+
+	// 删除职位后增量同步将删除推送到云端
+	if err := positions.Delete(unused.ID); err != nil {
+		t.Fatalf("删除职位失败: %v", err)
+	}
+	if _, err := svc.SyncTables(context.Background(), cfg, SyncOptions{Tables: tables, Mode: SyncModePush}); err != nil {
+		t.Fatalf("同步失败: %v", err)
+	}
+	remote.gormDB.Model(&models.Position{}).Where("id IN ?", []int64{assigned.ID, unused.ID}).Count(&count)
+	if count != 1 {
+		t.Errorf("云端职位数 = %d，期望 1", count)
+	}
+}
+
+// failCommit 使第 n 次提交同步事务失败 (回滚该事务)，测试结束时恢复
+func failCommit(t *testing.T, n int) {
+	t.Helper()
+	calls := 0
+	commitTx = func(tx *sql.Tx) error {
+		calls++
+		if calls == n {
+			tx.Rollback()
+			return errors.New("模拟提交失败")
+		}
+		return tx.Commit()
+	}
+	t.Cleanup(func() { commitTx = func(tx *sql.Tx) error { return tx.Commit() } })
+}
+
+// remoteDictionaryName 读取云端字典的名称
+func remoteDictionaryName(t *testing.T, svc *SyncService, cfg SyncConfig, id int64) string {
+	t.Helper()
+	remote, err := svc.openRemote(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer remote.Close()
+	var d models.Dictionary
+	if err := remote.gormDB.First(&d, id).Error; err != nil {
+		t.Fatal(err)
+	}
+	return d.Name
+}
+
+// syncDictionariesFailing 同步字典表，期望以 message 所述的错误失败
+func syncDictionariesFailing(t *testing.T, svc *SyncService, cfg SyncConfig, message string) {
+	t.Helper()
+	results, err := svc.SyncTables(context.Background(), cfg, SyncOptions{Tables: []string{"dictionaries"}, Mode: SyncModeMerge})
+	if err != nil {
+		t.Fatalf("同步失败: %v", err)
+	}
+	if len(results) != 1 || results[0].Success || results[0].ErrorMessage == "" {
+		t.Fatalf("期望同步失败: %+v", results)
+	}
+	if got := results[0].ErrorMessage; !strings.HasPrefix(got, message) {
+		t.Errorf("错误信息 = %q，期望以 %q 开头", got, message)
+	}
+}
+
+func TestSyncMergeRecoversFromFailedCommit(t *testing.T) {
+	svc := NewSyncService()
+	cfg := SyncConfig{DBType: "sqlite", Path: filepath.Join(t.TempDir(), "remote.db")}
+	db := database.GetDB()
+
+	local := models.Dictionary{Code: "commit_local", Name: "本机"}
+	if err := db.Create(&local).Error; err != nil {
+		t.Fatal(err)
+	}
+	syncDictionaries(t, svc, cfg, SyncModeMerge)
+
+	behind := time.Now().Add(-time.Hour)
+	pushAsOtherDevice(t, svc, cfg, models.Dictionary{ID: 9101, Code: "commit_remote", Name: "云端", Status: 1, CreateTime: behind, UpdateTime: behind})
+	if err := db.Model(&local).Update("name", "本机修改").Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// 本地提交失败: 两侧均不生效
+	failCommit(t, 1)
+	syncDictionariesFailing(t, svc, cfg, "提交本地事务失败")
+	if err := db.First(&models.Dictionary{}, 9101).Error; err == nil {
+		t.Error("本地提交失败后不应留下拉取的记录")
+	}
+	if name := remoteDictionaryName(t, svc, cfg, local.ID); name != "本机" {
+		t.Errorf("本地提交失败后云端记录 = %q，期望保持 本机", name)
+	}
+
+	// 本地已提交、云端提交失败: 本地保留拉取的记录，云端不变
+	failCommit(t, 2)
+	syncDictionariesFailing(t, svc, cfg, "提交云端事务失败")
+	if err := db.First(&models.Dictionary{}, 9101).Error; err != nil {
+		t.Errorf("本地应已拉取云端记录: %v", err)
+	}
+	if name := remoteDictionaryName(t, svc, cfg, local.ID); name != "本机" {
+		t.Errorf("云端提交失败后云端记录 = %q，期望保持 本机", name)
+	}
+
+	// 重试补齐两侧，重复拉取的同一版本不视为冲突
+	commitTx = func(tx *sql.Tx) error { return tx.Commit() }
+	result := syncDictionaries(t, svc, cfg, SyncModeMerge)
+	if len(result.Conflicts) != 0 || result.SyncedCount != 1 {
+		t.Errorf("重试: synced=%d conflicts=%+v，期望推送 1 条且无冲突", result.SyncedCount, result.Conflicts)
+	}
+	if name := remoteDictionaryName(t, svc, cfg, local.ID); name != "本机修改" {
+		t.Errorf("重试后云端记录 = %q，期望 本机修改", name)
+	}
+	result = syncDictionaries(t, svc, cfg, SyncModeMerge)
+	if result.PulledCount != 0 || result.SyncedCount != 0 {
+		t.Errorf("补齐后: pulled=%d synced=%d，期望均为 0", result.PulledCount, result.SyncedCount)
+	}
+}
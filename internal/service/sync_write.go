@@ -0,0 +1,447 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/database"
+	"gorm.io/gorm"
+)
+
+// syncBatchSize 每条 UPSERT / DELETE 语句处理的最大记录数
+// 受限于数据库单条语句的参数个数上限 (PostgreSQL/MySQL 为 65535)。
+const syncBatchSize = 200
+
+// commitTx 提交同步事务 (测试中替换以模拟提交失败)
+var commitTx = func(tx *sql.Tx) error {
+	return tx.Commit()
+}
+
+// sqlExecutor 可执行 SQL 的连接或事务
+type sqlExecutor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// SyncProgressFunc 同步进度回调
+// 每写入一批记录调用一次，done/total 为该表已写入/待写入的记录数 (含删除)。
+type SyncProgressFunc func(table string, done, total int64)
+
+// syncProgress 单张表的进度统计
+type syncProgress struct {
+	table string
+	done  int64
+	total int64
+	fn    SyncProgressFunc
+}
+
+// newSyncProgress 创建进度统计，fn 为 nil 时不上报
+func newSyncProgress(table string, total int64, fn SyncProgressFunc) *syncProgress {
+	p := &syncProgress{table: table, total: total, fn: fn}
+	p.report()
+	return p
+}
+
+// grow 增加待写入总数 (全量同步时多余数据的数量在写入过程中才能确定)
+func (p *syncProgress) grow(n int) {
+	p.total += int64(n)
+	p.report()
+}
+
+// advance 推进已写入数量
+func (p *syncProgress) advance(n int) {
+	p.done += int64(n)
+	p.report()
+}
+
+// report 上报当前进度
+func (p *syncProgress) report() {
+	if p.fn != nil {
+		p.fn(p.table, p.done, p.total)
+	}
+}
+
+// applyCounts 单张表写入的记录数统计
+type applyCounts struct {
+	synced       int64 // 推送到云端的记录数
+	deleted      int64 // 删除的云端记录数
+	pulled       int64 // 拉取到本地的记录数
+	localDeleted int64 // 删除的本地记录数
+	seq          int64 // 本次推送分配的云端变更序号 (未推送任何变更时为 0)
+}
+
+// applyChanges 将两侧变更分别写入云端与本地
+// 云端写入在一个云端事务中完成，本地写入在一个本地事务中完成，每一侧都不会留下写了一半的表。
+// 两侧写入都成功后先提交本地再提交云端: 写入或本地提交失败 (含 ctx 取消) 时两侧均不生效；
+// 本地已提交而云端提交失败时，本地保留拉取的变更、云端 (含变更日志序号) 不变。
+// 两个事务无法原子提交，返回错误时调用方不推进水位线，重试会重新拉取与推送同一批变更:
+// 写入按 ID 覆盖、删除可重复执行，两侧 update_time 相同的记录不视为冲突，因此重试即可补齐。
+func (s *SyncService) applyChanges(ctx context.Context, localDB *gorm.DB, remote *remoteConn, table *syncTable, local, remoteChanges *changeSet, mode string, incremental bool, progress *syncProgress) (applyCounts, error) {
+	var counts applyCounts
+	var err error
+
+	// 1. 云端事务: 推送本地变更
+	var remoteTx *sql.Tx
+	if mode != SyncModePull {
+		if remoteTx, err = remote.sqlDB.BeginTx(ctx, nil); err != nil {
+			return counts, fmt.Errorf("开启云端事务失败: %w", err)
+		}
+		defer remoteTx.Rollback()
+
+		fullPush := mode == SyncModePush && !incremental
+		if counts.synced, counts.deleted, counts.seq, err = s.pushChanges(ctx, remoteTx, remote.dbType, table, local, fullPush, progress); err != nil {
+			return applyCounts{}, fmt.Errorf("同步失败: %w", err)
+		}
+	}
+
+	// 2. 本地事务: 拉取云端变更
+	var localTx *sql.Tx
+	if mode != SyncModePush {
+		sqlDB, err := localDB.DB()
+		if err != nil {
+			return applyCounts{}, err
+		}
+		if localTx, err = sqlDB.BeginTx(ctx, nil); err != nil {
+			return applyCounts{}, fmt.Errorf("开启本地事务失败: %w", err)
+		}
+		defer localTx.Rollback()
+
+		fullPull := mode == SyncModePull && !incremental
+		if counts.pulled, counts.localDeleted, err = s.pullChanges(ctx, localTx, database.GetDBType(), table, remoteChanges, fullPull, progress); err != nil {
+			return applyCounts{}, fmt.Errorf("写入本地数据失败: %w", err)
+		}
+	}
+
+	// 3. 提交 (先本地后云端，本地提交失败时云端事务随之回滚)
+	if localTx != nil {
+		if err := commitTx(localTx); err != nil {
+			return applyCounts{}, fmt.Errorf("提交本地事务失败: %w", err)
+		}
+	}
+	if remoteTx != nil {
+		if err := commitTx(remoteTx); err != nil {
+			return applyCounts{}, fmt.Errorf("提交云端事务失败: %w", err)
+		}
+	}
+	return counts, nil
+}
+
+// pushChanges 将本地变更写入云端
+// 删除操作同时写入云端墓碑表，供其他设备拉取；重新写入的记录清除其云端墓碑。
+// 写入与删除的记录登记到云端变更日志，返回分配的变更序号。全量推送时删除云端多余数据。
+func (s *SyncService) pushChanges(ctx context.Context, tx sqlExecutor, dbType string, table *syncTable, local *changeSet, full bool, progress *syncProgress) (synced, deleted, seq int64, err error) {
+	// 1. 批量 UPSERT 到云端
+	rows := local.sortedRows()
+	if err := s.upsertRows(ctx, tx, dbType, table, rows, false, progress); err != nil {
+		return 0, 0, 0, err
+	}
+	if err := s.clearTombstones(ctx, tx, dbType, table.Name, rowIDs(rows)); err != nil {
+		return 0, 0, 0, err
+	}
+
+	// 2. 同步删除
+	now := time.Now()
+	deletes := make(map[int64]time.Time, len(local.deletes))
+	if full {
+		remoteIDs, err := s.queryIDs(ctx, tx, table.Name)
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		for _, id := range remoteIDs {
+			if _, ok := local.rows[id]; !ok {
+				deletes[id] = now
+			}
+		}
+		progress.grow(len(deletes))
+	} else {
+		for id, t := range local.deletes {
+			deletes[id] = t
+		}
+	}
+
+	ids := make([]int64, 0, len(deletes))
+	for id := range deletes {
+		ids = append(ids, id)
+	}
+	if err := s.deleteByIDs(ctx, tx, dbType, table.Name, ids, progress); err != nil {
+		return 0, 0, 0, err
+	}
+	if err := s.saveTombstones(ctx, tx, dbType, table.Name, deletes); err != nil {
+		return 0, 0, 0, err
+	}
+
+	// 3. 登记变更日志
+	if seq, err = s.recordChanges(ctx, tx, dbType, table.Name, append(rowIDs(rows), ids...)); err != nil {
+		return 0, 0, 0, err
+	}
+
+	return int64(len(rows)), int64(len(ids)), seq, nil
+}
+
+// pullChanges 将云端变更写入本地
+// 使用原生 SQL 写入，不触发 update_time 自动更新与删除墓碑回调，
+// 拉取的数据保持云端的更新时间，也不会在下次推送时被当作本地删除。
+// table.PullDefaults 中的列不采用云端值。全量拉取时删除本地多余数据。
+func (s *SyncService) pullChanges(ctx context.Context, tx sqlExecutor, dbType string, table *syncTable, remote *changeSet, full bool, progress *syncProgress) (pulled, deleted int64, err error) {
+	deleteIDs := remote.deleteIDs()
+	if full {
+		localIDs, err := s.queryIDs(ctx, tx, table.Name)
+		if err != nil {
+			return 0, 0, err
+		}
+		deleteIDs = deleteIDs[:0]
+		for _, id := range localIDs {
+			if _, ok := remote.rows[id]; !ok {
+				deleteIDs = append(deleteIDs, id)
+			}
+		}
+		progress.grow(len(deleteIDs))
+	}
+	if err := s.deleteByIDs(ctx, tx, dbType, table.Name, deleteIDs, progress); err != nil {
+		return 0, 0, err
+	}
+
+	rows := remote.sortedRows()
+	if err := s.upsertRows(ctx, tx, dbType, table, rows, true, progress); err != nil {
+		return 0, 0, err
+	}
+
+	// 云端重新写入的记录不再视为本地已删除
+	if err := s.clearTombstones(ctx, tx, dbType, table.Name, rowIDs(rows)); err != nil {
+		return 0, 0, err
+	}
+
+	return int64(len(rows)), int64(len(deleteIDs)), nil
+}
+
+// upsertRows 分批执行多行 UPSERT
+// pull 为 true 时 (写入本地)，table.PullDefaults 中的列以默认值插入，冲突时保留原值。
+func (s *SyncService) upsertRows(ctx context.Context, tx sqlExecutor, dbType string, table *syncTable, rows []syncRow, pull bool, progress *syncProgress) error {
+	var keep []string
+	if pull {
+		for _, col := range table.Columns {
+			if _, ok := table.PullDefaults[col]; ok {
+				keep = append(keep, col)
+			}
+		}
+	}
+
+	for start := 0; start < len(rows); start += syncBatchSize {
+		end := min(start+syncBatchSize, len(rows))
+		batch := rows[start:end]
+
+		args := make([]interface{}, 0, len(batch)*len(table.Columns))
+		for _, row := range batch {
+			for i, value := range row.Values {
+				if def, ok := table.PullDefaults[table.Columns[i]]; ok && pull {
+					value = def
+				}
+				args = append(args, value)
+			}
+		}
+		query := s.buildUpsertQuery(table.Name, table.Columns, []string{"id"}, keep, len(batch), dbType)
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+		progress.advance(len(batch))
+	}
+	return nil
+}
+
+// saveTombstones 写入删除墓碑，同一记录已有墓碑时刷新删除时间
+func (s *SyncService) saveTombstones(ctx context.Context, tx sqlExecutor, dbType string, table string, deletes map[int64]time.Time) error {
+	columns := []string{"table_name", "record_id", "delete_time"}
+	keys := []string{"table_name", "record_id"}
+
+	ids := make([]int64, 0, len(deletes))
+	for id := range deletes {
+		ids = append(ids, id)
+	}
+	for start := 0; start < len(ids); start += syncBatchSize {
+		end := min(start+syncBatchSize, len(ids))
+
+		args := make([]interface{}, 0, (end-start)*len(columns))
+		for _, id := range ids[start:end] {
+			args = append(args, table, id, deletes[id])
+		}
+		query := s.buildUpsertQuery("sync_tombstones", columns, keys, nil, end-start, dbType)
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordChanges 为推送的记录分配云端变更序号并写入变更日志
+// 同一次推送的记录共用一个序号。计数器行在云端事务中递增，行锁持有到提交，
+// 多个设备同时推送时依次分配序号，读取方不会先看到较大的序号、之后才出现较小的序号。
+func (s *SyncService) recordChanges(ctx context.Context, tx sqlExecutor, dbType string, table string, ids []int64) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE sync_sequences SET seq = seq + 1 WHERE id = 1"); err != nil {
+		return 0, err
+	}
+	rows, err := tx.QueryContext(ctx, "SELECT seq FROM sync_sequences WHERE id = 1")
+	if err != nil {
+		return 0, err
+	}
+	var seq int64
+	found := rows.Next()
+	if found {
+		err = rows.Scan(&seq)
+	}
+	rows.Close()
+	if err != nil {
+		return 0, err
+	}
+	if !found {
+		return 0, errors.New("云端变更序号未初始化")
+	}
+
+	columns := []string{"table_name", "record_id", "seq"}
+	keys := []string{"table_name", "record_id"}
+	for start := 0; start < len(ids); start += syncBatchSize {
+		end := min(start+syncBatchSize, len(ids))
+
+		args := make([]interface{}, 0, (end-start)*len(columns))
+		for _, id := range ids[start:end] {
+			args = append(args, table, id, seq)
+		}
+		query := s.buildUpsertQuery("sync_changes", columns, keys, nil, end-start, dbType)
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return 0, err
+		}
+	}
+	return seq, nil
+}
+
+// deleteByIDs 按 ID 分批删除记录
+func (s *SyncService) deleteByIDs(ctx context.Context, tx sqlExecutor, dbType string, table string, ids []int64, progress *syncProgress) error {
+	for start := 0; start < len(ids); start += syncBatchSize {
+		end := min(start+syncBatchSize, len(ids))
+
+		query := fmt.Sprintf("DELETE FROM %s WHERE id IN (%s)", table, s.buildPlaceholders(1, end-start, dbType))
+		if _, err := tx.ExecContext(ctx, query, int64Args(ids[start:end])...); err != nil {
+			return err
+		}
+		progress.advance(end - start)
+	}
+	return nil
+}
+
+// clearTombstones 分批删除指定记录的墓碑
+func (s *SyncService) clearTombstones(ctx context.Context, tx sqlExecutor, dbType string, table string, ids []int64) error {
+	for start := 0; start < len(ids); start += syncBatchSize {
+		end := min(start+syncBatchSize, len(ids))
+
+		query := fmt.Sprintf("DELETE FROM sync_tombstones WHERE table_name = %s AND record_id IN (%s)",
+			s.placeholder(1, dbType), s.buildPlaceholders(2, end-start, dbType))
+		args := append([]interface{}{table}, int64Args(ids[start:end])...)
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// queryIDs 查询表中全部记录 ID
+func (s *SyncService) queryIDs(ctx context.Context, tx sqlExecutor, table string) ([]int64, error) {
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf("SELECT id FROM %s", table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// rowIDs 提取记录 ID 列表
+func rowIDs(rows []syncRow) []int64 {
+	ids := make([]int64, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+	return ids
+}
+
+// int64Args 将 ID 列表转换为 SQL 参数
+func int64Args(ids []int64) []interface{} {
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	return args
+}
+
+// buildPlaceholders 构建从第 start 个参数开始的 n 个占位符
+// (PostgreSQL: $1, $2...; MySQL/SQLite: ?, ?...)
+func (s *SyncService) buildPlaceholders(start, n int, dbType string) string {
+	placeholders := make([]string, n)
+	for i := range placeholders {
+		placeholders[i] = s.placeholder(start+i, dbType)
+	}
+	return strings.Join(placeholders, ", ")
+}
+
+// placeholder 第 i 个参数的占位符 (从 1 开始)
+func (s *SyncService) placeholder(i int, dbType string) string {
+	if dbType == "postgres" {
+		return fmt.Sprintf("$%d", i)
+	}
+	return "?"
+}
+
+// buildUpsertQuery 构建多行 UPSERT 语句 (支持 PostgreSQL、MySQL 和 SQLite)
+// keys 为冲突判定列 (主键或唯一索引)，冲突时更新除 keys 与 keep 外的其余列。
+func (s *SyncService) buildUpsertQuery(table string, columns []string, keys []string, keep []string, rows int, dbType string) string {
+	skip := make(map[string]bool, len(keys)+len(keep))
+	for _, k := range keys {
+		skip[k] = true
+	}
+	for _, k := range keep {
+		skip[k] = true
+	}
+
+	// 构建更新子句
+	updates := make([]string, 0, len(columns))
+	for _, col := range columns {
+		if skip[col] {
+			continue
+		}
+		switch dbType {
+		case "postgres":
+			updates = append(updates, fmt.Sprintf("%s = EXCLUDED.%s", col, col))
+		case "sqlite":
+			updates = append(updates, fmt.Sprintf("%s = excluded.%s", col, col))
+		default:
+			updates = append(updates, fmt.Sprintf("%s = VALUES(%s)", col, col))
+		}
+	}
+
+	// 构建多行 VALUES
+	values := make([]string, rows)
+	for i := range values {
+		values[i] = "(" + s.buildPlaceholders(i*len(columns)+1, len(columns), dbType) + ")"
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", table, strings.Join(columns, ", "), strings.Join(values, ", "))
+	if dbType == "postgres" || dbType == "sqlite" {
+		return fmt.Sprintf("%s ON CONFLICT (%s) DO UPDATE SET %s", query, strings.Join(keys, ", "), strings.Join(updates, ", "))
+	}
+	// MySQL
+	return fmt.Sprintf("%s ON DUPLICATE KEY UPDATE %s", query, strings.Join(updates, ", "))
+}
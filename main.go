@@ -3,21 +3,25 @@ package main
 import (
 	"embed"
 	_ "embed"
+	"flag"
+	"fmt"
 	"io/fs"
 	"log"
 	"log/slog"
 	"net/http"
 	"os"
+	"path/filepath"
 	"runtime/debug"
 	"strings"
 	"time"
 
 	"github.com/FruitsAI/Orange/internal/config"
 	"github.com/FruitsAI/Orange/internal/database"
-	"github.com/FruitsAI/Orange/internal/models"
 	"github.com/FruitsAI/Orange/internal/pkg/jwt"
 	"github.com/FruitsAI/Orange/internal/pkg/logger"
+	"github.com/FruitsAI/Orange/internal/pkg/secret"
 	"github.com/FruitsAI/Orange/internal/router"
+	"github.com/FruitsAI/Orange/internal/service"
 	"github.com/wailsapp/wails/v3/pkg/application"
 )
 
@@ -61,6 +65,7 @@ func createAssetHandler() http.Handler {
 // 它负责初始化应用配置、日志、数据库，创建 Wails 应用实例及窗口，并启动主事件循环。
 func main() {
 	// 1. 加载配置信息
+	flag.Parse()
 	config.Load()
 
 	// 2. 初始化日志系统
@@ -78,24 +83,37 @@ func main() {
 		}
 	}()
 
-	// 4. 初始化 JWT 密钥配置
-	jwt.SecretKey = []byte(config.AppConfig.JWTSecret)
-	jwt.TokenExpiry = time.Duration(config.AppConfig.TokenExpiry) * time.Hour
+	// 4. 初始化 JWT 签名密钥 (首次启动时自动生成本安装专用的密钥)
+	jwt.TokenExpiry = time.Duration(config.AppConfig.AccessTokenExpiry) * time.Minute
+	jwt.KeyDir = filepath.Join(config.AppConfig.DataDir, "jwt-keys")
+	jwt.Algorithm = config.AppConfig.JWTAlgorithm
+	jwt.RotationInterval = time.Duration(config.AppConfig.JWTKeyRotationDays) * 24 * time.Hour
+	if err := jwt.LoadKeys(); err != nil {
+		slog.Error("Failed to load JWT signing keys", "error", err)
+		os.Exit(1)
+	}
+
+	// 本机密钥 (用于加密保存的同步连接密码等)
+	secret.KeyFile = filepath.Join(config.AppConfig.DataDir, "secret.key")
 
 	// 5. 初始化数据库连接
 	slog.Info("Initializing database...")
 	db := database.GetDB()
 
-	// 执行数据库自动迁移 (同步表结构)
-	db.AutoMigrate(
-		&models.User{},
-		&models.Project{},
-		&models.Payment{},
-		&models.Dictionary{},
-		&models.DictionaryItem{},
-		&models.Notification{},
-		&models.UserNotification{},
-	)
+	// 命令行迁移操作 (-migrate-status / -migrate-to / -migrate-rollback)，执行后直接退出
+	if handled, err := runMigrateCommand(db); handled {
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// 执行未执行的数据库迁移 (见 internal/database/migrations.go)
+	if err := database.Migrate(db); err != nil {
+		slog.Error("Failed to migrate database", "error", err)
+		os.Exit(1)
+	}
 
 	// 播种初始化数据 (如默认用户、字典等)
 	if err := database.Seed(db); err != nil {
@@ -104,6 +122,14 @@ func main() {
 
 	defer database.Close()
 
+	// 启动自动同步调度 (未配置 SYNC_CRON / SYNC_INTERVAL 时不启用)
+	if scheduler, err := service.NewSyncScheduler(); err != nil {
+		slog.Error("Failed to create sync scheduler", "error", err)
+	} else if scheduler != nil {
+		scheduler.Start()
+		defer scheduler.Stop()
+	}
+
 	// 6. 创建组合资源处理器 (API + 前端静态资源)
 	assetHandler := createAssetHandler()
 
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/FruitsAI/Orange/internal/database"
+	"gorm.io/gorm"
+)
+
+// 数据库迁移命令行参数
+// 指定任一参数时只执行迁移操作并退出，不启动应用窗口。
+var (
+	migrateStatus   = flag.Bool("migrate-status", false, "显示数据库迁移状态后退出")
+	migrateTo       = flag.Int("migrate-to", -1, "升级或回滚到指定迁移版本后退出 (0 表示回滚全部)")
+	migrateRollback = flag.Int("migrate-rollback", 0, "回滚最近 N 个迁移后退出")
+)
+
+// runMigrateCommand 处理迁移命令行参数
+// 未指定迁移参数时返回 false，由调用方继续正常启动。
+func runMigrateCommand(db *gorm.DB) (bool, error) {
+	switch {
+	case *migrateTo >= 0:
+		if err := database.MigrateTo(db, *migrateTo); err != nil {
+			return true, err
+		}
+	case *migrateRollback > 0:
+		if err := database.Rollback(db, *migrateRollback); err != nil {
+			return true, err
+		}
+	case *migrateStatus:
+	default:
+		return false, nil
+	}
+	return true, printMigrationStatus(db)
+}
+
+// printMigrationStatus 输出迁移状态表
+func printMigrationStatus(db *gorm.DB) error {
+	list, err := database.Status(db)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+	for _, m := range list {
+		status, appliedAt := "pending", "-"
+		if m.Applied {
+			status = "applied"
+			appliedAt = m.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		if m.Unknown {
+			status = "applied (unknown)"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", m.Version, m.Name, status, appliedAt)
+	}
+	return w.Flush()
+}